@@ -0,0 +1,142 @@
+package grpclogrus
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Suffixes of the fields SplitAddresses adds for an address: addr is
+// followed by addr.host, addr.port and addr.scheme.
+const (
+	HostSuffix   = ".host"
+	PortSuffix   = ".port"
+	SchemeSuffix = ".scheme"
+)
+
+// DefaultAddressKeys are the fields SplitAddresses splits when given none:
+// the addresses of grpc's messages, and the target and peer of the
+// interceptors.
+var DefaultAddressKeys = []string{"addr", TargetKey, PeerKey}
+
+// SplitAddress splits an address or dial target into its scheme, host and
+// port, those it has: "dns:///example.com:443", "unix:///tmp/s.sock",
+// "[::1]:50051", "10.0.0.1:443"...
+func SplitAddress(addr string) (scheme, host, port string) {
+	scheme, addr = splitScheme(addr)
+	if strings.HasPrefix(scheme, "unix") {
+		// A path, or an abstract socket's name.
+		return scheme, addr, ""
+	}
+	if h, p, err := net.SplitHostPort(addr); err == nil {
+		return scheme, h, p
+	}
+	return scheme, strings.Trim(addr, "[]"), ""
+}
+
+// splitScheme splits the scheme off a target, skipping its authority,
+// e.g. "dns://8.8.8.8/example.com:443". Unix targets may have no slashes,
+// as in "unix:path"; others must, not to take "localhost:50051" for a
+// target of scheme localhost.
+func splitScheme(target string) (scheme, rest string) {
+	i := strings.Index(target, ":")
+	if i <= 0 || !isScheme(target[:i]) {
+		return "", target
+	}
+	scheme, rest = target[:i], target[i+1:]
+	if !strings.HasPrefix(rest, "//") {
+		if strings.HasPrefix(scheme, "unix") {
+			return scheme, rest
+		}
+		return "", target
+	}
+	rest = rest[2:]
+	j := strings.Index(rest, "/")
+	if j < 0 {
+		return scheme, rest
+	}
+	if strings.HasPrefix(scheme, "unix") {
+		// Keep the path absolute.
+		return scheme, rest[j:]
+	}
+	return scheme, rest[j+1:]
+}
+
+// isScheme reports whether s is made of a scheme's characters: letters,
+// '+' and '-'.
+func isScheme(s string) bool {
+	for _, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '+', c == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// SplitAddresses makes a Backend adding the host, port and scheme of the
+// address fields of the keys given, DefaultAddressKeys when none, before
+// emitting entries to next, for logs to be filtered by host. Fields are
+// named after the key, with HostSuffix, PortSuffix and SchemeSuffix, for
+// those the address has.
+func SplitAddresses(next Backend, keys ...string) Backend {
+	if len(keys) == 0 {
+		keys = DefaultAddressKeys
+	}
+	return &splitAddresses{next: next, keys: keys}
+}
+
+type splitAddresses struct {
+	next Backend
+	keys []string
+}
+
+func (s *splitAddresses) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	s.next.Emit(level, splitAddressFields(fields, s.keys), message)
+}
+
+// splitAddressFields returns fields with the parts of the addresses of
+// keys, copied when there are some.
+func splitAddressFields(fields logrus.Fields, keys []string) logrus.Fields {
+	var split logrus.Fields
+	for _, k := range keys {
+		v, ok := fields[k]
+		if !ok {
+			continue
+		}
+		addr, ok := v.(string)
+		if !ok {
+			addr = fmt.Sprint(v)
+		}
+		scheme, host, port := SplitAddress(addr)
+		if host == "" {
+			continue
+		}
+		if split == nil {
+			split = make(logrus.Fields, len(fields)+3)
+			for k, v := range fields {
+				split[k] = v
+			}
+		}
+		split[k+HostSuffix] = host
+		if port != "" {
+			split[k+PortSuffix] = port
+		}
+		if scheme != "" {
+			split[k+SchemeSuffix] = scheme
+		}
+	}
+	if split == nil {
+		return fields
+	}
+	return split
+}
+
+// SplitAddresses splits address fields as SplitAddresses does, in the
+// RewritePhase.
+func (p *PipelineBuilder) SplitAddresses(keys ...string) *PipelineBuilder {
+	return p.Use(RewritePhase, func(next Backend) Backend { return SplitAddresses(next, keys...) })
+}
@@ -0,0 +1,94 @@
+package grpclogrus
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+var _ Backend = (*Alarm)(nil)
+
+// Fields of the entry an Alarm emits when it goes off.
+const (
+	ErrorsKey = "errors"
+	WindowKey = "window"
+)
+
+// DefaultAlarmWindow is the window of an Alarm.
+const DefaultAlarmWindow = time.Minute
+
+// Alarm is a Backend passing entries to another, going off when the
+// error and fatal entries within its sliding Window reach its threshold.
+// It goes off once per crossing, rearming when the count falls back
+// below the threshold.
+type Alarm struct {
+	next      Backend
+	threshold int
+
+	// Window is the sliding window errors are counted within. Set before
+	// use.
+	Window time.Duration
+	// OnAlarm is called when the alarm goes off, with the number of
+	// errors in the window, e.g. to open a circuit breaker or page. When
+	// nil, an error entry is emitted to the next backend instead. Set
+	// before use.
+	OnAlarm func(errors int, window time.Duration)
+
+	mu     sync.Mutex
+	errors []time.Time
+	fired  bool
+}
+
+// NewAlarm makes an Alarm passing entries to next, going off at
+// threshold errors within DefaultAlarmWindow.
+func NewAlarm(next Backend, threshold int) *Alarm {
+	return &Alarm{next: next, threshold: threshold, Window: DefaultAlarmWindow}
+}
+
+// Emit passes the entry to the next backend, counting it if it is an
+// error.
+func (a *Alarm) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	a.next.Emit(level, fields, message)
+	if level <= logrus.ErrorLevel {
+		if n, fire := a.count(time.Now()); fire {
+			a.fire(n)
+		}
+	}
+}
+
+// count adds an error at now, reporting the errors in the window and
+// whether the alarm goes off.
+func (a *Alarm) count(now time.Time) (int, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	cutoff := now.Add(-a.Window)
+	i := 0
+	for i < len(a.errors) && !a.errors[i].After(cutoff) {
+		i++
+	}
+	a.errors = append(a.errors[i:], now)
+	n := len(a.errors)
+	if n < a.threshold {
+		a.fired = false
+		return n, false
+	}
+	if a.fired {
+		return n, false
+	}
+	a.fired = true
+	return n, true
+}
+
+func (a *Alarm) fire(n int) {
+	if a.OnAlarm != nil {
+		a.OnAlarm(n, a.Window)
+		return
+	}
+	a.next.Emit(logrus.ErrorLevel, logrus.Fields{
+		parser.EventIDKey: "grpclogrus.error_rate_alarm",
+		ErrorsKey:         n,
+		WindowKey:         a.Window.String(),
+	}, "error rate alarm")
+}
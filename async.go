@@ -0,0 +1,188 @@
+package grpclogrus
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+var _ Backend = (*Async)(nil)
+
+// DroppedKey is the field of the summary entries of an Async holding the
+// number of entries dropped within the interval. The counts by severity
+// are in DroppedKey + "_" + the level, e.g. dropped_error, for the levels
+// that had drops.
+const DroppedKey = "dropped"
+
+// Defaults of an Async.
+const (
+	DefaultAsyncSize     = 1024
+	DefaultAsyncInterval = time.Minute
+)
+
+// Async is a Backend passing entries to another from a goroutine, so that
+// a slow backend never blocks grpc. Entries queue in a ring buffer: when
+// it is full, the oldest entry is dropped for the newest, and the drops
+// are counted by severity, summarized in a dropped_entries entry every
+// Interval that had some. Fatal entries are emitted synchronously, after
+// the queue, for them not to be lost when the process exits.
+type Async struct {
+	next Backend
+
+	// Interval is the time between summaries, DefaultAsyncInterval when
+	// not positive. Set before use.
+	Interval time.Duration
+
+	once sync.Once
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+
+	mu      sync.Mutex
+	ring    []asyncEntry
+	head, n int
+	dropped [logrus.TraceLevel + 1]int
+	closed  bool
+}
+
+type asyncEntry struct {
+	level   logrus.Level
+	fields  logrus.Fields
+	message string
+}
+
+// NewAsync makes an Async passing entries to next, queueing up to size of
+// them, DefaultAsyncSize when size isn't positive.
+func NewAsync(next Backend, size int) *Async {
+	if size <= 0 {
+		size = DefaultAsyncSize
+	}
+	return &Async{next: next, Interval: DefaultAsyncInterval, ring: make([]asyncEntry, size)}
+}
+
+func (a *Async) start() {
+	if a.Interval <= 0 {
+		a.Interval = DefaultAsyncInterval
+	}
+	a.wake = make(chan struct{}, 1)
+	a.stop = make(chan struct{})
+	a.done = make(chan struct{})
+	go a.run()
+}
+
+// Emit queues the entry, dropping the oldest one queued when the queue is
+// full.
+func (a *Async) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	a.once.Do(a.start)
+	if level == logrus.FatalLevel {
+		a.Close()
+		a.next.Emit(level, fields, message)
+		return
+	}
+
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		a.next.Emit(level, fields, message)
+		return
+	}
+	// Fields are reused once Emit returns: keep a copy.
+	copied := make(logrus.Fields, len(fields))
+	for k, v := range fields {
+		copied[k] = v
+	}
+	e := asyncEntry{level: level, fields: copied, message: message}
+	if a.n == len(a.ring) {
+		a.dropped[a.ring[a.head].level]++
+		CountDropped(1)
+		a.ring[a.head] = e
+		a.head = (a.head + 1) % len(a.ring)
+	} else {
+		a.ring[(a.head+a.n)%len(a.ring)] = e
+		a.n++
+	}
+	a.mu.Unlock()
+
+	select {
+	case a.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Close emits the entries still queued and the last summary. Entries
+// emitted afterwards are passed to the next backend synchronously.
+func (a *Async) Close() error {
+	a.once.Do(a.start)
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.stop)
+	}
+	a.mu.Unlock()
+	<-a.done
+	return nil
+}
+
+func (a *Async) run() {
+	defer close(a.done)
+	t := time.NewTicker(a.Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-a.wake:
+			a.drain()
+		case <-t.C:
+			a.summarize()
+		case <-a.stop:
+			a.drain()
+			a.summarize()
+			return
+		}
+	}
+}
+
+// drain emits the queued entries, one at a time, for the entries dropped
+// meanwhile to be the oldest still queued.
+func (a *Async) drain() {
+	for {
+		a.mu.Lock()
+		if a.n == 0 {
+			a.mu.Unlock()
+			return
+		}
+		e := a.ring[a.head]
+		a.ring[a.head] = asyncEntry{}
+		a.head = (a.head + 1) % len(a.ring)
+		a.n--
+		a.mu.Unlock()
+		a.next.Emit(e.level, e.fields, e.message)
+	}
+}
+
+// summarize emits the counts of the entries dropped since the last
+// summary, if any, and resets them.
+func (a *Async) summarize() {
+	a.mu.Lock()
+	dropped := a.dropped
+	a.dropped = [logrus.TraceLevel + 1]int{}
+	a.mu.Unlock()
+
+	fields := logrus.Fields{
+		parser.EventIDKey: "grpclogrus.dropped_entries",
+		IntervalKey:       a.Interval.String(),
+	}
+	var total int
+	for level, n := range dropped {
+		if n > 0 {
+			fields[DroppedKey+"_"+logrus.Level(level).String()] = n
+			total += n
+		}
+	}
+	if total == 0 {
+		return
+	}
+	fields[DroppedKey] = total
+	a.next.Emit(logrus.WarnLevel, fields, "dropped entries")
+}
@@ -0,0 +1,25 @@
+package grpclogrus_test
+
+import (
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/logtest"
+)
+
+func TestAsyncZeroInterval(t *testing.T) {
+	var captured logtest.Captured
+	a := grpclogrus.NewAsync(&captured, 0)
+	a.Interval = 0
+	a.Emit(logrus.InfoLevel, logrus.Fields{"k": "v"}, "queued")
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if a.Interval != grpclogrus.DefaultAsyncInterval {
+		t.Errorf("want interval %v, got %v", grpclogrus.DefaultAsyncInterval, a.Interval)
+	}
+	if len(captured.Entries()) != 1 {
+		t.Errorf("want the queued entry emitted, got %v", captured.Entries())
+	}
+}
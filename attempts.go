@@ -0,0 +1,149 @@
+package grpclogrus
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// Fields of the entries of the attempts of calls, logged by the
+// AttemptHandler. The backoff is the time from the end of the previous
+// attempt to the start of this one, and is absent for the first attempt
+// and for hedged attempts, started while others are still running.
+const (
+	AttemptKey     = "attempt"
+	BackoffKey     = "backoff"
+	TransparentKey = "transparent_retry"
+)
+
+// AttemptHandler logs an entry of l for every attempt grpc makes of the
+// outbound calls of a connection, with its number, backoff, code and
+// duration: at debug level when it succeeds, and at warning when it
+// fails, as a failure is either retried or reported by the finish entry
+// of the call. The UnaryClientInterceptor numbers the attempts of its
+// calls, and adds their total to the finish entry, so both must be
+// installed on the connection:
+//
+//	grpc.Dial(target,
+//		grpc.WithUnaryInterceptor(grpclogrus.UnaryClientInterceptor(l)),
+//		grpc.WithStatsHandler(grpclogrus.AttemptHandler(l)),
+//	)
+//
+// The calls of methods not logged by the options aren't either.
+func AttemptHandler(l *logrus.Entry, opts ...Option) stats.Handler {
+	if l == nil {
+		l = logrus.WithFields(logrus.Fields{"source": "grpc"})
+	}
+	return &attemptHandler{l: l, o: newOptions(opts)}
+}
+
+type attemptHandler struct {
+	l *logrus.Entry
+	o options
+}
+
+type attemptsContextKey struct{}
+type attemptContextKey struct{}
+
+// attempts counts the attempts of a call, which hedging runs
+// concurrently.
+type attempts struct {
+	mu      sync.Mutex
+	n       int
+	running int
+	lastEnd time.Time
+}
+
+// callAttempt is an attempt of a call.
+type callAttempt struct {
+	calls   *attempts
+	n       int
+	method  string
+	backoff time.Duration
+	fields  logrus.Fields
+}
+
+// withAttempts returns a context counting the attempts of a call.
+func withAttempts(ctx context.Context) (context.Context, *attempts) {
+	a := &attempts{}
+	return context.WithValue(ctx, attemptsContextKey{}, a), a
+}
+
+// total is the number of attempts made of a call, 0 if unknown.
+func (a *attempts) total() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.n
+}
+
+func (h *attemptHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	calls, ok := ctx.Value(attemptsContextKey{}).(*attempts)
+	if !ok {
+		return ctx
+	}
+	if log, _ := h.o.method(info.FullMethodName); !log {
+		return ctx
+	}
+	calls.mu.Lock()
+	calls.n++
+	a := &callAttempt{calls: calls, n: calls.n, method: info.FullMethodName}
+	if calls.running == 0 && !calls.lastEnd.IsZero() {
+		a.backoff = time.Since(calls.lastEnd)
+	}
+	calls.running++
+	calls.mu.Unlock()
+	return context.WithValue(ctx, attemptContextKey{}, a)
+}
+
+func (h *attemptHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	a, ok := ctx.Value(attemptContextKey{}).(*callAttempt)
+	if !ok || !s.IsClient() {
+		return
+	}
+	switch s := s.(type) {
+	case *stats.Begin:
+		fields := logrus.Fields{AttemptKey: a.n}
+		if a.backoff > 0 {
+			fields[BackoffKey] = a.backoff.String()
+		}
+		if s.IsTransparentRetryAttempt {
+			fields[TransparentKey] = true
+		}
+		a.fields = fields
+	case *stats.End:
+		a.calls.mu.Lock()
+		a.calls.running--
+		a.calls.lastEnd = s.EndTime
+		a.calls.mu.Unlock()
+
+		code := status.Code(s.Error)
+		fields := methodFields(a.method)
+		for k, v := range a.fields {
+			fields[k] = v
+		}
+		fields[CodeKey] = code.String()
+		fields[DurationKey] = s.EndTime.Sub(s.BeginTime).String()
+		if s.Error != nil {
+			fields[ErrKey] = s.Error
+		}
+		fields = h.o.fields(fields)
+		fields[parser.EventIDKey] = "grpc.client_attempt_finished"
+		level := logrus.DebugLevel
+		if code != codes.OK {
+			level = logrus.WarnLevel
+		}
+		h.o.withContext(ctx, h.l.WithFields(fields)).Log(level, "client attempt finished")
+	}
+}
+
+func (h *attemptHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (h *attemptHandler) HandleConn(context.Context, stats.ConnStats) {}
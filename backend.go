@@ -0,0 +1,201 @@
+package grpclogrus
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+
+	"google.golang.org/grpc/grpclog"
+)
+
+// Backend emits entries structured from grpclog calls. Backends must not
+// modify fields, which are shared by every backend of a Tee, nor keep
+// them once Emit returns, as they are reused for later entries: backends
+// emitting asynchronously copy what they need.
+type Backend interface {
+	Emit(level logrus.Level, fields logrus.Fields, message string)
+}
+
+// FatalTimeout bounds how long a Tee waits for its backends to persist a
+// Fatal entry, and flush what they queued, before exiting. Set before
+// grpc logs.
+var FatalTimeout = 5 * time.Second
+
+// Tee makes a grpclog.Logger that parses each message once and emits the
+// result to every backend, in order. Fatal entries are emitted to every
+// backend at once, the process exiting once they all returned, or after
+// FatalTimeout.
+//
+// The logger is a grpclog.LoggerV2 too, which SetLogger installs as one:
+// the severity of grpc's calls then raises the level of the messages no
+// rule matched, and its V reports the verbosity of the first backend
+// having one, such as Levels, or 0.
+func Tee(backends ...Backend) grpclog.Logger {
+	return &tee{backends: backends}
+}
+
+// TeeWith is a Tee with options.
+func TeeWith(opts []LoggerOption, backends ...Backend) grpclog.Logger {
+	return &tee{backends: backends, o: newLoggerOptions(opts)}
+}
+
+// TeeObserved is a Tee calling observe with the time each message took
+// to parse, e.g. to record it in a histogram.
+func TeeObserved(observe func(elapsed time.Duration), backends ...Backend) grpclog.Logger {
+	return &tee{backends: backends, observe: observe}
+}
+
+type tee struct {
+	backends []Backend
+	observe  func(time.Duration)
+	o        loggerOptions
+}
+
+func (t *tee) Fatal(args ...interface{})                 { t.fatal(t.parseln(fmt.Sprint, args...)) }
+func (t *tee) Fatalf(format string, args ...interface{}) { t.fatal(t.parsef(format, args...)) }
+func (t *tee) Fatalln(args ...interface{})               { t.fatal(t.parseln(sprintln, args...)) }
+func (t *tee) Print(args ...interface{})                 { t.print(t.parseln(fmt.Sprint, args...)) }
+func (t *tee) Printf(format string, args ...interface{}) { t.print(t.parsef(format, args...)) }
+func (t *tee) Println(args ...interface{})               { t.print(t.parseln(sprintln, args...)) }
+
+var _ grpclog.LoggerV2 = (*tee)(nil)
+
+func (t *tee) Info(args ...interface{})                    { t.print(t.parseln(fmt.Sprint, args...)) }
+func (t *tee) Infof(format string, args ...interface{})    { t.print(t.parsef(format, args...)) }
+func (t *tee) Infoln(args ...interface{})                  { t.print(t.parseln(sprintln, args...)) }
+func (t *tee) Warning(args ...interface{})                 { t.warning(t.parseln(fmt.Sprint, args...)) }
+func (t *tee) Warningf(format string, args ...interface{}) { t.warning(t.parsef(format, args...)) }
+func (t *tee) Warningln(args ...interface{})               { t.warning(t.parseln(sprintln, args...)) }
+func (t *tee) Error(args ...interface{})                   { t.error(t.parseln(fmt.Sprint, args...)) }
+func (t *tee) Errorf(format string, args ...interface{})   { t.error(t.parsef(format, args...)) }
+func (t *tee) Errorln(args ...interface{})                 { t.error(t.parseln(sprintln, args...)) }
+
+// verbose is implemented by the backends holding a verbosity.
+type verbose interface {
+	V(verbosity int) bool
+}
+
+// V reports whether grpc's info logs of that verbosity are enabled.
+func (t *tee) V(verbosity int) bool {
+	for _, b := range t.backends {
+		if v, ok := b.(verbose); ok {
+			return v.V(verbosity)
+		}
+	}
+	return verbosity <= 0
+}
+
+func (t *tee) parsef(format string, args ...interface{}) (parser.Fields, string, parser.Level) {
+	if t.observe == nil {
+		return t.o.parsef(format, args...)
+	}
+	start := time.Now()
+	fields, message, level := t.o.parsef(format, args...)
+	t.observe(time.Since(start))
+	return fields, message, level
+}
+
+func (t *tee) parseln(render func(...interface{}) string, args ...interface{}) (parser.Fields, string, parser.Level) {
+	if t.observe == nil {
+		return t.o.parseln(render, args...)
+	}
+	start := time.Now()
+	fields, message, level := t.o.parseln(render, args...)
+	t.observe(time.Since(start))
+	return fields, message, level
+}
+
+// fatal emits to every backend before exiting, so that none of them
+// misses the entry: concurrently, for a backend stuck flushing not to
+// hold the others back.
+func (t *tee) fatal(fields parser.Fields, message string, _ parser.Level) {
+	countMatch(logrus.Fields(fields))
+	var wg sync.WaitGroup
+	for _, b := range t.backends {
+		wg.Add(1)
+		go func(b Backend) {
+			defer wg.Done()
+			b.Emit(logrus.FatalLevel, logrus.Fields(fields), message)
+		}(b)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(FatalTimeout):
+	}
+	os.Exit(1)
+}
+
+// warning and error print the messages of grpc's Warning and Error
+// calls, at least at that severity when no rule matched them.
+func (t *tee) warning(fields parser.Fields, message string, level parser.Level) {
+	t.print(fields, message, atLeast(fields, level, parser.WarnLevel))
+}
+
+func (t *tee) error(fields parser.Fields, message string, level parser.Level) {
+	t.print(fields, message, atLeast(fields, level, parser.ErrorLevel))
+}
+
+func atLeast(fields parser.Fields, level, severity parser.Level) parser.Level {
+	if _, matched := fields[parser.EventIDKey]; !matched && severity < level {
+		return severity
+	}
+	return level
+}
+
+func (t *tee) print(fields parser.Fields, message string, level parser.Level) {
+	t.emit(logrusLevel(level), logrus.Fields(fields), message)
+	parser.Release(fields)
+}
+
+func (t *tee) emit(level logrus.Level, fields logrus.Fields, message string) {
+	countMatch(fields)
+	for _, b := range t.backends {
+		b.Emit(level, fields, message)
+	}
+}
+
+type logrusBackend struct {
+	l   *logrus.Entry
+	ids idEntries
+}
+
+// Logrus makes a Backend from a logrus.Entry. Fatal entries are logged
+// without exiting, the Tee being in charge of that.
+func Logrus(l *logrus.Entry) Backend {
+	if l == nil {
+		l = logrus.WithFields(logrus.Fields{"source": "grpc"})
+	}
+	return &logrusBackend{l: l}
+}
+
+func (b *logrusBackend) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	b.ids.with(b.l, fields).Log(level, message)
+}
+
+// SyslogSeverity maps a level to its RFC 5424 severity code, which the
+// syslog, journald and GELF formats share.
+func SyslogSeverity(level logrus.Level) int {
+	switch level {
+	case logrus.PanicLevel:
+		return 0 // emergency
+	case logrus.FatalLevel:
+		return 2 // critical
+	case logrus.ErrorLevel:
+		return 3 // error
+	case logrus.WarnLevel:
+		return 4 // warning
+	case logrus.InfoLevel:
+		return 6 // informational
+	default:
+		return 7 // debug
+	}
+}
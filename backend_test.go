@@ -0,0 +1,110 @@
+package grpclogrus_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/logtest"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+func TestTeeEmitsToEveryBackend(t *testing.T) {
+	var a, b logtest.Captured
+	g := grpclogrus.Tee(&a, &b)
+	g.Printf("grpc: Server.RegisterService found duplicate service registration for %q", "grpc.health.v1.Health")
+
+	for name, c := range map[string]*logtest.Captured{"first": &a, "second": &b} {
+		entries := c.Entries()
+		if len(entries) != 1 {
+			t.Fatalf("%s: want 1 entry, got %v", name, entries)
+		}
+		if e := entries[0]; e.EventID() != "grpc.server_registerservice_found_duplicate_service_registration" || e.Level != logrus.ErrorLevel {
+			t.Errorf("%s: want the structured entry, got %v %v", name, e.Message, e.Fields)
+		}
+	}
+}
+
+// fatalChild is set in the environment of the process logging a fatal
+// entry, which the Tee exits on.
+const fatalChild = "GRPCLOGRUS_FATAL_CHILD"
+
+func TestTeeFatal(t *testing.T) {
+	if os.Getenv(fatalChild) != "" {
+		logFatal()
+		return
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestTeeFatal$")
+	cmd.Env = append(os.Environ(), fatalChild+"=1")
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	start := time.Now()
+	err := cmd.Run()
+	if exit, ok := err.(*exec.ExitError); !ok || exit.ExitCode() != 1 {
+		t.Fatalf("want the process to exit with 1, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("want the process to exit after FatalTimeout despite the stuck backend, took %v", elapsed)
+	}
+	if !strings.Contains(stdout.String(), "emitted fatal") {
+		t.Errorf("want the fatal entry emitted to the backend that isn't stuck, got %q", stdout.String())
+	}
+}
+
+// logFatal logs a fatal entry through a Tee with a stuck backend first,
+// which the Tee must not wait for beyond FatalTimeout.
+func logFatal() {
+	grpclogrus.FatalTimeout = 100 * time.Millisecond
+	stuck := grpclogrus.BackendFunc(func(logrus.Level, logrus.Fields, string) { select {} })
+	printed := grpclogrus.BackendFunc(func(level logrus.Level, _ logrus.Fields, _ string) {
+		os.Stdout.WriteString("emitted " + level.String() + "\n")
+	})
+	grpclogrus.Tee(stuck, printed).Fatalf("grpc: %v", "boom")
+	os.Exit(0)
+}
+
+func TestLogrusBackend(t *testing.T) {
+	var out bytes.Buffer
+	l := logrus.New()
+	l.Out = &out
+	l.Formatter = &logrus.JSONFormatter{}
+	b := grpclogrus.Logrus(logrus.NewEntry(l))
+	b.Emit(logrus.FatalLevel, logrus.Fields{parser.EventIDKey: "grpc.test"}, "fatal, without exiting")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["level"] != "fatal" || entry[parser.EventIDKey] != "grpc.test" {
+		t.Errorf("want the fatal entry logged, got %v", entry)
+	}
+}
+
+func TestSlog(t *testing.T) {
+	var out bytes.Buffer
+	b := grpclogrus.Slog(slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	b.Emit(logrus.WarnLevel, logrus.Fields{"b": 2, "a": 1}, "warned")
+	b.Emit(logrus.FatalLevel, nil, "fatal")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("want 2 records, got %q", out.String())
+	}
+	if !strings.Contains(lines[0], `"level":"WARN","msg":"warned","a":1,"b":2`) {
+		t.Errorf("want the fields as attributes sorted by key, got %s", lines[0])
+	}
+	var fatal map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[1]), &fatal); err != nil {
+		t.Fatal(err)
+	}
+	if fatal["level"] != slog.Level(grpclogrus.LevelFatal).String() {
+		t.Errorf("want fatal entries at LevelFatal, got %v", fatal["level"])
+	}
+}
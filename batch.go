@@ -0,0 +1,113 @@
+package grpclogrus
+
+import (
+	"sync"
+	"time"
+)
+
+// Defaults of a Batcher.
+const (
+	DefaultBatchSize = 100
+	DefaultBatchWait = time.Second
+)
+
+// pendingBatches is the number of batches waiting to be flushed beyond
+// which new batches are dropped, rather than piling up while the remote
+// end is down.
+const pendingBatches = 16
+
+// Batcher groups the entries of a network-backed backend into batches,
+// flushed from a goroutine once MaxSize entries are pending or the oldest
+// waited MaxWait, for the backend not to do one network write per entry.
+// Batches waiting on a slow flush are dropped beyond a few, and counted.
+type Batcher[T any] struct {
+	// MaxSize is the most entries in a batch, and MaxWait the longest an
+	// entry waits to be flushed. Set before use.
+	MaxSize int
+	MaxWait time.Duration
+
+	flush   func(batch []T)
+	once    sync.Once
+	batches chan []T
+	done    chan struct{}
+
+	mu     sync.Mutex
+	batch  []T
+	timer  *time.Timer
+	closed bool
+}
+
+// NewBatcher makes a Batcher passing batches to flush, one at a time,
+// DefaultBatchSize entries or DefaultBatchWait apart at most. flush owns
+// the batches it is passed.
+func NewBatcher[T any](flush func(batch []T)) *Batcher[T] {
+	return &Batcher[T]{MaxSize: DefaultBatchSize, MaxWait: DefaultBatchWait, flush: flush}
+}
+
+func (b *Batcher[T]) start() {
+	b.batches = make(chan []T, pendingBatches)
+	b.done = make(chan struct{})
+	go b.run()
+}
+
+// Add adds an entry to the batch. Entries added once the Batcher is
+// closed are dropped.
+func (b *Batcher[T]) Add(v T) {
+	b.once.Do(b.start)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		CountDropped(1)
+		return
+	}
+	b.batch = append(b.batch, v)
+	if len(b.batch) >= b.MaxSize {
+		b.send()
+		return
+	}
+	if b.timer == nil {
+		b.timer = time.AfterFunc(b.MaxWait, func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.send()
+		})
+	}
+}
+
+// send queues the batch for flushing; it must be called with mu held.
+func (b *Batcher[T]) send() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.batch) == 0 {
+		return
+	}
+	select {
+	case b.batches <- b.batch:
+	default:
+		CountDropped(len(b.batch))
+	}
+	b.batch = nil
+}
+
+// Close flushes the pending entries, waiting for the flushes in progress.
+func (b *Batcher[T]) Close() error {
+	b.once.Do(b.start)
+	b.mu.Lock()
+	if !b.closed {
+		b.send()
+		b.closed = true
+		close(b.batches)
+	}
+	b.mu.Unlock()
+	<-b.done
+	return nil
+}
+
+func (b *Batcher[T]) run() {
+	defer close(b.done)
+	for batch := range b.batches {
+		b.flush(batch)
+	}
+}
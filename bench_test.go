@@ -0,0 +1,132 @@
+package grpclogrus_test
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+
+	"google.golang.org/grpc/grpclog"
+)
+
+// Messages of the benchmarks: a format with a rule, one without, and a
+// Println call with a rule.
+const (
+	matchedFormat  = "transport: http2Server.HandleStreams failed to read frame: %v"
+	fallbackFormat = "transport: some message without rule: %v"
+	printlnFormat  = "grpc: Server.Serve failed to create ServerTransport: "
+)
+
+var errBench = errors.New("connection reset by peer")
+
+// discard is a Backend doing nothing, to measure the parsing alone.
+var discard discardBackend
+
+type discardBackend struct{}
+
+func (discardBackend) Emit(logrus.Level, logrus.Fields, string) {}
+
+func discardLogrus() *logrus.Entry {
+	l := logrus.New()
+	l.Out = io.Discard
+	return logrus.NewEntry(l)
+}
+
+// benchmarks are the cases of the benchmarks and of their allocation
+// budgets: the most allocations an entry may take, a regression failing
+// TestAllocBudgets.
+var benchmarks = []struct {
+	name   string
+	budget float64
+	log    func() func()
+}{
+	{"RawGrpclog", 1, func() func() {
+		l := grpclog.NewLoggerV2(io.Discard, io.Discard, io.Discard)
+		return func() { l.Infof(matchedFormat, errBench) }
+	}},
+	{"PrintfMatched", 12, func() func() {
+		l := grpclogrus.Tee(discard)
+		return func() { l.Printf(matchedFormat, errBench) }
+	}},
+	{"PrintfFallback", 12, func() func() {
+		l := grpclogrus.Tee(discard)
+		return func() { l.Printf(fallbackFormat, errBench) }
+	}},
+	{"Println", 12, func() func() {
+		l := grpclogrus.Tee(discard)
+		return func() { l.Println(printlnFormat, errBench) }
+	}},
+	{"Backend/Logrus", 37, func() func() {
+		l := grpclogrus.Tee(grpclogrus.Logrus(discardLogrus()))
+		return func() { l.Printf(matchedFormat, errBench) }
+	}},
+	{"Backend/Slog", 14, func() func() {
+		l := grpclogrus.Tee(grpclogrus.Slog(slog.New(slog.NewJSONHandler(io.Discard, nil))))
+		return func() { l.Printf(matchedFormat, errBench) }
+	}},
+	{"Backend/Levels", 12, func() func() {
+		l := grpclogrus.Tee(grpclogrus.NewLevels(discard, logrus.InfoLevel))
+		return func() { l.Printf(matchedFormat, errBench) }
+	}},
+	{"Backend/Truncated", 12, func() func() {
+		l := grpclogrus.Tee(grpclogrus.Truncated(discard, 64))
+		return func() { l.Printf(matchedFormat, errBench) }
+	}},
+	{"Backend/Renamed", 14, func() func() {
+		l := grpclogrus.Tee(grpclogrus.Renamed(discard, grpclogrus.OTelFields))
+		return func() { l.Printf(matchedFormat, errBench) }
+	}},
+	{"Backend/SplitAddresses", 12, func() func() {
+		l := grpclogrus.Tee(grpclogrus.SplitAddresses(discard))
+		return func() { l.Printf(matchedFormat, errBench) }
+	}},
+}
+
+func BenchmarkPrintfMatched(b *testing.B)  { runBenchmark(b, "PrintfMatched") }
+func BenchmarkPrintfFallback(b *testing.B) { runBenchmark(b, "PrintfFallback") }
+func BenchmarkPrintln(b *testing.B)        { runBenchmark(b, "Println") }
+func BenchmarkRawGrpclog(b *testing.B)     { runBenchmark(b, "RawGrpclog") }
+
+func BenchmarkBackend(b *testing.B) {
+	for _, bench := range benchmarks {
+		if name := strings.TrimPrefix(bench.name, "Backend/"); name != bench.name {
+			b.Run(name, func(b *testing.B) { runBenchmark(b, bench.name) })
+		}
+	}
+}
+
+func runBenchmark(b *testing.B, name string) {
+	for _, bench := range benchmarks {
+		if bench.name != name {
+			continue
+		}
+		log := bench.log()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			log()
+		}
+		return
+	}
+	b.Fatalf("no benchmark %s", name)
+}
+
+func TestAllocBudgets(t *testing.T) {
+	if testing.Short() {
+		t.Skip("allocation budgets aren't checked in short mode")
+	}
+	if raceEnabled {
+		t.Skip("allocation budgets aren't checked with the race detector")
+	}
+	for _, bench := range benchmarks {
+		log := bench.log()
+		log() // warm the pools and caches up
+		if allocs := testing.AllocsPerRun(100, log); allocs > bench.budget {
+			t.Errorf("%s: %v allocations per entry, over its budget of %v", bench.name, allocs, bench.budget)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package grpclogrus
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+var _ Backend = (*Burst)(nil)
+
+// Defaults of a Burst.
+const (
+	DefaultBurstWindow    = 30 * time.Second
+	DefaultBurstVerbosity = 2
+)
+
+// Burst is a Backend passing entries to Levels, raising them to debug,
+// components included, and their verbosity, for a Window after each
+// error or fatal entry, before reverting them: the detailed transport
+// context around failures is captured without always paying for debug.
+// Errors within a burst extend it.
+type Burst struct {
+	levels *Levels
+
+	// Window is how long a burst lasts after an error. Set before use.
+	Window time.Duration
+	// Verbosity is the verbosity of bursts. Set before use.
+	Verbosity int
+	// Logger is also raised to debug during bursts, when not nil, for
+	// the entries to reach its output. Set before use.
+	Logger *logrus.Logger
+
+	mu    sync.Mutex
+	timer *time.Timer
+	// gen counts the timers started, for a timer firing while its burst
+	// is extended not to end it.
+	gen    uint64
+	saved  levelsState
+	logger logrus.Level
+}
+
+// NewBurst makes a Burst passing entries to levels, bursting for
+// DefaultBurstWindow at DefaultBurstVerbosity.
+func NewBurst(levels *Levels) *Burst {
+	return &Burst{levels: levels, Window: DefaultBurstWindow, Verbosity: DefaultBurstVerbosity}
+}
+
+// Emit passes the entry to the levels, starting or extending a burst
+// after it if it is an error.
+func (b *Burst) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	b.levels.Emit(level, fields, message)
+	if level <= logrus.ErrorLevel {
+		b.burst()
+	}
+}
+
+func (b *Burst) burst() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = b.revertAfter(b.Window)
+		return
+	}
+	b.saved = b.levels.state()
+	debug, verbosity := logrus.DebugLevel.String(), b.Verbosity
+	_ = b.levels.set(levelsState{Level: &debug, Verbosity: &verbosity, Components: map[string]string{}})
+	if b.Logger != nil {
+		b.logger = b.Logger.GetLevel()
+		if b.logger < logrus.DebugLevel {
+			b.Logger.SetLevel(logrus.DebugLevel)
+		}
+	}
+	b.timer = b.revertAfter(b.Window)
+	b.levels.Emit(logrus.InfoLevel, logrus.Fields{
+		parser.EventIDKey: "grpclogrus.debug_burst_started",
+		WindowKey:         b.Window.String(),
+	}, "debug burst started")
+}
+
+// revertAfter starts a timer ending the burst after d, unless another
+// is started before. It's called with mu held.
+func (b *Burst) revertAfter(d time.Duration) *time.Timer {
+	b.gen++
+	gen := b.gen
+	return time.AfterFunc(d, func() { b.revert(gen) })
+}
+
+// revert ends a burst, restoring the levels as they were before it, if
+// the timer of gen is the last started.
+func (b *Burst) revert(gen uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if gen != b.gen || b.timer == nil {
+		return
+	}
+	b.levels.Emit(logrus.InfoLevel, logrus.Fields{
+		parser.EventIDKey: "grpclogrus.debug_burst_ended",
+	}, "debug burst ended")
+	_ = b.levels.set(b.saved)
+	if b.Logger != nil {
+		b.Logger.SetLevel(b.logger)
+	}
+	b.timer = nil
+}
@@ -0,0 +1,29 @@
+package grpclogrus_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/logtest"
+)
+
+func TestBurstExtendedEndsOnce(t *testing.T) {
+	var captured logtest.Captured
+	levels := grpclogrus.NewLevels(&captured, logrus.InfoLevel)
+	b := grpclogrus.NewBurst(levels)
+	b.Window = 5 * time.Millisecond
+	for i := 0; i < 50; i++ {
+		b.Emit(logrus.ErrorLevel, logrus.Fields{}, "failed")
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	started, ended := captured.Count("grpclogrus.debug_burst_started"), captured.Count("grpclogrus.debug_burst_ended")
+	if started == 0 || ended != started {
+		t.Errorf("want as many bursts ended as started, got %d ended for %d started", ended, started)
+	}
+	if levels.Level() != logrus.InfoLevel {
+		t.Errorf("want level reverted to info, got %v", levels.Level())
+	}
+}
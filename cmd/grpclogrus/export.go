@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+// rowGroupSize is the number of entries buffered before being written
+// as a Parquet row group, bounding memory on large inputs.
+const rowGroupSize = 64 << 10
+
+// codeKeys are the fields holding a grpc status code.
+var codeKeys = []string{"code", "grpc.Code(err)", "got.code"}
+
+// exporter writes entries as Parquet rows, with typed columns for the
+// common fields and the JSON of the others.
+type exporter struct {
+	folder parser.Folder
+	w      *parquetWriter
+
+	timestamp, level, message, eventID, component, code, peer, fields *parquetColumn
+}
+
+func newExporter(w io.Writer) *exporter {
+	x := &exporter{
+		timestamp: &parquetColumn{name: "timestamp", typ: parquetInt64, converted: parquetTimestampMicros, optional: true},
+		level:     &parquetColumn{name: "level", typ: parquetByteArray, converted: parquetUTF8},
+		message:   &parquetColumn{name: "message", typ: parquetByteArray, converted: parquetUTF8},
+		eventID:   &parquetColumn{name: "event_id", typ: parquetByteArray, converted: parquetUTF8, optional: true},
+		component: &parquetColumn{name: "component", typ: parquetByteArray, converted: parquetUTF8, optional: true},
+		code:      &parquetColumn{name: "code", typ: parquetByteArray, converted: parquetUTF8, optional: true},
+		peer:      &parquetColumn{name: "peer", typ: parquetByteArray, converted: parquetUTF8, optional: true},
+		fields:    &parquetColumn{name: "fields", typ: parquetByteArray, converted: parquetUTF8},
+	}
+	x.w = newParquetWriter(w, []*parquetColumn{
+		x.timestamp, x.level, x.message, x.eventID, x.component, x.code, x.peer, x.fields,
+	})
+	return x
+}
+
+func (x *exporter) push(line string) {
+	if e, ok := x.folder.Push(line); ok {
+		x.add(e)
+	}
+}
+
+func (x *exporter) flush() {
+	if e, ok := x.folder.Flush(); ok {
+		x.add(e)
+	}
+}
+
+func (x *exporter) add(e parser.Entry) {
+	rest := make(map[string]interface{}, len(e.Fields))
+	for k, v := range e.Fields {
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		rest[k] = v
+	}
+	if t, ok := rest[parser.TimestampKey].(time.Time); ok {
+		x.timestamp.int64(t.UnixNano() / int64(time.Microsecond))
+		delete(rest, parser.TimestampKey)
+	} else {
+		x.timestamp.null()
+	}
+	x.level.string(e.Level.String())
+	x.message.string(e.Message)
+	x.optional(x.eventID, rest, parser.EventIDKey)
+	x.optional(x.component, rest, parser.ComponentKey, parser.PackageKey)
+	x.optional(x.code, rest, codeKeys...)
+	x.optional(x.peer, rest, peerKeys...)
+	b, err := json.Marshal(rest)
+	if err != nil {
+		b = []byte("{}")
+	}
+	x.fields.string(string(b))
+
+	if x.level.rows >= rowGroupSize {
+		x.w.flushRowGroup()
+	}
+}
+
+// optional fills c with the first of keys found in fields, which then
+// isn't repeated in the fields column.
+func (x *exporter) optional(c *parquetColumn, fields map[string]interface{}, keys ...string) {
+	for _, k := range keys {
+		if v, ok := fields[k]; ok {
+			c.string(fmt.Sprint(v))
+			delete(fields, k)
+			return
+		}
+	}
+	c.null()
+}
+
+// export runs the export subcommand, returning its exit code.
+func export(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	output := fs.String("o", "-", "Parquet file written, stdout for -")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s export [-o file.parquet] [file ...]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	out := os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "grpclogrus: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		out = f
+	}
+	bw := bufio.NewWriter(out)
+	x := newExporter(bw)
+
+	inputs := fs.Args()
+	if len(inputs) == 0 {
+		inputs = []string{"-"}
+	}
+	for _, input := range inputs {
+		if err := eachLine(input, x.push); err != nil {
+			fmt.Fprintf(os.Stderr, "grpclogrus: %v\n", err)
+			return 1
+		}
+		x.flush()
+	}
+	if err := x.w.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "grpclogrus: %v\n", err)
+		return 1
+	}
+	if err := bw.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "grpclogrus: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+type exportedRow struct {
+	Timestamp *int64  `parquet:"timestamp,optional"`
+	Level     string  `parquet:"level"`
+	Message   string  `parquet:"message"`
+	EventID   *string `parquet:"event_id,optional"`
+	Component *string `parquet:"component,optional"`
+	Code      *string `parquet:"code,optional"`
+	Peer      *string `parquet:"peer,optional"`
+	Fields    string  `parquet:"fields"`
+}
+
+func TestExport(t *testing.T) {
+	var buf bytes.Buffer
+	x := newExporter(&buf)
+	x.push(duplicateRegistration)
+	x.push(`WARNING: 2020/01/01 00:00:01 grpc: ClientConn.resetTransport failed to create client transport: connection refused; Reconnecting to "10.0.0.1:443"`)
+	x.flush()
+	if err := x.w.flushRowGroup(); err != nil {
+		t.Fatal(err)
+	}
+	x.push("unprefixed")
+	x.flush()
+	if err := x.w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(f.RowGroups()); n != 2 {
+		t.Errorf("want 2 row groups, got %d", n)
+	}
+	rows, err := parquet.Read[exportedRow](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("want 3 rows, got %d", len(rows))
+	}
+
+	first := rows[0]
+	if first.Timestamp == nil || *first.Timestamp != time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).UnixMicro() {
+		t.Errorf("want the timestamp of the line, got %v", first.Timestamp)
+	}
+	if first.Level != "warning" || first.EventID == nil || first.Component == nil || *first.Component != "grpc" || first.Peer != nil {
+		t.Errorf("unexpected row %+v", first)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(first.Fields), &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["service.name"] != "foo" {
+		t.Errorf("want the other fields as JSON, got %s", first.Fields)
+	}
+	for _, k := range []string{"timestamp", "event_id", "package"} {
+		if _, ok := fields[k]; ok {
+			t.Errorf("want %s in its own column only, got %s", k, first.Fields)
+		}
+	}
+	if peer := rows[1].Peer; peer == nil || *peer != "10.0.0.1:443" {
+		t.Errorf("want the peer column, got %v", peer)
+	}
+	if last := rows[2]; last.Timestamp != nil || last.EventID != nil || last.Message != "unprefixed" {
+		t.Errorf("want nulls for the missing columns, got %+v", last)
+	}
+}
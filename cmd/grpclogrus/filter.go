@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+// stringsFlag collects the values of a repeated flag.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string     { return strings.Join(*s, ",") }
+func (s *stringsFlag) Set(v string) error { *s = append(*s, v); return nil }
+
+// filter keeps the entries matching all of its criteria. Levels are
+// filtered by the output logger itself.
+type filter struct {
+	components stringsFlag
+	eventIDs   stringsFlag
+	matches    stringsFlag
+
+	fieldMatches []fieldMatch
+}
+
+type fieldMatch struct {
+	key, value string
+}
+
+// compile checks and splits the -match flags.
+func (f *filter) compile() error {
+	for _, m := range f.matches {
+		i := strings.IndexByte(m, '=')
+		if i <= 0 {
+			return fmt.Errorf("invalid -match %q, want field=value", m)
+		}
+		f.fieldMatches = append(f.fieldMatches, fieldMatch{key: m[:i], value: m[i+1:]})
+	}
+	return nil
+}
+
+func (f *filter) keep(fields parser.Fields) bool {
+	if len(f.components) > 0 && !oneOf(parser.Component(fields), f.components) {
+		return false
+	}
+	if len(f.eventIDs) > 0 && !oneOf(fields[parser.EventIDKey], f.eventIDs) {
+		return false
+	}
+	for _, m := range f.fieldMatches {
+		v, ok := fields[m.key]
+		if !ok || fmt.Sprint(v) != m.value {
+			return false
+		}
+	}
+	return true
+}
+
+func oneOf(v interface{}, values []string) bool {
+	if v == nil {
+		return false
+	}
+	s := fmt.Sprint(v)
+	for _, value := range values {
+		if s == value {
+			return true
+		}
+	}
+	return false
+}
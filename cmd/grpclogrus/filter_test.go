@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+func TestFilter(t *testing.T) {
+	f := filter{
+		components: stringsFlag{"transport", "core"},
+		eventIDs:   stringsFlag{"grpc.transport.conn_failed", "grpc.core.state_change"},
+		matches:    stringsFlag{"code=14", "peer=10.0.0.1:443"},
+	}
+	if err := f.compile(); err != nil {
+		t.Fatal(err)
+	}
+	kept := parser.Fields{
+		parser.PackageKey: "transport",
+		parser.EventIDKey: "grpc.transport.conn_failed",
+		"code":            14,
+		"peer":            "10.0.0.1:443",
+	}
+	if !f.keep(kept) {
+		t.Errorf("want %v kept", kept)
+	}
+	for name, fields := range map[string]parser.Fields{
+		"other component": {parser.ComponentKey: "server", parser.EventIDKey: "grpc.transport.conn_failed", "code": 14, "peer": "10.0.0.1:443"},
+		"no component":    {parser.EventIDKey: "grpc.transport.conn_failed", "code": 14, "peer": "10.0.0.1:443"},
+		"other event id":  {parser.ComponentKey: "core", parser.EventIDKey: "grpc.core.other", "code": 14, "peer": "10.0.0.1:443"},
+		"unmatched field": {parser.ComponentKey: "core", parser.EventIDKey: "grpc.core.state_change", "code": 0, "peer": "10.0.0.1:443"},
+		"missing field":   {parser.ComponentKey: "core", parser.EventIDKey: "grpc.core.state_change", "code": 14},
+	} {
+		if f.keep(fields) {
+			t.Errorf("%s: want %v filtered out", name, fields)
+		}
+	}
+	if !new(filter).keep(parser.Fields{}) {
+		t.Error("want every entry kept without criteria")
+	}
+}
+
+func TestFilterInvalidMatch(t *testing.T) {
+	for _, m := range []string{"code", "=14"} {
+		f := filter{matches: stringsFlag{m}}
+		if err := f.compile(); err == nil {
+			t.Errorf("%q: want an error", m)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"time"
+)
+
+// pollInterval is how often a followed file is checked for new lines.
+const pollInterval = 250 * time.Millisecond
+
+// follow emits the lines appended to the file name from now on, until an
+// error occurs, calling idle whenever it waits for more. Like tail -F, it
+// reopens the file when it's rotated and starts over when it's truncated.
+func follow(name string, emit func(line string), idle func()) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer func() { f.Close() }()
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	r := bufio.NewReader(f)
+
+	var partial []byte
+	for {
+		line, err := r.ReadBytes('\n')
+		offset += int64(len(line))
+		partial = append(partial, line...)
+		if err == nil {
+			if len(partial) > maxLineLength {
+				partial = partial[:maxLineLength]
+			}
+			emit(string(partial))
+			partial = partial[:0]
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+
+		idle()
+		time.Sleep(pollInterval)
+		current, err := os.Stat(name)
+		if err != nil {
+			// rotated away, and not recreated yet
+			continue
+		}
+		opened, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		switch {
+		case !os.SameFile(current, opened):
+			next, err := os.Open(name)
+			if err != nil {
+				continue
+			}
+			f.Close()
+			f, offset, partial = next, 0, partial[:0]
+			r.Reset(f)
+		case current.Size() < offset:
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			offset, partial = 0, partial[:0]
+			r.Reset(f)
+		}
+	}
+}
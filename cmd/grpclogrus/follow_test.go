@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFollow(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "grpc.log")
+	if err := os.WriteFile(name, []byte("before\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	lines := make(chan string, 16)
+	idle := make(chan struct{}, 1)
+	go follow(name, func(line string) { lines <- line }, func() {
+		select {
+		case idle <- struct{}{}:
+		default:
+		}
+	})
+	waitIdle := func() {
+		t.Helper()
+		select {
+		case <-idle:
+		case <-time.After(5 * time.Second):
+			t.Fatal("follow never waited for more lines")
+		}
+	}
+	next := func(want string) {
+		t.Helper()
+		select {
+		case line := <-lines:
+			if line != want {
+				t.Errorf("want %q, got %q", want, line)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%q never followed", want)
+		}
+	}
+	write := func(flag int, s string) {
+		t.Helper()
+		f, err := os.OpenFile(name, flag|os.O_WRONLY, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	waitIdle()
+	write(os.O_APPEND, "appended\nparti")
+	next("appended\n")
+	waitIdle()
+	write(os.O_APPEND, "al\n")
+	next("partial\n")
+
+	// rotated
+	waitIdle()
+	if err := os.Rename(name, name+".1"); err != nil {
+		t.Fatal(err)
+	}
+	write(os.O_CREATE, "rotated\n")
+	next("rotated\n")
+
+	// truncated
+	waitIdle()
+	write(os.O_TRUNC, "new\n")
+	next("new\n")
+
+	select {
+	case line := <-lines:
+		t.Errorf("unexpected line %q", line)
+	default:
+	}
+}
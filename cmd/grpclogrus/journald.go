@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// journalEntry is an entry of journalctl's json output. MESSAGE is a
+// string, or an array of bytes when it isn't valid UTF-8.
+type journalEntry struct {
+	Message json.RawMessage `json:"MESSAGE"`
+}
+
+// journal emits the lines of the messages unit logged to the journal, as
+// read by journalctl, calling flush after each message: a message being
+// an entry, however many lines it spans. With followJournal, only the
+// messages logged from now on are read, until an error occurs.
+func journal(unit string, followJournal bool, emit func(line string), flush func()) error {
+	args := []string{"--output=json", "--output-fields=MESSAGE", "--no-pager"}
+	if unit != "" {
+		args = append(args, "--unit="+unit)
+	}
+	if followJournal {
+		args = append(args, "--follow", "--lines=0")
+	}
+	cmd := exec.Command("journalctl", args...)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	sc := bufio.NewScanner(out)
+	sc.Buffer(make([]byte, 0, 64<<10), maxLineLength)
+	for sc.Scan() {
+		var e journalEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			cmd.Process.Kill()
+			cmd.Wait()
+			return fmt.Errorf("journalctl: %v", err)
+		}
+		message, ok := journalMessage(e.Message)
+		if !ok {
+			continue
+		}
+		for _, line := range strings.Split(message, "\n") {
+			emit(line)
+		}
+		flush()
+	}
+	if err := sc.Err(); err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return fmt.Errorf("journalctl: %v", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("journalctl: %v", err)
+	}
+	return nil
+}
+
+func journalMessage(raw json.RawMessage) (string, bool) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, true
+	}
+	var b []byte
+	if err := json.Unmarshal(raw, &b); err != nil {
+		return "", false
+	}
+	return string(b), true
+}
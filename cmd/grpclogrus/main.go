@@ -0,0 +1,240 @@
+/*
+Command grpclogrus converts grpc-go log output to structured JSON, one
+object per line, using the same rules as package grpclogrus.
+
+	grpclogrus [-f] [-output format] [-level lvl] [-component c] [-event-id id] [-match field=value] [file ...]
+	grpclogrus -input journald [-unit name] [-f] [flags]
+	grpclogrus stats [-top n] [file ...]
+	grpclogrus tui [-f] file
+	grpclogrus export [-o file.parquet] [file ...]
+
+Files are read in order, stdin when none is given or for "-". Lines
+continuing an entry, like those of a stack trace, are folded into its
+stack field. Lines wrapped by container runtimes, as printed by kubectl
+logs or stored by Docker and containerd, are unwrapped.
+
+With -f (or --follow), the single file given is followed like tail -F,
+across rotations and truncations.
+
+With -input journald, the messages of the systemd unit given by -unit
+are read from the journal with journalctl instead, and followed with -f.
+
+With -replay, entries are dated by the timestamps of the original lines
+rather than by the time they're converted.
+
+Entries are printed with -output as JSON lines (the default), text
+(colored when stdout is a terminal, the default with -f), logfmt, or
+CSV. CSV columns are the fields listed by -columns, along with time,
+level and msg.
+
+The tui subcommand browses the entries of a file, followed with -f, in a
+table: entries can be filtered by field=value or by text as they come,
+and inspected with all their fields.
+
+The export subcommand converts the entries to Parquet, for analytics
+with DuckDB or Spark: timestamp, level, message, event_id, component,
+code and peer are columns of their own, other fields are kept as JSON in
+the fields column.
+
+Entries can be filtered by minimum level, by component (the "component"
+field, or "package" for older grpc-go versions, e.g. transport), by event
+id, and by the value of any field.
+-component, -event-id and -match can be repeated: an entry is kept when
+it has one of the components and one of the event ids given, and
+matches every -match.
+*/
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+// maxLineLength bounds the lines read from inputs. Longer lines are cut.
+const maxLineLength = 1 << 20
+
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "stats":
+			os.Exit(stats(os.Args[2:]))
+		case "tui":
+			os.Exit(tui(os.Args[2:]))
+		case "export":
+			os.Exit(export(os.Args[2:]))
+		}
+	}
+
+	var (
+		followFile bool
+		replay     bool
+		input      string
+		unit       string
+		output     string
+		columns    string
+		level      string
+		f          filter
+	)
+	flag.BoolVar(&followFile, "f", false, "follow the file given, as tail -F does")
+	flag.BoolVar(&followFile, "follow", false, "same as -f")
+	flag.BoolVar(&replay, "replay", false, "date entries by the timestamps of the original lines")
+	flag.StringVar(&input, "input", "file", "input read: file or journald")
+	flag.StringVar(&unit, "unit", "", "systemd unit whose journal is read, with -input journald")
+	flag.StringVar(&output, "output", "", "output format: json, text, logfmt or csv")
+	flag.StringVar(&columns, "columns", defaultColumns, "comma separated columns of the csv output")
+	flag.StringVar(&level, "level", "trace", "minimum level of the entries printed")
+	flag.Var(&f.components, "component", "print entries of this component (repeatable)")
+	flag.Var(&f.eventIDs, "event-id", "print entries with this event id (repeatable)")
+	flag.Var(&f.matches, "match", "print entries whose field=value (repeatable)")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [flags] [file ...]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if output == "" {
+		output = "json"
+		if followFile {
+			output = "text"
+		}
+	}
+	out := logrus.New()
+	out.Out = os.Stdout
+	formatter, err := newFormatter(output, columns)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "grpclogrus: %v\n", err)
+		os.Exit(2)
+	}
+	out.Formatter = formatter
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "grpclogrus: %v\n", err)
+		os.Exit(2)
+	}
+	out.Level = lvl
+	if err := f.compile(); err != nil {
+		fmt.Fprintf(os.Stderr, "grpclogrus: %v\n", err)
+		os.Exit(2)
+	}
+	c := &converter{out: out, filter: f, replay: replay}
+
+	switch input {
+	case "file":
+	case "journald":
+		if err := journal(unit, followFile, c.emit, c.flush); err != nil {
+			fmt.Fprintf(os.Stderr, "grpclogrus: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "grpclogrus: unknown input %q\n", input)
+		os.Exit(2)
+	}
+
+	if followFile {
+		if flag.NArg() != 1 || flag.Arg(0) == "-" {
+			fmt.Fprintln(os.Stderr, "grpclogrus: -f needs a single file")
+			os.Exit(2)
+		}
+		err := follow(flag.Arg(0), c.emit, c.flush)
+		fmt.Fprintf(os.Stderr, "grpclogrus: %v\n", err)
+		os.Exit(1)
+	}
+
+	inputs := flag.Args()
+	if len(inputs) == 0 {
+		inputs = []string{"-"}
+	}
+	for _, input := range inputs {
+		if err := eachLine(input, c.emit); err != nil {
+			fmt.Fprintf(os.Stderr, "grpclogrus: %v\n", err)
+			os.Exit(1)
+		}
+		c.flush()
+	}
+}
+
+// eachLine calls fn with every line of the file name, or of stdin for "-".
+func eachLine(name string, fn func(line string)) error {
+	if name == "-" {
+		return scanLines(os.Stdin, fn)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := scanLines(f, fn); err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	return nil
+}
+
+// scanLines calls fn with every line of r, cutting those longer than
+// maxLineLength.
+func scanLines(r io.Reader, fn func(line string)) error {
+	br := bufio.NewReaderSize(r, 64<<10)
+	var line []byte
+	for {
+		chunk, isPrefix, err := br.ReadLine()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if room := maxLineLength - len(line); room > 0 {
+			line = append(line, chunk[:min(room, len(chunk))]...)
+		}
+		if !isPrefix {
+			fn(string(line))
+			line = line[:0]
+		}
+	}
+}
+
+// converter prints the entries folded from the lines it's given.
+type converter struct {
+	out    *logrus.Logger
+	filter filter
+	replay bool
+	folder parser.Folder
+	last   time.Time
+}
+
+func (c *converter) emit(line string) {
+	if e, ok := c.folder.Push(line); ok {
+		c.log(e)
+	}
+}
+
+// flush prints the pending entry, once no more lines are expected.
+func (c *converter) flush() {
+	if e, ok := c.folder.Flush(); ok {
+		c.log(e)
+	}
+}
+
+func (c *converter) log(e parser.Entry) {
+	if !c.filter.keep(e.Fields) {
+		return
+	}
+	entry := c.out.WithFields(logrus.Fields(e.Fields))
+	if c.replay {
+		if t, ok := e.Fields[parser.TimestampKey].(time.Time); ok {
+			c.last = t
+			delete(entry.Data, parser.TimestampKey)
+		}
+		if !c.last.IsZero() {
+			entry = entry.WithTime(c.last)
+		}
+	}
+	entry.Log(logrus.Level(e.Level), e.Message)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+const duplicateRegistration = `WARNING: 2020/01/01 00:00:00 grpc: Server.RegisterService found duplicate service registration for "foo"`
+
+// convert emits lines through c, printing JSON, returning the entries
+// printed.
+func convert(t *testing.T, c *converter, lines ...string) []map[string]interface{} {
+	t.Helper()
+	var out bytes.Buffer
+	c.out = logrus.New()
+	c.out.Out = &out
+	c.out.Formatter = &logrus.JSONFormatter{}
+	c.out.Level = logrus.TraceLevel
+	for _, line := range lines {
+		c.emit(line + "\n")
+	}
+	c.flush()
+	var entries []map[string]interface{}
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var e map[string]interface{}
+		if err := dec.Decode(&e); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestConvert(t *testing.T) {
+	entries := convert(t, new(converter),
+		duplicateRegistration,
+		"INFO: 2020/01/01 00:00:01 [core] Subchannel Connectivity change to READY",
+		"\tat frame",
+	)
+	if len(entries) != 2 {
+		t.Fatalf("want 2 entries, got %v", entries)
+	}
+	if e := entries[0]; e["level"] != "warning" || e[parser.EventIDKey] == nil || e["service.name"] != "foo" {
+		t.Errorf("unexpected entry %v", e)
+	}
+	if e := entries[1]; e["level"] != "info" || e[parser.StackKey] != "\tat frame" {
+		t.Errorf("want the stack folded into the last entry, got %v", e)
+	}
+}
+
+func TestConvertReplay(t *testing.T) {
+	entries := convert(t, &converter{replay: true},
+		duplicateRegistration,
+		"continued",
+		"unprefixed",
+	)
+	for _, e := range entries {
+		if e["time"] != "2020-01-01T00:00:00Z" {
+			t.Errorf("want the time of the original line, got %v", e)
+		}
+		if _, ok := e[parser.TimestampKey]; ok {
+			t.Errorf("want the timestamp field removed, got %v", e)
+		}
+	}
+	now := time.Now().Format("2006")
+	entries = convert(t, new(converter), duplicateRegistration)
+	if !strings.HasPrefix(entries[0]["time"].(string), now) {
+		t.Errorf("want entries dated as converted without -replay, got %v", entries[0]["time"])
+	}
+}
+
+func TestScanLinesCutsLongLines(t *testing.T) {
+	in := strings.Repeat("x", 2*maxLineLength) + "\nnext\nlast"
+	var lines []string
+	if err := scanLines(strings.NewReader(in), func(line string) { lines = append(lines, line) }); err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 3 || len(lines[0]) != maxLineLength || lines[1] != "next" || lines[2] != "last" {
+		t.Errorf("want the long line cut and the others whole, got %d lines", len(lines))
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+)
+
+// defaultColumns of the csv output.
+const defaultColumns = "time,level,msg,event_id,package,err"
+
+// newFormatter makes the formatter of an -output format.
+func newFormatter(output, columns string) (logrus.Formatter, error) {
+	switch output {
+	case "json":
+		return &logrus.JSONFormatter{}, nil
+	case "text":
+		f := grpclogrus.NewTextFormatter()
+		f.FullTimestamp = true
+		return f, nil
+	case "logfmt":
+		f := grpclogrus.NewTextFormatter()
+		f.FullTimestamp = true
+		f.DisableColors = true
+		return f, nil
+	case "csv":
+		return &csvFormatter{columns: strings.Split(columns, ",")}, nil
+	}
+	return nil, fmt.Errorf("unknown output %q, want json, text, logfmt or csv", output)
+}
+
+// csvFormatter writes a header of its columns, then one record per entry.
+// The time, level and msg columns are the entry's; others are fields,
+// empty when an entry doesn't have them.
+type csvFormatter struct {
+	columns     []string
+	wroteHeader bool
+}
+
+func (f *csvFormatter) Format(e *logrus.Entry) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if !f.wroteHeader {
+		if err := w.Write(f.columns); err != nil {
+			return nil, err
+		}
+		f.wroteHeader = true
+	}
+	record := make([]string, len(f.columns))
+	for i, col := range f.columns {
+		switch col {
+		case "time":
+			record[i] = e.Time.Format(time.RFC3339Nano)
+		case "level":
+			record[i] = e.Level.String()
+		case "msg":
+			record[i] = e.Message
+		default:
+			if v, ok := e.Data[col]; ok {
+				record[i] = fmt.Sprint(v)
+			}
+		}
+	}
+	if err := w.Write(record); err != nil {
+		return nil, err
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+func TestNewFormatter(t *testing.T) {
+	e := &logrus.Entry{
+		Logger:  logrus.New(),
+		Time:    time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Level:   logrus.WarnLevel,
+		Message: "failed",
+		Data:    logrus.Fields{"event_id": "grpc.transport.conn_failed", "err": "connection refused"},
+	}
+	for output, want := range map[string]string{
+		"json":   `{"err":"connection refused","event_id":"grpc.transport.conn_failed","level":"warning","msg":"failed","time":"2020-01-02T03:04:05Z"}` + "\n",
+		"logfmt": `time="2020-01-02T03:04:05Z" level=warning msg=failed event_id=grpc.transport.conn_failed err="connection refused"` + "\n",
+		"csv": "time,level,msg,event_id,package,err\n" +
+			"2020-01-02T03:04:05Z,warning,failed,grpc.transport.conn_failed,,connection refused\n",
+	} {
+		f, err := newFormatter(output, defaultColumns)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := f.Format(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != want {
+			t.Errorf("%s: want\n%s\ngot\n%s", output, want, b)
+		}
+	}
+	if _, err := newFormatter("xml", defaultColumns); err == nil {
+		t.Error("want an error for an unknown output")
+	}
+}
+
+func TestCSVHeaderOnce(t *testing.T) {
+	f, _ := newFormatter("csv", "msg,code")
+	var out strings.Builder
+	for _, e := range []*logrus.Entry{
+		{Message: "a, quoted \"one\"", Data: logrus.Fields{"code": 14}},
+		{Message: "b", Data: logrus.Fields{}},
+	} {
+		b, err := f.Format(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out.Write(b)
+	}
+	want := "msg,code\n\"a, quoted \"\"one\"\"\",14\nb,\n"
+	if out.String() != want {
+		t.Errorf("want\n%s\ngot\n%s", want, out.String())
+	}
+}
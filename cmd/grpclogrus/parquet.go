@@ -0,0 +1,322 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+)
+
+// Parquet physical types, converted types, repetitions, encodings and
+// codecs used by parquetWriter, from parquet.thrift.
+const (
+	parquetInt64     = 2
+	parquetByteArray = 6
+
+	parquetUTF8            = 0
+	parquetTimestampMicros = 10
+
+	parquetRequired = 0
+	parquetOptional = 1
+
+	parquetPlain = 0
+	parquetRLE   = 3
+
+	parquetGzip = 2
+)
+
+// parquetMagic starts and ends Parquet files.
+const parquetMagic = "PAR1"
+
+// parquetColumn buffers the values of a column for the current row group.
+type parquetColumn struct {
+	name      string
+	typ       int32
+	converted int32 // -1 for none
+	optional  bool
+
+	defs   []byte // definition levels, 1 for present values
+	values bytes.Buffer
+	rows   int
+
+	chunks []parquetChunk
+}
+
+// parquetChunk locates a column chunk written, for the footer.
+type parquetChunk struct {
+	offset           int64
+	values           int64
+	uncompressedSize int64
+	compressedSize   int64
+}
+
+func (c *parquetColumn) null() {
+	c.defs = append(c.defs, 0)
+	c.rows++
+}
+
+func (c *parquetColumn) int64(v int64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(v))
+	c.values.Write(b[:])
+	c.defs = append(c.defs, 1)
+	c.rows++
+}
+
+func (c *parquetColumn) string(s string) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], uint32(len(s)))
+	c.values.Write(b[:])
+	c.values.WriteString(s)
+	c.defs = append(c.defs, 1)
+	c.rows++
+}
+
+// parquetWriter writes a Parquet file sequentially, a row group at a
+// time, each column of a row group being a single gzipped data page in
+// PLAIN encoding: which any reader handles.
+type parquetWriter struct {
+	w         io.Writer
+	offset    int64
+	columns   []*parquetColumn
+	rowGroups []int64 // rows of the row groups written
+	err       error
+}
+
+func newParquetWriter(w io.Writer, columns []*parquetColumn) *parquetWriter {
+	p := &parquetWriter{w: w, columns: columns}
+	p.write([]byte(parquetMagic))
+	return p
+}
+
+func (p *parquetWriter) write(b []byte) {
+	if p.err != nil {
+		return
+	}
+	n, err := p.w.Write(b)
+	p.offset += int64(n)
+	p.err = err
+}
+
+// flushRowGroup writes the rows buffered by the columns as a row group.
+func (p *parquetWriter) flushRowGroup() error {
+	rows := p.columns[0].rows
+	if rows == 0 {
+		return p.err
+	}
+	for _, c := range p.columns {
+		var page bytes.Buffer
+		if c.optional {
+			levels := rleBitWidth1(c.defs)
+			var n [4]byte
+			binary.LittleEndian.PutUint32(n[:], uint32(len(levels)))
+			page.Write(n[:])
+			page.Write(levels)
+		}
+		page.Write(c.values.Bytes())
+
+		var compressed bytes.Buffer
+		zw := gzip.NewWriter(&compressed)
+		zw.Write(page.Bytes())
+		zw.Close()
+
+		var h thriftWriter
+		h.i32(1, 0) // DATA_PAGE
+		h.i32(2, int32(page.Len()))
+		h.i32(3, int32(compressed.Len()))
+		h.structBegin(5)
+		h.i32(1, int32(c.rows))
+		h.i32(2, parquetPlain)
+		h.i32(3, parquetRLE)
+		h.i32(4, parquetRLE)
+		h.structEnd()
+		h.stop()
+
+		chunk := parquetChunk{
+			offset:           p.offset,
+			values:           int64(c.rows),
+			uncompressedSize: int64(len(h.buf) + page.Len()),
+			compressedSize:   int64(len(h.buf) + compressed.Len()),
+		}
+		p.write(h.buf)
+		p.write(compressed.Bytes())
+		c.chunks = append(c.chunks, chunk)
+		c.defs, c.rows = c.defs[:0], 0
+		c.values.Reset()
+	}
+	p.rowGroups = append(p.rowGroups, int64(rows))
+	return p.err
+}
+
+// Close flushes the last row group and writes the footer.
+func (p *parquetWriter) Close() error {
+	if err := p.flushRowGroup(); err != nil {
+		return err
+	}
+	var total int64
+	for _, n := range p.rowGroups {
+		total += n
+	}
+
+	var m thriftWriter
+	m.i32(1, 1) // version
+	m.listBegin(2, thriftStruct, len(p.columns)+1)
+	m.elemBegin()
+	m.binary(4, "schema")
+	m.i32(5, int32(len(p.columns)))
+	m.structEnd()
+	for _, c := range p.columns {
+		m.elemBegin()
+		m.i32(1, c.typ)
+		repetition := int32(parquetRequired)
+		if c.optional {
+			repetition = parquetOptional
+		}
+		m.i32(3, repetition)
+		m.binary(4, c.name)
+		if c.converted >= 0 {
+			m.i32(6, c.converted)
+		}
+		m.structEnd()
+	}
+	m.i64(3, total)
+	m.listBegin(4, thriftStruct, len(p.rowGroups))
+	for i, rows := range p.rowGroups {
+		m.elemBegin()
+		var size int64
+		m.listBegin(1, thriftStruct, len(p.columns))
+		for _, c := range p.columns {
+			chunk := c.chunks[i]
+			size += chunk.uncompressedSize
+			m.elemBegin()
+			m.i64(2, chunk.offset)
+			m.structBegin(3)
+			m.i32(1, c.typ)
+			m.listBegin(2, thriftI32, 2)
+			m.listI32(parquetPlain)
+			m.listI32(parquetRLE)
+			m.listBegin(3, thriftBinary, 1)
+			m.listBinary(c.name)
+			m.i32(4, parquetGzip)
+			m.i64(5, chunk.values)
+			m.i64(6, chunk.uncompressedSize)
+			m.i64(7, chunk.compressedSize)
+			m.i64(9, chunk.offset)
+			m.structEnd()
+			m.structEnd()
+		}
+		m.i64(2, size)
+		m.i64(3, rows)
+		m.structEnd()
+	}
+	m.binary(6, "grpclogrus")
+	m.stop()
+
+	p.write(m.buf)
+	var n [4]byte
+	binary.LittleEndian.PutUint32(n[:], uint32(len(m.buf)))
+	p.write(n[:])
+	p.write([]byte(parquetMagic))
+	return p.err
+}
+
+// rleBitWidth1 encodes levels of bit width 1 in the RLE/bit-packing
+// hybrid, as runs.
+func rleBitWidth1(levels []byte) []byte {
+	var b []byte
+	for i := 0; i < len(levels); {
+		j := i
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		b = binary.AppendUvarint(b, uint64(j-i)<<1)
+		b = append(b, levels[i])
+		i = j
+	}
+	return b
+}
+
+// Thrift compact protocol types.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes structs in the Thrift compact protocol, in which
+// Parquet's metadata is.
+type thriftWriter struct {
+	buf  []byte
+	last []int16 // id of the last field of the structs being written
+}
+
+func (t *thriftWriter) field(id int16, typ byte) {
+	var last int16
+	if n := len(t.last); n > 0 {
+		last = t.last[n-1]
+		t.last[n-1] = id
+	} else {
+		t.last = append(t.last, id)
+	}
+	if d := id - last; d > 0 && d <= 15 {
+		t.buf = append(t.buf, byte(d)<<4|typ)
+		return
+	}
+	t.buf = append(t.buf, typ)
+	t.buf = binary.AppendVarint(t.buf, int64(id))
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.field(id, thriftI32)
+	t.buf = binary.AppendVarint(t.buf, int64(v))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.field(id, thriftI64)
+	t.buf = binary.AppendVarint(t.buf, v)
+}
+
+func (t *thriftWriter) binary(id int16, s string) {
+	t.field(id, thriftBinary)
+	t.listBinary(s)
+}
+
+func (t *thriftWriter) structBegin(id int16) {
+	t.field(id, thriftStruct)
+	t.last = append(t.last, 0)
+}
+
+// elemBegin starts a struct element of a list.
+func (t *thriftWriter) elemBegin() {
+	t.last = append(t.last, 0)
+}
+
+func (t *thriftWriter) structEnd() {
+	t.buf = append(t.buf, 0)
+	t.last = t.last[:len(t.last)-1]
+}
+
+func (t *thriftWriter) stop() {
+	t.buf = append(t.buf, 0)
+}
+
+func (t *thriftWriter) listBegin(id int16, elem byte, n int) {
+	t.field(id, thriftList)
+	if n < 15 {
+		t.buf = append(t.buf, byte(n)<<4|elem)
+		return
+	}
+	t.buf = append(t.buf, 0xf0|elem)
+	t.buf = binary.AppendUvarint(t.buf, uint64(n))
+}
+
+func (t *thriftWriter) listI32(v int32) {
+	t.buf = binary.AppendVarint(t.buf, int64(v))
+}
+
+func (t *thriftWriter) listBinary(s string) {
+	t.buf = binary.AppendUvarint(t.buf, uint64(len(s)))
+	t.buf = append(t.buf, s...)
+}
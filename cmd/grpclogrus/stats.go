@@ -0,0 +1,152 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+// peerKeys are the fields holding the address of a peer or a target.
+var peerKeys = []string{"addr", "target", "peer"}
+
+// maxUnmatchedLength truncates the unmatched lines listed.
+const maxUnmatchedLength = 80
+
+type counts map[string]int
+
+func (c counts) top(n int) []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if c[keys[i]] != c[keys[j]] {
+			return c[keys[i]] > c[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if n > 0 && len(keys) > n {
+		keys = keys[:n]
+	}
+	return keys
+}
+
+type summary struct {
+	folder    parser.Folder
+	entries   int
+	errors    int
+	events    counts
+	levels    map[string]parser.Level
+	peers     counts
+	unmatched counts
+}
+
+func (s *summary) push(line string) {
+	if e, ok := s.folder.Push(line); ok {
+		s.add(e)
+	}
+}
+
+func (s *summary) flush() {
+	if e, ok := s.folder.Flush(); ok {
+		s.add(e)
+	}
+}
+
+func (s *summary) add(e parser.Entry) {
+	s.entries++
+	fields, message := e.Fields, e.Message
+	if e.Level <= parser.ErrorLevel {
+		s.errors++
+	}
+	if !e.Matched {
+		if len(message) > maxUnmatchedLength {
+			message = message[:maxUnmatchedLength]
+		}
+		s.unmatched[message]++
+		return
+	}
+	id := fmt.Sprint(fields[parser.EventIDKey])
+	s.events[id]++
+	s.levels[id] = e.Level
+	for _, k := range peerKeys {
+		if v, ok := fields[k]; ok {
+			s.peers[fmt.Sprint(v)]++
+		}
+	}
+}
+
+func (s *summary) print(w io.Writer, top int) {
+	unmatched := 0
+	for _, n := range s.unmatched {
+		unmatched += n
+	}
+	fmt.Fprintf(w, "entries\t%d\n", s.entries)
+	fmt.Fprintf(w, "errors\t%d\t%s\n", s.errors, percent(s.errors, s.entries))
+	fmt.Fprintf(w, "unmatched\t%d\t%s\n", unmatched, percent(unmatched, s.entries))
+
+	fmt.Fprintf(w, "\nEVENT ID\tLEVEL\tCOUNT\tSHARE\n")
+	for _, id := range s.events.top(0) {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", id, s.levels[id], s.events[id], percent(s.events[id], s.entries))
+	}
+	if len(s.peers) > 0 {
+		fmt.Fprintf(w, "\nPEER/TARGET\tCOUNT\n")
+		for _, peer := range s.peers.top(top) {
+			fmt.Fprintf(w, "%s\t%d\n", peer, s.peers[peer])
+		}
+	}
+	if len(s.unmatched) > 0 {
+		fmt.Fprintf(w, "\nUNMATCHED\tCOUNT\n")
+		for _, line := range s.unmatched.top(top) {
+			fmt.Fprintf(w, "%s\t%d\n", line, s.unmatched[line])
+		}
+	}
+}
+
+func percent(n, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", 100*float64(n)/float64(total))
+}
+
+// stats runs the stats subcommand, returning its exit code.
+func stats(args []string) int {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	top := fs.Int("top", 10, "number of peers and unmatched lines listed")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s stats [flags] [file ...]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	s := &summary{
+		events:    make(counts),
+		levels:    make(map[string]parser.Level),
+		peers:     make(counts),
+		unmatched: make(counts),
+	}
+	inputs := fs.Args()
+	if len(inputs) == 0 {
+		inputs = []string{"-"}
+	}
+	for _, input := range inputs {
+		if err := eachLine(input, s.push); err != nil {
+			fmt.Fprintf(os.Stderr, "grpclogrus: %v\n", err)
+			return 1
+		}
+		s.flush()
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	s.print(w, *top)
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "grpclogrus: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+func TestSummary(t *testing.T) {
+	s := &summary{
+		events:    make(counts),
+		levels:    make(map[string]parser.Level),
+		peers:     make(counts),
+		unmatched: make(counts),
+	}
+	for _, line := range []string{
+		`WARNING: 2020/01/01 00:00:00 grpc: ClientConn.resetTransport failed to create client transport: connection refused; Reconnecting to "10.0.0.1:443"`,
+		`WARNING: 2020/01/01 00:00:01 grpc: ClientConn.resetTransport failed to create client transport: connection refused; Reconnecting to "10.0.0.1:443"`,
+		`WARNING: 2020/01/01 00:00:02 grpc: ClientConn.resetTransport failed to create client transport: connection refused; Reconnecting to "10.0.0.2:443"`,
+		`ERROR: 2020/01/01 00:00:03 grpc: Server.Serve failed to create ServerTransport: boom`,
+		`INFO: 2020/01/01 00:00:04 ` + strings.Repeat("unmatched ", 10),
+	} {
+		s.push(line)
+	}
+	s.flush()
+
+	var out strings.Builder
+	s.print(&out, 1)
+	want := `entries	5
+errors	1	20.0%
+unmatched	1	20.0%
+
+EVENT ID	LEVEL	COUNT	SHARE
+grpc.clientconn_resettransport_failed	warning	3	60.0%
+grpc.server_serve_failed_to_create_servertransport	error	1	20.0%
+
+PEER/TARGET	COUNT
+10.0.0.1:443	2
+
+UNMATCHED	COUNT
+` + strings.Repeat("unmatched ", 8) + `	1
+`
+	if out.String() != want {
+		t.Errorf("want\n%s\ngot\n%s", want, out.String())
+	}
+}
+
+func TestCountsTop(t *testing.T) {
+	c := counts{"b": 2, "a": 2, "c": 3, "d": 1}
+	if got := strings.Join(c.top(3), ","); got != "c,a,b" {
+		t.Errorf("want the most frequent first, ties by name, got %s", got)
+	}
+	if got := len(c.top(0)); got != 4 {
+		t.Errorf("want every key without a limit, got %d", got)
+	}
+}
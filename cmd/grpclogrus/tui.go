@@ -0,0 +1,397 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aybabtme/grpclogrus/parser"
+	"golang.org/x/term"
+)
+
+// tui runs the tui subcommand, returning its exit code.
+func tui(args []string) int {
+	fs := flag.NewFlagSet("tui", flag.ExitOnError)
+	followFile := fs.Bool("f", false, "follow the file, as tail -F does")
+	fs.BoolVar(followFile, "follow", false, "same as -f")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s tui [flags] file\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 || fs.Arg(0) == "-" {
+		fmt.Fprintln(os.Stderr, "grpclogrus: tui needs a single file, stdin being the keyboard")
+		return 2
+	}
+	in, out := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(in) || !term.IsTerminal(out) {
+		fmt.Fprintln(os.Stderr, "grpclogrus: tui needs a terminal")
+		return 2
+	}
+
+	v := &viewer{name: fs.Arg(0), sticky: true}
+	errs := make(chan error, 1)
+	go func() { errs <- v.read(*followFile) }()
+
+	state, err := term.MakeRaw(in)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "grpclogrus: %v\n", err)
+		return 1
+	}
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	restore := func() {
+		fmt.Print("\x1b[?25h\x1b[?1049l")
+		term.Restore(in, state)
+	}
+	keys := make(chan []byte)
+	go readKeys(os.Stdin, keys)
+	tick := time.NewTicker(pollInterval)
+	defer tick.Stop()
+
+	for {
+		width, height, err := term.GetSize(out)
+		if err != nil {
+			width, height = 80, 24
+		}
+		os.Stdout.Write(v.render(width, height))
+		select {
+		case k := <-keys:
+			if !v.key(k) {
+				restore()
+				return 0
+			}
+		case <-tick.C:
+		case err := <-errs:
+			if err == nil {
+				// The whole file was read: keep browsing it.
+				errs = nil
+				continue
+			}
+			restore()
+			fmt.Fprintf(os.Stderr, "grpclogrus: %v\n", err)
+			return 1
+		}
+	}
+}
+
+// readKeys sends the keys pressed, escape sequences included, as read
+// at once from the terminal.
+func readKeys(r io.Reader, keys chan<- []byte) {
+	buf := make([]byte, 64)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			close(keys)
+			return
+		}
+		k := make([]byte, n)
+		copy(k, buf[:n])
+		keys <- k
+	}
+}
+
+// viewer is the state of the tui: the entries read so far, and those
+// shown by the current query.
+type viewer struct {
+	name string
+
+	mu      sync.Mutex
+	entries []parser.Entry
+	folder  parser.Folder
+
+	query    viewQuery
+	shown    []int // indices of the entries matching query
+	scanned  int   // entries checked against query
+	selected int   // index in shown
+	top      int   // first row of shown on screen
+	sticky   bool  // whether the selection follows new entries
+	inspect  bool
+	editing  bool
+	input    []byte
+}
+
+func (v *viewer) read(followFile bool) error {
+	if followFile {
+		return follow(v.name, v.push, v.flush)
+	}
+	if err := eachLine(v.name, v.push); err != nil {
+		return err
+	}
+	v.flush()
+	return nil
+}
+
+func (v *viewer) push(line string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if e, ok := v.folder.Push(line); ok {
+		v.entries = append(v.entries, e)
+	}
+}
+
+func (v *viewer) flush() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if e, ok := v.folder.Flush(); ok {
+		v.entries = append(v.entries, e)
+	}
+}
+
+// key handles a key, reporting false to quit.
+func (v *viewer) key(k []byte) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if k == nil {
+		return false
+	}
+	if v.editing {
+		v.edit(k)
+		return true
+	}
+	switch string(k) {
+	case "q", "\x03":
+		return false
+	case "j", "\x1b[B":
+		v.move(1)
+	case "k", "\x1b[A":
+		v.move(-1)
+	case " ", "\x1b[6~":
+		v.move(10)
+	case "b", "\x1b[5~":
+		v.move(-10)
+	case "g", "\x1b[H":
+		v.move(-len(v.shown))
+	case "G", "\x1b[F":
+		v.move(len(v.shown))
+	case "\r":
+		v.inspect = !v.inspect && len(v.shown) > 0
+	case "\x1b":
+		v.inspect = false
+	case "/":
+		v.editing, v.input = true, []byte(v.query.text)
+	case "c":
+		v.setQuery("")
+	}
+	return true
+}
+
+// edit handles a key typed in the filter prompt.
+func (v *viewer) edit(k []byte) {
+	switch string(k) {
+	case "\r":
+		v.editing = false
+		v.setQuery(string(v.input))
+	case "\x1b", "\x03":
+		v.editing = false
+	case "\x7f", "\b":
+		if len(v.input) > 0 {
+			v.input = v.input[:len(v.input)-1]
+		}
+	default:
+		for _, c := range k {
+			if c >= ' ' && c < 0x7f {
+				v.input = append(v.input, c)
+			}
+		}
+	}
+}
+
+func (v *viewer) move(n int) {
+	v.selected += n
+	if v.selected >= len(v.shown)-1 {
+		v.selected = len(v.shown) - 1
+	}
+	if v.selected < 0 {
+		v.selected = 0
+	}
+	v.sticky = v.selected >= len(v.shown)-1
+}
+
+func (v *viewer) setQuery(text string) {
+	v.query = parseViewQuery(text)
+	v.shown, v.scanned, v.selected, v.top = nil, 0, 0, 0
+	v.sticky, v.inspect = true, false
+}
+
+// update checks the entries read since the last render against the
+// query.
+func (v *viewer) update() {
+	for ; v.scanned < len(v.entries); v.scanned++ {
+		if v.query.keep(v.entries[v.scanned]) {
+			v.shown = append(v.shown, v.scanned)
+		}
+	}
+	if v.sticky && len(v.shown) > 0 {
+		v.selected = len(v.shown) - 1
+	}
+}
+
+// render draws the screen, of the given size, over the previous one.
+func (v *viewer) render(width, height int) []byte {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.update()
+
+	var b bytes.Buffer
+	b.WriteString("\x1b[H")
+	header := fmt.Sprintf(" %s  %d/%d entries", v.name, len(v.shown), len(v.entries))
+	if v.query.text != "" {
+		header += "  filter: " + v.query.text
+	}
+	b.WriteString("\x1b[7m" + pad(header, width) + "\x1b[0m\r\n")
+
+	rows := height - 2
+	if v.inspect && len(v.shown) > 0 {
+		v.renderEntry(&b, v.entries[v.shown[v.selected]], width, rows)
+	} else {
+		v.renderList(&b, width, rows)
+	}
+
+	b.WriteString("\x1b[J\x1b[" + fmt.Sprint(height) + ";1H")
+	switch {
+	case v.editing:
+		b.WriteString(pad("/"+string(v.input), width))
+	case v.inspect:
+		b.WriteString("\x1b[2m" + pad(" esc/enter back  q quit", width) + "\x1b[0m")
+	default:
+		b.WriteString("\x1b[2m" + pad(" j/k move  enter inspect  / filter by field=value or text  c clear  G follow  q quit", width) + "\x1b[0m")
+	}
+	return b.Bytes()
+}
+
+func (v *viewer) renderList(b *bytes.Buffer, width, rows int) {
+	rows-- // column names
+	if v.selected < v.top {
+		v.top = v.selected
+	}
+	if v.selected >= v.top+rows {
+		v.top = v.selected - rows + 1
+	}
+	b.WriteString("\x1b[1m" + pad(fmt.Sprintf("%-12s %-5s %-32s %s", "TIME", "LEVEL", "EVENT", "MESSAGE"), width) + "\x1b[0m\r\n")
+	for i := v.top; i < len(v.shown) && i < v.top+rows; i++ {
+		e := v.entries[v.shown[i]]
+		ts := ""
+		if t, ok := e.Fields[parser.TimestampKey].(time.Time); ok {
+			ts = t.Format("15:04:05.000")
+		}
+		event, _ := e.Fields[parser.EventIDKey].(string)
+		level := fmt.Sprintf("%-5.5s", e.Level)
+		row := pad(fmt.Sprintf("%-12s %s %-32.32s %s", ts, level, event, printable(e.Message)), width)
+		if i == v.selected {
+			b.WriteString("\x1b[7m" + row + "\x1b[0m\r\n")
+			continue
+		}
+		// Color the level alone, once the row fits the screen.
+		if len(row) >= 13+len(level) {
+			row = row[:13] + levelColor(e.Level) + level + "\x1b[0m" + row[13+len(level):]
+		}
+		b.WriteString(row + "\x1b[K\r\n")
+	}
+}
+
+func (v *viewer) renderEntry(b *bytes.Buffer, e parser.Entry, width, rows int) {
+	lines := []string{
+		"message: " + printable(e.Message),
+		"level: " + e.Level.String(),
+	}
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		if k != parser.StackKey {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		lines = append(lines, printable(fmt.Sprintf("%s: %v", k, e.Fields[k])))
+	}
+	if stack, ok := e.Fields[parser.StackKey].(string); ok {
+		lines = append(lines, parser.StackKey+":")
+		for _, l := range strings.Split(stack, "\n") {
+			lines = append(lines, "  "+printable(l))
+		}
+	}
+	for i := 0; i < len(lines) && i < rows; i++ {
+		b.WriteString(truncate(lines[i], width) + "\x1b[K\r\n")
+	}
+}
+
+func levelColor(level parser.Level) string {
+	switch {
+	case level <= parser.ErrorLevel:
+		return "\x1b[31m"
+	case level == parser.WarnLevel:
+		return "\x1b[33m"
+	case level == parser.InfoLevel:
+		return "\x1b[36m"
+	default:
+		return "\x1b[37m"
+	}
+}
+
+// pad truncates or pads s to width runes.
+func pad(s string, width int) string {
+	s = truncate(s, width)
+	return s + strings.Repeat(" ", width-len([]rune(s)))
+}
+
+func truncate(s string, width int) string {
+	if r := []rune(s); len(r) > width {
+		return string(r[:width])
+	}
+	return s
+}
+
+// printable replaces the tabs and control characters of s, which would
+// garble the screen.
+func printable(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t':
+			return ' '
+		case r < ' ' || r == 0x7f:
+			return '?'
+		}
+		return r
+	}, s)
+}
+
+// viewQuery filters the entries shown: words of the form field=value
+// match fields, others match the message.
+type viewQuery struct {
+	text    string
+	matches []fieldMatch
+	words   []string
+}
+
+func parseViewQuery(text string) viewQuery {
+	q := viewQuery{text: strings.TrimSpace(text)}
+	for _, w := range strings.Fields(text) {
+		if i := strings.IndexByte(w, '='); i > 0 {
+			q.matches = append(q.matches, fieldMatch{key: w[:i], value: w[i+1:]})
+			continue
+		}
+		q.words = append(q.words, w)
+	}
+	return q
+}
+
+func (q *viewQuery) keep(e parser.Entry) bool {
+	for _, m := range q.matches {
+		v, ok := e.Fields[m.key]
+		if !ok || fmt.Sprint(v) != m.value {
+			return false
+		}
+	}
+	for _, w := range q.words {
+		if !strings.Contains(e.Message, w) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,342 @@
+package grpclogrus
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+	"google.golang.org/grpc/grpclog"
+	"gopkg.in/yaml.v3"
+)
+
+// Config declares the behavior of grpc's logger and of the interceptors,
+// for deployments to manage it as configuration, loaded by LoadConfig,
+// rather than code. Levels are named as logrus' are, such as "warning".
+// The zero Config logs grpc's messages at info and above, synchronously,
+// and sets no options.
+type Config struct {
+	// Format of the entries of the logger of Logger: "json", or "text"
+	// when empty.
+	Format string `json:"format" yaml:"format"`
+	// Colors colors the text of the logger of Logger.
+	Colors bool `json:"colors" yaml:"colors"`
+	// Level of grpc's messages logged, info when empty.
+	Level string `json:"level" yaml:"level"`
+	// Verbosity reported by the Levels' V.
+	Verbosity int `json:"verbosity" yaml:"verbosity"`
+	// ComponentLevels are the levels of components, e.g. "transport".
+	ComponentLevels map[string]string `json:"component_levels" yaml:"component_levels"`
+	// CategoryLevels adjust the levels of the messages of parser's
+	// categories, e.g. "security".
+	CategoryLevels map[string]CategoryLevelConfig `json:"category_levels" yaml:"category_levels"`
+	// RawMessageField is the key of WithRawMessageField, when set.
+	RawMessageField string `json:"raw_message_field" yaml:"raw_message_field"`
+	// Strict turns parser's strict mode on.
+	Strict bool `json:"strict" yaml:"strict"`
+	// MaxFieldLength truncates the values of fields longer than that many
+	// bytes, of grpc's messages and of the interceptors.
+	MaxFieldLength int `json:"max_field_length" yaml:"max_field_length"`
+	// AsyncSize emits grpc's messages asynchronously through a buffer of
+	// that many entries, when positive.
+	AsyncSize int `json:"async_size" yaml:"async_size"`
+	// AggregateKeepalive summarizes keepalive's messages by peer.
+	AggregateKeepalive bool `json:"aggregate_keepalive" yaml:"aggregate_keepalive"`
+	// KeepaliveInterval is the time between keepalive summaries, such as
+	// "30s", DefaultKeepaliveInterval when empty or not positive.
+	KeepaliveInterval string `json:"keepalive_interval" yaml:"keepalive_interval"`
+	// RulePacks are the names of the rule packs loaded, registered with
+	// RegisterRulePack, such as etcdpack.Name.
+	RulePacks []string `json:"rule_packs" yaml:"rule_packs"`
+	// RuleFiles are the paths of the rule files loaded, see RuleFile.
+	RuleFiles []string `json:"rule_files" yaml:"rule_files"`
+	// Backends are emitted grpc's messages besides the logger given to
+	// NewFromConfig, or instead of it when it's nil.
+	Backends []BackendConfig `json:"backends" yaml:"backends"`
+
+	// Sampling is the rate of WithSampling, all calls when nil.
+	Sampling *float64 `json:"sampling" yaml:"sampling"`
+	// FieldPrefix is the prefix of WithFieldPrefix.
+	FieldPrefix string `json:"field_prefix" yaml:"field_prefix"`
+	// WithoutStart leaves the start entries of calls out.
+	WithoutStart bool `json:"without_start" yaml:"without_start"`
+	// Metadata are the keys of WithMetadata.
+	Metadata []string `json:"metadata" yaml:"metadata"`
+	// RequestIDHeader turns WithRequestID on, with that header, when set.
+	RequestIDHeader string `json:"request_id_header" yaml:"request_id_header"`
+	// PeerDetails turns WithPeerDetails on.
+	PeerDetails bool `json:"peer_details" yaml:"peer_details"`
+	// RedactedFields are the names of WithRedactedFields.
+	RedactedFields []string `json:"redacted_fields" yaml:"redacted_fields"`
+	// Methods are the rules of WithMethodLevel and WithoutMethods, in
+	// order.
+	Methods []MethodConfig `json:"methods" yaml:"methods"`
+}
+
+// MethodConfig is a rule of Config for the methods matching Pattern:
+// calls are logged at Level, or not at all with Skip.
+type MethodConfig struct {
+	Pattern string `json:"pattern" yaml:"pattern"`
+	Level   string `json:"level" yaml:"level"`
+	Skip    bool   `json:"skip" yaml:"skip"`
+}
+
+// CategoryLevelConfig is the parser.CategoryLevel of a category of
+// Config, by level names.
+type CategoryLevelConfig struct {
+	Min      string `json:"min" yaml:"min"`
+	Override string `json:"override" yaml:"override"`
+}
+
+// BackendConfig is a backend of Config: a logrus logger or a slog
+// handler, of Type "logrus" or "slog", writing to Output in Format, or a
+// backend of a Type of RegisterBackend.
+type BackendConfig struct {
+	Type string `json:"type" yaml:"type"`
+	// Output is "stderr" when empty, "stdout", or the path of a file,
+	// appended to. Files stay open for the life of the process.
+	Output string `json:"output" yaml:"output"`
+	// Format is "json", or "text" when empty.
+	Format string `json:"format" yaml:"format"`
+	// Level is the least severe level emitted to the backend, of those
+	// the levels of Config let through, all of them when empty.
+	Level string `json:"level" yaml:"level"`
+	// Options are the settings of the backends of RegisterBackend.
+	Options map[string]string `json:"options" yaml:"options"`
+}
+
+// LoadConfig reads a Config from a file, as YAML if its extension is
+// .yaml or .yml, as JSON otherwise. Unknown keys are errors, not to miss
+// a misspelled option.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	if err := decodeFile(path, &cfg); err != nil {
+		return cfg, fmt.Errorf("grpclogrus: config %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// decodeFile decodes the file of path into v, as LoadConfig does.
+func decodeFile(path string, v interface{}) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(f)
+		dec.KnownFields(true)
+		return dec.Decode(v)
+	default:
+		dec := json.NewDecoder(f)
+		dec.DisallowUnknownFields()
+		return dec.Decode(v)
+	}
+}
+
+// NewFromConfig makes a grpclog.Logger from a logrus.Entry configured by
+// cfg, along with the Levels it logs through, to change at runtime, and
+// loads the rule packs of cfg. The options of the interceptors are those
+// of cfg.Options.
+func NewFromConfig(l *logrus.Entry, cfg Config) (grpclog.Logger, *Levels, error) {
+	next, err := cfg.backend(l)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.MaxFieldLength > 0 {
+		next = Truncated(next, cfg.MaxFieldLength)
+	}
+	if cfg.AsyncSize > 0 {
+		next = NewAsync(next, cfg.AsyncSize)
+	}
+	if cfg.AggregateKeepalive {
+		a := NewKeepaliveAggregator(next)
+		if cfg.KeepaliveInterval != "" {
+			interval, err := time.ParseDuration(cfg.KeepaliveInterval)
+			if err != nil {
+				return nil, nil, fmt.Errorf("grpclogrus: keepalive interval: %v", err)
+			}
+			a.Interval = interval
+		}
+		next = a
+	}
+	levels := NewLevels(next, logrus.InfoLevel)
+	if err := cfg.apply(levels, Config{}); err != nil {
+		return nil, nil, err
+	}
+	var opts []LoggerOption
+	if cfg.RawMessageField != "" {
+		opts = append(opts, WithRawMessageField(cfg.RawMessageField))
+	}
+	return TeeWith(opts, levels), levels, nil
+}
+
+// apply applies what of cfg can change at runtime to levels and to
+// package parser, once it's all valid: the levels and verbosity,
+// parser's settings changed from prev, and the rule packs, the rule files
+// being read anew.
+func (cfg Config) apply(levels *Levels, prev Config) error {
+	level, err := parseLevel(cfg.Level)
+	if err != nil {
+		return err
+	}
+	components := make(map[string]logrus.Level, len(cfg.ComponentLevels))
+	for component, name := range cfg.ComponentLevels {
+		if components[component], err = parseLevel(name); err != nil {
+			return err
+		}
+	}
+	categoryLevels, err := cfg.categoryLevels()
+	if err != nil {
+		return err
+	}
+	packs, err := cfg.packs()
+	if err != nil {
+		return err
+	}
+
+	levels.update(func(s *levelSettings) {
+		s.level, s.verbosity, s.components = level, cfg.Verbosity, components
+	})
+	if !reflect.DeepEqual(cfg.CategoryLevels, prev.CategoryLevels) {
+		parser.SetCategoryLevels(categoryLevels)
+	}
+	if cfg.Strict != prev.Strict {
+		parser.SetStrict(cfg.Strict, nil)
+	}
+	loadConfigPacks(packs)
+	return nil
+}
+
+func (cfg Config) categoryLevels() (map[parser.Category]parser.CategoryLevel, error) {
+	if len(cfg.CategoryLevels) == 0 {
+		return nil, nil
+	}
+	categoryLevels := make(map[parser.Category]parser.CategoryLevel, len(cfg.CategoryLevels))
+	for category, c := range cfg.CategoryLevels {
+		var adjust parser.CategoryLevel
+		for _, l := range []struct {
+			name  string
+			level *parser.Level
+		}{{c.Min, &adjust.Min}, {c.Override, &adjust.Override}} {
+			if l.name == "" {
+				continue
+			}
+			level, err := parseLevel(l.name)
+			if err != nil {
+				return nil, err
+			}
+			*l.level = parser.Level(level)
+		}
+		categoryLevels[parser.Category(category)] = adjust
+	}
+	return categoryLevels, nil
+}
+
+// Logger makes a logrus logger writing to stderr in cfg.Format, at the
+// most verbose of the levels of cfg, for NewFromConfig and the
+// interceptors when there's no logrus setup.
+func (cfg Config) Logger() (*logrus.Entry, error) {
+	var f logrus.Formatter
+	switch cfg.Format {
+	case "json":
+		f = jsonFormatter()
+	case "", "text":
+		f = textFormatter(cfg.Colors)
+	default:
+		return nil, fmt.Errorf("grpclogrus: unknown format %q", cfg.Format)
+	}
+	max, err := parseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(cfg.ComponentLevels)+len(cfg.Methods))
+	for _, name := range cfg.ComponentLevels {
+		names = append(names, name)
+	}
+	for _, m := range cfg.Methods {
+		if !m.Skip {
+			names = append(names, m.Level)
+		}
+	}
+	for _, name := range names {
+		level, err := parseLevel(name)
+		if err != nil {
+			return nil, err
+		}
+		if level > max {
+			max = level
+		}
+	}
+	l := logrus.New()
+	l.Out = os.Stderr
+	l.Formatter = f
+	l.Level = max
+	return l.WithField("source", "grpc"), nil
+}
+
+// Options are the options of the interceptors and StatsHandler set by
+// cfg.
+func (cfg Config) Options() ([]Option, error) {
+	var opts []Option
+	if cfg.Sampling != nil {
+		if *cfg.Sampling < 0 || *cfg.Sampling > 1 {
+			return nil, fmt.Errorf("grpclogrus: sampling %v not between 0 and 1", *cfg.Sampling)
+		}
+		opts = append(opts, WithSampling(*cfg.Sampling))
+	}
+	if cfg.MaxFieldLength > 0 {
+		opts = append(opts, WithMaxFieldLength(cfg.MaxFieldLength))
+	}
+	if cfg.FieldPrefix != "" {
+		opts = append(opts, WithFieldPrefix(cfg.FieldPrefix))
+	}
+	if cfg.WithoutStart {
+		opts = append(opts, WithoutStart())
+	}
+	if len(cfg.Metadata) > 0 {
+		opts = append(opts, WithMetadata(cfg.Metadata...))
+	}
+	if cfg.RequestIDHeader != "" {
+		opts = append(opts, WithRequestID(cfg.RequestIDHeader))
+	}
+	if cfg.PeerDetails {
+		opts = append(opts, WithPeerDetails())
+	}
+	if len(cfg.RedactedFields) > 0 {
+		opts = append(opts, WithRedactedFields(cfg.RedactedFields...))
+	}
+	for _, m := range cfg.Methods {
+		if _, err := regexp.Compile(m.Pattern); err != nil {
+			return nil, fmt.Errorf("grpclogrus: method pattern: %v", err)
+		}
+		if m.Skip {
+			opts = append(opts, WithoutMethods(m.Pattern))
+			continue
+		}
+		level, err := parseLevel(m.Level)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithMethodLevel(m.Pattern, level))
+	}
+	return opts, nil
+}
+
+// parseLevel parses the name of a logrus level, info when empty.
+func parseLevel(name string) (logrus.Level, error) {
+	if name == "" {
+		return logrus.InfoLevel, nil
+	}
+	level, err := logrus.ParseLevel(name)
+	if err != nil {
+		return level, fmt.Errorf("grpclogrus: %v", err)
+	}
+	return level, nil
+}
@@ -0,0 +1,229 @@
+package grpclogrus_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/parser"
+	"github.com/aybabtme/grpclogrus/parser/etcdpack"
+)
+
+const ruleFile = `name: test@rules
+parsef:
+  "test: failed to dial %s: %v":
+    id: test.dial_failed
+    level: warning
+    category: connection
+    message: failed to dial
+    args: [target, err]
+`
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// unloadConfigPacks unloads the packs of the configs of the test when it
+// ends.
+func unloadConfigPacks(t *testing.T) {
+	t.Cleanup(func() {
+		if _, _, err := grpclogrus.NewFromConfig(nil, grpclogrus.Config{}); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestLoadRuleFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	writeFile(t, path, ruleFile)
+	p, err := grpclogrus.LoadRuleFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, ok := p.Parsef["test: failed to dial %s: %v"]
+	if !ok || p.Name != "test@rules" {
+		t.Fatalf("want the rule of the file in pack test@rules, got %+v", p)
+	}
+	fields, message := r.Parse("10.0.0.1:443", "eof")
+	if fields["target"] != "10.0.0.1:443" || fields["err"] != "eof" || message != "failed to dial" {
+		t.Errorf("want the args named, got %v %q", fields, message)
+	}
+	if r.Level != parser.WarnLevel || r.Category != parser.CategoryConnection {
+		t.Errorf("want the level and category of the file, got %v %v", r.Level, r.Category)
+	}
+}
+
+func TestLoadRuleFileInvalid(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"noid.yaml":       "parsef:\n  \"x %v\":\n    level: error\n",
+		"level.yaml":      "parsef:\n  \"x %v\":\n    id: x\n    level: loud\n",
+		"misspelled.yaml": "parsef:\n  \"x %v\":\n    id: x\n    fields: [a]\n",
+	} {
+		path := filepath.Join(dir, name)
+		writeFile(t, path, content)
+		if _, err := grpclogrus.LoadRuleFile(path); err == nil {
+			t.Errorf("%s: want an error", name)
+		}
+	}
+}
+
+func TestNewFromConfigRulePacks(t *testing.T) {
+	unloadConfigPacks(t)
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	writeFile(t, path, ruleFile)
+	cfg := grpclogrus.Config{RulePacks: []string{etcdpack.Name}, RuleFiles: []string{path}}
+	if _, _, err := grpclogrus.NewFromConfig(nil, cfg); err != nil {
+		t.Fatal(err)
+	}
+	sources := make(map[string]bool)
+	for _, r := range parser.Rules() {
+		sources[r.Source] = true
+	}
+	if !sources[etcdpack.Name] || !sources["test@rules"] {
+		t.Errorf("want the rules of both packs, got the sources %v", sources)
+	}
+
+	if _, _, err := grpclogrus.NewFromConfig(nil, grpclogrus.Config{RuleFiles: []string{path}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range parser.Rules() {
+		if r.Source == etcdpack.Name {
+			t.Fatalf("want the pack dropped from the config unloaded, got %v", r)
+		}
+	}
+
+	cfg = grpclogrus.Config{RulePacks: []string{"nope"}}
+	if _, _, err := grpclogrus.NewFromConfig(nil, cfg); err == nil {
+		t.Error("want an error for an unknown pack")
+	}
+}
+
+func TestNewFromConfigBackends(t *testing.T) {
+	dir := t.TempDir()
+	all, errors := filepath.Join(dir, "all.log"), filepath.Join(dir, "errors.log")
+	cfg := grpclogrus.Config{Backends: []grpclogrus.BackendConfig{
+		{Type: "logrus", Output: all, Format: "json"},
+		{Type: "slog", Output: errors, Format: "json", Level: "error"},
+	}}
+	g, _, err := grpclogrus.NewFromConfig(nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Println("grpc: Server.Serve failed to complete security handshake.")
+	g.Println("EmptyUnaryCall done")
+
+	for path, want := range map[string][]string{
+		all:    {"grpc.server_serve_failed_to_complete_security_handshake", "emptyunarycall_done"},
+		errors: {"grpc.server_serve_failed_to_complete_security_handshake"},
+	} {
+		out, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		dec := json.NewDecoder(bytes.NewReader(out))
+		for dec.More() {
+			var entry map[string]interface{}
+			if err := dec.Decode(&entry); err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, entry[parser.EventIDKey].(string))
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s: want the entries of %v, got %v", filepath.Base(path), want, got)
+		}
+	}
+
+	cfg.Backends = []grpclogrus.BackendConfig{{Type: "nope"}}
+	if _, _, err := grpclogrus.NewFromConfig(nil, cfg); err == nil {
+		t.Error("want an error for an unknown backend type")
+	}
+}
+
+func TestRegisterBackend(t *testing.T) {
+	var messages []string
+	grpclogrus.RegisterBackend("test", func(c grpclogrus.BackendConfig) (grpclogrus.Backend, error) {
+		prefix := c.Options["prefix"]
+		return grpclogrus.BackendFunc(func(level logrus.Level, fields logrus.Fields, message string) {
+			messages = append(messages, prefix+message)
+		}), nil
+	})
+	cfg := grpclogrus.Config{Backends: []grpclogrus.BackendConfig{{Type: "test", Options: map[string]string{"prefix": "> "}}}}
+	g, _, err := grpclogrus.NewFromConfig(nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Println("EmptyUnaryCall done")
+	if len(messages) != 1 || messages[0] != "> EmptyUnaryCall done" {
+		t.Errorf("want the message through the registered backend, got %q", messages)
+	}
+}
+
+func TestSignalReloadRuleFiles(t *testing.T) {
+	unloadConfigPacks(t)
+	dir := t.TempDir()
+	rules, path := filepath.Join(dir, "rules.yaml"), filepath.Join(dir, "config.yaml")
+	writeFile(t, rules, ruleFile)
+	writeFile(t, path, "rule_files: ["+rules+"]\n")
+	cfg, err := grpclogrus.LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, levels, err := grpclogrus.NewFromConfig(nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out lockedBuffer
+	l := logrus.New()
+	l.Out = &out
+	l.Formatter = &logrus.JSONFormatter{}
+	stop := grpclogrus.EnableSignalReload(path, cfg, levels, logrus.NewEntry(l))
+	defer stop()
+
+	writeFile(t, rules, strings.Replace(ruleFile, "test.dial_failed", "test.dial_failed_reloaded", 1))
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); !strings.Contains(out.String(), "config reloaded"); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("want the config reloaded on SIGHUP")
+		}
+	}
+	if logged := out.String(); !strings.Contains(logged, `"changed":"rule_files"`) {
+		t.Errorf("want the rule files reported as changed, got %s", logged)
+	}
+	fields, _, _ := parser.Parsef("test: failed to dial %s: %v", "10.0.0.1:443", "eof")
+	if fields[parser.EventIDKey] != "test.dial_failed_reloaded" {
+		t.Errorf("want the rule file read anew, got %v", fields)
+	}
+}
+
+// lockedBuffer is a bytes.Buffer safe to read while logrus writes to it.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,134 @@
+package grpclogrus
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// backendTypes are the types of RegisterBackend.
+var backendTypes = struct {
+	sync.Mutex
+	m map[string]func(BackendConfig) (Backend, error)
+}{m: make(map[string]func(BackendConfig) (Backend, error))}
+
+// RegisterBackend makes backends of a type configurable in the Backends
+// of Config, made by fn from their BackendConfig, e.g. from its Options:
+//
+//	grpclogrus.RegisterBackend("loki", func(c grpclogrus.BackendConfig) (grpclogrus.Backend, error) {
+//		return grpcloki.New(c.Options["url"]), nil
+//	})
+//
+// Register before loading configs. Types registered replace "logrus" and
+// "slog".
+func RegisterBackend(typ string, fn func(BackendConfig) (Backend, error)) {
+	backendTypes.Lock()
+	backendTypes.m[typ] = fn
+	backendTypes.Unlock()
+}
+
+// backend is the backend of cfg: l, if not nil, and its Backends.
+func (cfg Config) backend(l *logrus.Entry) (Backend, error) {
+	if len(cfg.Backends) == 0 {
+		return Logrus(l), nil
+	}
+	var backends multiBackend
+	if l != nil {
+		backends = append(backends, Logrus(l))
+	}
+	for _, c := range cfg.Backends {
+		b, err := c.backend()
+		if err != nil {
+			return nil, err
+		}
+		backends = append(backends, b)
+	}
+	if len(backends) == 1 {
+		return backends[0], nil
+	}
+	return backends, nil
+}
+
+func (c BackendConfig) backend() (Backend, error) {
+	max := logrus.TraceLevel
+	if c.Level != "" {
+		var err error
+		if max, err = parseLevel(c.Level); err != nil {
+			return nil, err
+		}
+	}
+	backendTypes.Lock()
+	fn, ok := backendTypes.m[c.Type]
+	backendTypes.Unlock()
+
+	var (
+		b   Backend
+		err error
+	)
+	switch {
+	case ok:
+		b, err = fn(c)
+	case c.Type == "logrus" || c.Type == "slog":
+		b, err = c.writerBackend()
+	default:
+		err = fmt.Errorf("grpclogrus: unknown backend type %q", c.Type)
+	}
+	if err != nil || max == logrus.TraceLevel {
+		return b, err
+	}
+	return BackendFunc(func(level logrus.Level, fields logrus.Fields, message string) {
+		if level <= max || level <= logrus.FatalLevel {
+			b.Emit(level, fields, message)
+		}
+	}), nil
+}
+
+// writerBackend makes the "logrus" and "slog" backends.
+func (c BackendConfig) writerBackend() (Backend, error) {
+	if c.Format != "" && c.Format != "json" && c.Format != "text" {
+		return nil, fmt.Errorf("grpclogrus: unknown format %q", c.Format)
+	}
+	var out io.Writer
+	switch c.Output {
+	case "", "stderr":
+		out = os.Stderr
+	case "stdout":
+		out = os.Stdout
+	default:
+		f, err := os.OpenFile(c.Output, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("grpclogrus: backend output: %v", err)
+		}
+		out = f
+	}
+
+	if c.Type == "slog" {
+		opts := &slog.HandlerOptions{Level: slog.LevelDebug}
+		var h slog.Handler = slog.NewTextHandler(out, opts)
+		if c.Format == "json" {
+			h = slog.NewJSONHandler(out, opts)
+		}
+		return Slog(slog.New(h).With("source", "grpc")), nil
+	}
+	l := logrus.New()
+	l.Out = out
+	l.Formatter = textFormatter(false)
+	if c.Format == "json" {
+		l.Formatter = jsonFormatter()
+	}
+	l.Level = logrus.TraceLevel
+	return Logrus(l.WithField("source", "grpc")), nil
+}
+
+// multiBackend emits entries to each of its backends, in order.
+type multiBackend []Backend
+
+func (m multiBackend) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	for _, b := range m {
+		b.Emit(level, fields, message)
+	}
+}
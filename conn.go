@@ -0,0 +1,48 @@
+package grpclogrus
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// Fields of the entries logged by WatchConn. The time in state is the
+// time the connection spent in the previous state.
+const (
+	StateKey       = "state"
+	FromStateKey   = "from_state"
+	TimeInStateKey = "time_in_state"
+)
+
+// WatchConn logs an entry of l on every connectivity state transition of
+// cc, with its target, the state it left and the time it spent there, and
+// the state it entered: at warning level for TransientFailure, and at info
+// otherwise. It returns when ctx is done or cc shuts down, so run it in a
+// goroutine of its own:
+//
+//	go grpclogrus.WatchConn(ctx, cc, l)
+func WatchConn(ctx context.Context, cc *grpc.ClientConn, l *logrus.Entry) {
+	if l == nil {
+		l = logrus.WithFields(logrus.Fields{"source": "grpc"})
+	}
+	entry := l.WithField(TargetKey, cc.Target())
+	state, since := cc.GetState(), time.Now()
+	for state != connectivity.Shutdown && cc.WaitForStateChange(ctx, state) {
+		next, now := cc.GetState(), time.Now()
+		level := logrus.InfoLevel
+		if next == connectivity.TransientFailure {
+			level = logrus.WarnLevel
+		}
+		entry.WithFields(logrus.Fields{
+			parser.EventIDKey: "grpc.conn_state_changed",
+			FromStateKey:      state.String(),
+			StateKey:          next.String(),
+			TimeInStateKey:    now.Sub(since).String(),
+		}).Log(level, "connection state changed")
+		state, since = next, now
+	}
+}
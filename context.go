@@ -0,0 +1,27 @@
+package grpclogrus
+
+import (
+	"context"
+
+	"github.com/Sirupsen/logrus"
+)
+
+type entryContextKey struct{}
+
+// IntoContext stores entry in ctx, for FromContext. The server
+// interceptors store the entry of a call in the context of its handler,
+// with the fields they attached: method, peer, request id...
+func IntoContext(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, entryContextKey{}, entry)
+}
+
+// FromContext returns the entry stored in ctx by IntoContext, for
+// handlers to log with the fields of their call, or an entry of logrus'
+// standard logger if there's none. The entry carries ctx, for hooks.
+func FromContext(ctx context.Context) *logrus.Entry {
+	entry, ok := ctx.Value(entryContextKey{}).(*logrus.Entry)
+	if !ok {
+		entry = logrus.NewEntry(logrus.StandardLogger())
+	}
+	return entry.WithContext(ctx)
+}
@@ -0,0 +1,64 @@
+package grpclogrus
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+// Handler serves the rules of the parser, with their source, priority and
+// the number of messages they matched since the process started, along
+// with the counters published with expvar: as JSON when asked for with
+// the Accept header or ?format=json, as an HTML table otherwise. Mount it
+// under /debug/grpclogrus, e.g.
+//
+//	http.Handle("/debug/grpclogrus/", grpclogrus.Handler())
+func Handler() http.Handler {
+	return http.HandlerFunc(serveRules)
+}
+
+type rulesView struct {
+	Counters map[string]int64  `json:"counters"`
+	Rules    []parser.RuleInfo `json:"rules"`
+}
+
+func serveRules(w http.ResponseWriter, r *http.Request) {
+	v := rulesView{
+		Counters: map[string]int64{
+			MatchedVar:        Counter(MatchedVar),
+			UnmatchedVar:      Counter(UnmatchedVar),
+			RulePanicsVar:     int64(parser.RulePanics()),
+			UnknownFormatsVar: int64(parser.UnknownFormats()),
+			DroppedVar:        Counter(DroppedVar),
+			SampledOutVar:     Counter(SampledOutVar),
+		},
+		Rules: parser.Rules(),
+	}
+	if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = rulesPage.Execute(w, v)
+}
+
+var rulesPage = template.Must(template.New("rules").Parse(`<!DOCTYPE html>
+<html>
+<head><title>grpclogrus</title></head>
+<body>
+<h1>grpclogrus</h1>
+<table>
+{{range $name, $value := .Counters}}<tr><th align="left">{{$name}}</th><td>{{$value}}</td></tr>
+{{end}}</table>
+<h2>Rules</h2>
+<table>
+<tr><th>priority</th><th>id</th><th>format</th><th>level</th><th>source</th><th>arity</th><th>matches</th></tr>
+{{range .Rules}}<tr><td>{{.Priority}}</td><td>{{.ID}}</td><td><code>{{.Format}}</code></td><td>{{.Level}}</td><td>{{.Source}}</td><td>{{.Arity}}</td><td>{{.Matches}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
@@ -0,0 +1,48 @@
+package grpclogrus
+
+import (
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+// Fields added by Diagnosed: the id of the rule an entry matched, if any,
+// whether it matched one, and the pack of rules it was parsed with, that
+// of its rule or parser.RulePack.
+const (
+	RuleIDKey   = "grpclogrus.rule_id"
+	MatchedKey  = "grpclogrus.matched"
+	RulePackKey = "grpclogrus.rulepack"
+)
+
+// Diagnosed makes a Backend stamping entries with how they were parsed
+// before emitting them to next, for the quality of the parsing to be
+// measured from the logs themselves:
+//
+//	grpclogrus.Tee(grpclogrus.Diagnosed(grpclogrus.Logrus(l)))
+func Diagnosed(next Backend) Backend {
+	return &diagnosed{next: next}
+}
+
+type diagnosed struct {
+	next Backend
+}
+
+func (d *diagnosed) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	stamped := make(logrus.Fields, len(fields)+3)
+	for k, v := range fields {
+		stamped[k] = v
+	}
+	pack := parser.RulePack
+	id, matched := fields[parser.EventIDKey]
+	if matched {
+		stamped[RuleIDKey] = id
+		id, _ := id.(string)
+		template, _ := fields[parser.MsgTemplateKey].(string)
+		if source, ok := parser.RuleSource(id, template); ok && source != parser.SourceBuiltin {
+			pack = source
+		}
+	}
+	stamped[MatchedKey] = matched
+	stamped[RulePackKey] = pack
+	d.next.Emit(level, stamped, message)
+}
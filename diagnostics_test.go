@@ -0,0 +1,38 @@
+package grpclogrus_test
+
+import (
+	"testing"
+
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/logtest"
+	"github.com/aybabtme/grpclogrus/parser"
+	"google.golang.org/grpc/grpclog"
+)
+
+func TestDiagnosedRulePack(t *testing.T) {
+	parser.LoadPack(parser.Pack{Name: "test@diagnosed", Parsef: map[string]parser.Rule{
+		"test: %v failed": {ID: "test.failed", Level: parser.WarnLevel, Parse: func(args ...interface{}) (parser.Fields, string) {
+			return parser.Fields{"what": args[0]}, "failed"
+		}},
+	}})
+	t.Cleanup(func() { parser.UnloadPack("test@diagnosed") })
+
+	var captured logtest.Captured
+	g := grpclogrus.Tee(grpclogrus.Diagnosed(&captured)).(grpclog.LoggerV2)
+	g.Infof("test: %v failed", "dial")
+	g.Infof("grpc: Server.RegisterService found duplicate service registration for %q", "grpc.health.v1.Health")
+	g.Info("nothing knows of this")
+
+	entries := captured.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("want 3 entries, got %v", entries)
+	}
+	for i, want := range []string{"test@diagnosed", parser.RulePack, parser.RulePack} {
+		if got := entries[i].Fields[grpclogrus.RulePackKey]; got != want {
+			t.Errorf("%q: want the rule pack %q, got %v", entries[i].Message, want, got)
+		}
+	}
+	if entries[1].Fields[grpclogrus.MatchedKey] != true || entries[2].Fields[grpclogrus.MatchedKey] != false {
+		t.Errorf("want the builtin rule matched and the last entry not, got %v %v", entries[1].Fields, entries[2].Fields)
+	}
+}
@@ -0,0 +1,78 @@
+package grpclogrus
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+	"google.golang.org/grpc/grpclog"
+)
+
+// Environment variables read by NewFromEnv and OptionsFromEnv: grpc-go's
+// own, and those of this package.
+const (
+	// SeverityEnv is the level of grpclog's messages logged: info,
+	// warning or error, as for grpc-go's default logger.
+	SeverityEnv = "GRPC_GO_LOG_SEVERITY_LEVEL"
+	// VerbosityEnv is the verbosity reported by the Levels' V.
+	VerbosityEnv = "GRPC_GO_LOG_VERBOSITY_LEVEL"
+	// StrictEnv turns parser's strict mode on, when true.
+	StrictEnv = "GRPCLOGRUS_STRICT"
+	// MaxFieldLengthEnv truncates the values of fields longer than that
+	// many bytes.
+	MaxFieldLengthEnv = "GRPCLOGRUS_MAX_FIELD_LENGTH"
+	// SamplingEnv is the rate of WithSampling, between 0 and 1.
+	SamplingEnv = "GRPCLOGRUS_SAMPLING"
+	// FieldPrefixEnv is the prefix of WithFieldPrefix.
+	FieldPrefixEnv = "GRPCLOGRUS_FIELD_PREFIX"
+)
+
+// NewFromEnv makes a grpclog.Logger from a logrus.Entry configured as
+// grpc-go's default logger is, by the environment: the messages below
+// SeverityEnv, error when unset, are left out, through the Levels
+// returned along, which report the verbosity of VerbosityEnv and can be
+// changed at runtime. StrictEnv and MaxFieldLengthEnv are applied too.
+// Invalid values are ignored, as grpc-go does.
+func NewFromEnv(l *logrus.Entry) (grpclog.Logger, *Levels) {
+	var next Backend = Logrus(l)
+	if n, err := strconv.Atoi(os.Getenv(MaxFieldLengthEnv)); err == nil && n > 0 {
+		next = Truncated(next, n)
+	}
+	levels := NewLevels(next, severityFromEnv())
+	if v, err := strconv.Atoi(os.Getenv(VerbosityEnv)); err == nil {
+		levels.SetVerbosity(v)
+	}
+	if strict, err := strconv.ParseBool(os.Getenv(StrictEnv)); err == nil && strict {
+		parser.SetStrict(true, nil)
+	}
+	return Tee(levels), levels
+}
+
+func severityFromEnv() logrus.Level {
+	switch strings.ToLower(os.Getenv(SeverityEnv)) {
+	case "info":
+		return logrus.InfoLevel
+	case "warning":
+		return logrus.WarnLevel
+	}
+	return logrus.ErrorLevel
+}
+
+// OptionsFromEnv are the options of the interceptors and StatsHandler set
+// by the environment: SamplingEnv, MaxFieldLengthEnv and FieldPrefixEnv.
+// Invalid values are ignored.
+func OptionsFromEnv() []Option {
+	var opts []Option
+	if rate, err := strconv.ParseFloat(os.Getenv(SamplingEnv), 64); err == nil && rate >= 0 && rate <= 1 {
+		opts = append(opts, WithSampling(rate))
+	}
+	if n, err := strconv.Atoi(os.Getenv(MaxFieldLengthEnv)); err == nil && n > 0 {
+		opts = append(opts, WithMaxFieldLength(n))
+	}
+	if prefix := os.Getenv(FieldPrefixEnv); prefix != "" {
+		opts = append(opts, WithFieldPrefix(prefix))
+	}
+	return opts
+}
@@ -0,0 +1,65 @@
+package grpclogrus
+
+import (
+	"expvar"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+// Counters published with expvar under the "grpclogrus" map, shown by
+// /debug/vars: the entries structured from grpclog by a rule or not, the
+// rule panics of the parser, the messages of unknown formats in strict
+// mode, the entries backends dropped, and the calls left out by
+// WithSampling. The counters of DefaultRuleCounters are
+// published alongside them.
+const (
+	MatchedVar        = "matched"
+	UnmatchedVar      = "unmatched"
+	RulePanicsVar     = "rule_panics"
+	UnknownFormatsVar = "unknown_formats"
+	DroppedVar        = "dropped"
+	SampledOutVar     = "sampled_out"
+)
+
+var vars = expvar.NewMap("grpclogrus")
+
+func init() {
+	vars.Set(RulePanicsVar, expvar.Func(func() interface{} { return parser.RulePanics() }))
+	vars.Set(UnknownFormatsVar, expvar.Func(func() interface{} { return parser.UnknownFormats() }))
+	vars.Add(MatchedVar, 0)
+	vars.Add(UnmatchedVar, 0)
+	vars.Add(DroppedVar, 0)
+	vars.Add(SampledOutVar, 0)
+	for _, name := range RuleCounterNames() {
+		vars.Add(name, 0)
+	}
+}
+
+// Counter is the value of one of the counters published with expvar.
+func Counter(name string) int64 {
+	v, ok := vars.Get(name).(*expvar.Int)
+	if !ok {
+		return 0
+	}
+	return v.Value()
+}
+
+// CountDropped adds n entries a backend dropped to the dropped counter.
+func CountDropped(n int) {
+	vars.Add(DroppedVar, int64(n))
+}
+
+// countMatch counts an entry structured from grpclog as matched or not,
+// and toward its counter of DefaultRuleCounters.
+func countMatch(fields logrus.Fields) {
+	id, ok := fields[parser.EventIDKey].(string)
+	if !ok {
+		vars.Add(UnmatchedVar, 1)
+		return
+	}
+	vars.Add(MatchedVar, 1)
+	if name, ok := DefaultRuleCounters[id]; ok {
+		vars.Add(name, 1)
+	}
+}
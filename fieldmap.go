@@ -0,0 +1,192 @@
+package grpclogrus
+
+import (
+	"sort"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+// FieldMapping renames the fields of entries, for them to follow the
+// naming of a backend or schema, e.g. OTelFields. Apply it to the entries
+// of grpclog with Renamed, and to those of the interceptors with
+// WithFieldMapping.
+type FieldMapping struct {
+	// Names maps field names to the names they're renamed to. Fields not
+	// in Names keep theirs.
+	Names map[string]string
+	// Fields are added to every entry, as they are.
+	Fields logrus.Fields
+}
+
+// OTelFields names fields after OpenTelemetry's semantic conventions, for
+// logs to line up with the traces and metrics of OTel-native backends.
+var OTelFields = FieldMapping{
+	Names: map[string]string{
+		parser.EventIDKey: "event.name",
+		"package":         "code.namespace",
+		ServiceKey:        "rpc.service",
+		MethodKey:         "rpc.method",
+		CodeNumberKey:     "rpc.grpc.status_code",
+		PeerKey:           "net.peer.addr",
+		"addr":            "net.peer.addr",
+		PeerIPKey:         "net.sock.peer.addr",
+		PeerPortKey:       "net.peer.port",
+		TargetKey:         "server.address",
+		TLSVersionKey:     "tls.protocol.version",
+		TLSCipherKey:      "tls.cipher",
+		ErrKey:            "exception.message",
+		parser.StackKey:   "exception.stacktrace",
+	},
+	Fields: logrus.Fields{"rpc.system": "grpc"},
+}
+
+// ECSFields names fields after the Elastic Common Schema, whatever the
+// encoding of the backend: logrus' JSON, Kafka, Loki... Fields without an
+// ECS equivalent are nested under "grpc.", as grpcecs.Formatter does,
+// covering every field of the built-in rules: see Unmapped.
+var ECSFields = FieldMapping{
+	Names: map[string]string{
+		parser.EventIDKey:     "event.code",
+		parser.CategoryKey:    parser.CategoryKey,
+		parser.MsgTemplateKey: "grpc.msg_template",
+		"package":             "log.logger",
+		"source":              "event.module",
+		ErrKey:                "error.message",
+		parser.StackKey:       "error.stack_trace",
+		"addr":                "server.address",
+		TargetKey:             "server.address",
+		PeerKey:               "client.address",
+		PeerIPKey:             "client.ip",
+		PeerPortKey:           "client.port",
+		TLSVersionKey:         "tls.version",
+		TLSCipherKey:          "tls.cipher",
+		PeerCNKey:             "tls.client.subject",
+		RequestIDKey:          "http.request.id",
+		ServiceKey:            "grpc.service",
+		MethodKey:             "grpc.method",
+		CodeKey:               "grpc.code",
+		CodeNumberKey:         "grpc.code_number",
+		DurationKey:           "grpc.duration",
+		DurationMSKey:         "grpc.duration_ms",
+		parser.StreamIDKey:    "grpc.stream.id",
+		parser.ConnIDKey:      "grpc.conn.id",
+
+		// Fields of the component loggers of newer grpc-go versions.
+		parser.ComponentKey:       "grpc.component",
+		parser.ComponentPrefixKey: "grpc.component_prefix",
+
+		// Fields of the built-in rules.
+		"aggregated.size":     "grpc.aggregated.size",
+		"client":              "grpc.client",
+		"count":               "grpc.count",
+		"ctx":                 "grpc.ctx",
+		"expected.type":       "grpc.expected.type",
+		"f.Name":              "grpc.f.Name",
+		"f.Value":             "grpc.f.Value",
+		"found.type":          "grpc.found.type",
+		"frame":               "grpc.frame",
+		"got.code":            "grpc.got.code",
+		"got.count":           "grpc.got.count",
+		"got.len":             "grpc.got.len",
+		"got.length":          "grpc.got.length",
+		"got.reply":           "grpc.got.reply",
+		"got.result":          "grpc.got.result",
+		"got.scope":           "grpc.got.scope",
+		"got.size":            "grpc.got.size",
+		"got.type":            "grpc.got.type",
+		"got.user":            "grpc.got.user",
+		"id":                  "grpc.id",
+		"item.type":           "grpc.item.type",
+		"json.key":            "grpc.json.key",
+		"length":              "grpc.length",
+		"md":                  "grpc.md",
+		"message":             "grpc.message",
+		"point":               "grpc.point",
+		"point.latitude":      "grpc.point.latitude",
+		"point.longitude":     "grpc.point.longitude",
+		"preface":             "grpc.preface",
+		"rect":                "grpc.rect",
+		"reply":               "grpc.reply",
+		"request.size":        "grpc.request.size",
+		"s.ServerTransport()": "grpc.s.ServerTransport()",
+		"service.name":        "grpc.service.name",
+		"stream":              "grpc.stream",
+		"tc":                  "grpc.tc",
+		"test.case":           "grpc.test.case",
+		"type":                "grpc.type",
+		"user":                "grpc.user",
+		"want.code":           "grpc.want.code",
+		"want.count":          "grpc.want.count",
+		"want.err":            "grpc.want.err",
+		"want.len":            "grpc.want.len",
+		"want.length":         "grpc.want.length",
+		"want.reply":          "grpc.want.reply",
+		"want.result":         "grpc.want.result",
+		"want.scope":          "grpc.want.scope",
+		"want.size":           "grpc.want.size",
+		"want.type":           "grpc.want.type",
+		"want.user":           "grpc.want.user",
+	},
+}
+
+// Unmapped are the fields of the built-in rules m doesn't rename, sorted:
+// those a preset misses. The fields of the entries of every rule, such as
+// parser.EventIDKey, are included.
+func (m FieldMapping) Unmapped() []string {
+	seen := make(map[string]bool)
+	var unmapped []string
+	for _, r := range parser.Rules() {
+		if r.Source != parser.SourceBuiltin {
+			continue
+		}
+		for _, k := range append([]string{parser.EventIDKey, parser.CategoryKey, parser.MsgTemplateKey}, r.Fields...) {
+			if _, ok := m.Names[k]; ok || seen[k] {
+				continue
+			}
+			seen[k] = true
+			unmapped = append(unmapped, k)
+		}
+	}
+	sort.Strings(unmapped)
+	return unmapped
+}
+
+// WithFieldMapping renames the fields of the interceptors' entries by m.
+// Renamed fields aren't prefixed WithFieldPrefix.
+func WithFieldMapping(m FieldMapping) Option {
+	return func(o *options) { o.mapping = &m }
+}
+
+// Renamed makes a Backend renaming the fields of entries by m before
+// emitting them to next.
+func Renamed(next Backend, m FieldMapping) Backend {
+	return &renamed{next: next, m: m}
+}
+
+type renamed struct {
+	next Backend
+	m    FieldMapping
+}
+
+func (r *renamed) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	r.next.Emit(level, r.m.rename(fields, nil), message)
+}
+
+// rename returns a copy of fields renamed by m, and with its fields. The
+// fields not renamed are passed through other, when not nil.
+func (m *FieldMapping) rename(fields logrus.Fields, other func(string) string) logrus.Fields {
+	renamed := make(logrus.Fields, len(fields)+len(m.Fields))
+	for k, v := range m.Fields {
+		renamed[k] = v
+	}
+	for k, v := range fields {
+		if name, ok := m.Names[k]; ok {
+			k = name
+		} else if other != nil {
+			k = other(k)
+		}
+		renamed[k] = v
+	}
+	return renamed
+}
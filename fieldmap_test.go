@@ -0,0 +1,25 @@
+package grpclogrus_test
+
+import (
+	"testing"
+
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+func TestECSFieldsCoverBuiltinRules(t *testing.T) {
+	for _, r := range parser.Rules() {
+		if r.Source != parser.SourceBuiltin {
+			continue
+		}
+		keys := append([]string{parser.EventIDKey, parser.CategoryKey, parser.MsgTemplateKey}, r.Fields...)
+		for _, k := range keys {
+			if _, ok := grpclogrus.ECSFields.Names[k]; !ok {
+				t.Errorf("rule %s: field %q has no ECS name", r.ID, k)
+			}
+		}
+	}
+	if unmapped := grpclogrus.ECSFields.Unmapped(); len(unmapped) != 0 {
+		t.Errorf("want no unmapped field, got %v", unmapped)
+	}
+}
@@ -0,0 +1,66 @@
+package grpclogrus
+
+import (
+	"sort"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+// FieldOrder is the order SortFields puts the fields of this package in,
+// after those logrus' TextFormatter writes first: the event id and
+// category, the component, then the fields of calls. Other fields follow, sorted.
+var FieldOrder = []string{
+	parser.EventIDKey,
+	parser.CategoryKey,
+	parser.ComponentKey,
+	parser.PackageKey,
+	ServiceKey,
+	MethodKey,
+	CodeKey,
+	DurationKey,
+	PeerKey,
+	TargetKey,
+	RequestIDKey,
+	ErrKey,
+	parser.MsgTemplateKey,
+}
+
+// logrusKeys are the keys logrus' TextFormatter writes first, in order.
+var logrusKeys = []string{
+	logrus.FieldKeyTime,
+	logrus.FieldKeyLevel,
+	logrus.FieldKeyMsg,
+	logrus.FieldKeyLogrusError,
+	logrus.FieldKeyFunc,
+	logrus.FieldKeyFile,
+}
+
+// SortFields sorts keys in FieldOrder, for text output to be stable and
+// easy to diff. Use it as the SortingFunc of a logrus.TextFormatter, as
+// NewTextFormatter does.
+func SortFields(keys []string) {
+	rank := make(map[string]int, len(logrusKeys)+len(FieldOrder))
+	for i, k := range logrusKeys {
+		rank[k] = i + 1
+	}
+	for i, k := range FieldOrder {
+		rank[k] = len(logrusKeys) + i + 1
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ri, rj := rank[keys[i]], rank[keys[j]]
+		switch {
+		case ri != 0 && rj != 0:
+			return ri < rj
+		case ri != 0 || rj != 0:
+			return ri != 0
+		}
+		return keys[i] < keys[j]
+	})
+}
+
+// NewTextFormatter makes a logrus.TextFormatter writing fields in
+// FieldOrder.
+func NewTextFormatter() *logrus.TextFormatter {
+	return &logrus.TextFormatter{SortingFunc: SortFields}
+}
@@ -0,0 +1,115 @@
+/*
+Package grpcadmin configures the grpc logging of a process over grpc
+itself: Server serves the LogAdmin service of admin.proto, changing the
+levels of a grpclogrus.Levels, for fleets to be configured from grpcurl
+or a control plane.
+*/
+package grpcadmin
+
+//go:generate protoc --go_out=.. --go_opt=paths=source_relative --go-grpc_out=.. --go-grpc_opt=paths=source_relative -I.. ../grpcadmin/admin.proto
+
+import (
+	"context"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/parser"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server serves the LogAdmin service for levels; register it on a
+// grpc.Server with RegisterLogAdminServer.
+type Server struct {
+	UnimplementedLogAdminServer
+
+	levels *grpclogrus.Levels
+
+	// Authorize authorizes the calls changing the configuration, failing
+	// them with its error, as PermissionDenied unless it is a status
+	// already. They are all refused when nil. Set before use.
+	Authorize func(ctx context.Context, fullMethod string) error
+}
+
+// NewServer makes a Server configuring levels.
+func NewServer(levels *grpclogrus.Levels) *Server {
+	return &Server{levels: levels}
+}
+
+// GetConfig returns the levels and verbosity.
+func (s *Server) GetConfig(context.Context, *GetConfigRequest) (*Config, error) {
+	return s.config(), nil
+}
+
+// SetLevel sets the level of a component, or the overall level.
+func (s *Server) SetLevel(ctx context.Context, req *SetLevelRequest) (*Config, error) {
+	if err := s.authorize(ctx, LogAdmin_SetLevel_FullMethodName); err != nil {
+		return nil, err
+	}
+	if req.GetComponent() != "" && req.GetLevel() == "" {
+		s.levels.ClearComponentLevel(req.GetComponent())
+		return s.config(), nil
+	}
+	level, err := logrus.ParseLevel(req.GetLevel())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.GetComponent() == "" {
+		s.levels.SetLevel(level)
+	} else {
+		s.levels.SetComponentLevel(req.GetComponent(), level)
+	}
+	return s.config(), nil
+}
+
+// SetVerbosity sets the verbosity.
+func (s *Server) SetVerbosity(ctx context.Context, req *SetVerbosityRequest) (*Config, error) {
+	if err := s.authorize(ctx, LogAdmin_SetVerbosity_FullMethodName); err != nil {
+		return nil, err
+	}
+	s.levels.SetVerbosity(int(req.GetVerbosity()))
+	return s.config(), nil
+}
+
+// ListRules lists the rules of the parser.
+func (s *Server) ListRules(context.Context, *ListRulesRequest) (*ListRulesResponse, error) {
+	infos := parser.Rules()
+	resp := &ListRulesResponse{Rules: make([]*Rule, 0, len(infos))}
+	for _, r := range infos {
+		resp.Rules = append(resp.Rules, &Rule{
+			Id:       r.ID,
+			Format:   r.Format,
+			Level:    r.Level,
+			Source:   r.Source,
+			Priority: int32(r.Priority),
+			Matches:  r.Matches,
+		})
+	}
+	return resp, nil
+}
+
+func (s *Server) authorize(ctx context.Context, fullMethod string) error {
+	if s.Authorize == nil {
+		return status.Error(codes.PermissionDenied, "changing the configuration isn't authorized")
+	}
+	err := s.Authorize(ctx, fullMethod)
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(codes.PermissionDenied, err.Error())
+}
+
+func (s *Server) config() *Config {
+	c := &Config{
+		Level:      s.levels.Level().String(),
+		Verbosity:  int32(s.levels.Verbosity()),
+		Components: make(map[string]string),
+	}
+	for component, level := range s.levels.ComponentLevels() {
+		c.Components[component] = level.String()
+	}
+	return c
+}
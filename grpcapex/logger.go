@@ -0,0 +1,59 @@
+/*
+Package grpcapex emits the grpc-go logs structured by package parser
+as apex/log entries.
+*/
+package grpcapex
+
+import (
+	"github.com/apex/log"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/parser"
+
+	"google.golang.org/grpc/grpclog"
+)
+
+type logger struct {
+	l log.Interface
+}
+
+// New makes a grpclog.Logger from an apex log.Interface.
+func New(l log.Interface) grpclog.Logger {
+	if l == nil {
+		l = log.WithFields(log.Fields{"source": "grpc"})
+	}
+	return &logger{l: l}
+}
+
+// Inject an apex logger in grpclog.
+func Inject(l log.Interface) {
+	grpclogrus.SetLogger(New(l))
+}
+
+func (l *logger) Fatal(args ...interface{}) { l.fatal(parser.Parseln(args...)) }
+func (l *logger) Fatalf(format string, args ...interface{}) {
+	l.fatal(parser.Parsef(format, args...))
+}
+func (l *logger) Fatalln(args ...interface{}) { l.fatal(parser.Parseln(args...)) }
+func (l *logger) Print(args ...interface{})   { l.print(parser.Parseln(args...)) }
+func (l *logger) Printf(format string, args ...interface{}) {
+	l.print(parser.Parsef(format, args...))
+}
+func (l *logger) Println(args ...interface{}) { l.print(parser.Parseln(args...)) }
+
+func (l *logger) fatal(fields parser.Fields, message string, _ parser.Level) {
+	l.l.WithFields(log.Fields(fields)).Fatal(message)
+}
+
+func (l *logger) print(fields parser.Fields, message string, level parser.Level) {
+	entry := l.l.WithFields(log.Fields(fields))
+	switch level {
+	case parser.FatalLevel, parser.ErrorLevel:
+		entry.Error(message)
+	case parser.WarnLevel:
+		entry.Warn(message)
+	case parser.InfoLevel:
+		entry.Info(message)
+	default:
+		entry.Debug(message)
+	}
+}
@@ -0,0 +1,29 @@
+package grpcapex
+
+import (
+	"testing"
+
+	"github.com/apex/log"
+	"github.com/apex/log/handlers/memory"
+	"github.com/aybabtme/grpclogrus"
+
+	"google.golang.org/grpc/grpclog"
+)
+
+func TestInjectRestoredAfterNewTesting(t *testing.T) {
+	h := memory.New()
+	Inject(&log.Logger{Handler: h, Level: log.DebugLevel})
+	t.Cleanup(func() { grpclogrus.SetLogger(nil) })
+
+	t.Run("testing", func(t *testing.T) {
+		grpclogrus.NewTesting(t)
+		grpclog.Println("Server Address: ", "localhost:50051")
+	})
+	if len(h.Entries) != 0 {
+		t.Fatalf("want no entries logged to apex during the subtest, got %d", len(h.Entries))
+	}
+	grpclog.Println("Server Address: ", "localhost:50051")
+	if len(h.Entries) != 1 {
+		t.Fatalf("want the apex logger restored, got %d entries", len(h.Entries))
+	}
+}
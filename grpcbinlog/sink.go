@@ -0,0 +1,226 @@
+/*
+Package grpcbinlog is a grpc binarylog.Sink logging the events of the
+binary log of grpc as logrus entries.
+*/
+package grpcbinlog
+
+import (
+	"encoding/base64"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/parser"
+	"google.golang.org/grpc/binarylog"
+	binlogpb "google.golang.org/grpc/binarylog/grpc_binarylog_v1"
+	"google.golang.org/grpc/codes"
+)
+
+var _ binarylog.Sink = (*Sink)(nil)
+
+// Fields of the entries logged by the Sink. The call id and sequence
+// number identify an event among those of the process; the direction is
+// inbound or outbound, from the point of view of the side logging it.
+const (
+	CallIDKey        = "call_id"
+	SequenceKey      = "sequence"
+	DirectionKey     = "direction"
+	AuthorityKey     = "authority"
+	TruncatedKey     = "truncated"
+	StatusMessageKey = "status_message"
+	MetadataCountKey = "metadata_count"
+)
+
+// DefaultRedactedKeys are the metadata keys whose values the Sink
+// redacts, unless told otherwise.
+var DefaultRedactedKeys = []string{"authorization", "cookie", "set-cookie"}
+
+// Sink logs the events of the binary log as entries of its logger: the
+// headers, messages, half-closes, trailers and cancellations of the calls
+// of the process, with their method, call id, direction, message length
+// and metadata, under grpclogrus.MetadataPrefix. Trailers are logged at
+// the level of their code, the other events at debug level.
+//
+// grpc logs the methods selected by the GRPC_BINARY_LOG_FILTER
+// environment variable, in its standard syntax, e.g. "*" for all of them
+// or "pkg.Service/*,-pkg.Service/Health": install the sink in an init
+// function,
+//
+//	func init() { binarylog.SetSink(grpcbinlog.NewSink(l)) }
+type Sink struct {
+	l *logrus.Entry
+
+	// RedactedKeys are the metadata keys whose values are replaced with
+	// grpclogrus.Redacted. Set before use.
+	RedactedKeys []string
+
+	mu      sync.Mutex
+	methods map[uint64]string
+}
+
+// NewSink makes a Sink logging entries of l, redacting the
+// DefaultRedactedKeys.
+func NewSink(l *logrus.Entry) *Sink {
+	if l == nil {
+		l = logrus.WithFields(logrus.Fields{"source": "grpc"})
+	}
+	return &Sink{
+		l:            l,
+		RedactedKeys: DefaultRedactedKeys,
+		methods:      make(map[uint64]string),
+	}
+}
+
+// Write logs an event.
+func (s *Sink) Write(e *binlogpb.GrpcLogEntry) error {
+	fields := logrus.Fields{
+		CallIDKey:   e.GetCallId(),
+		SequenceKey: e.GetSequenceIdWithinCall(),
+	}
+	client := e.GetLogger() == binlogpb.GrpcLogEntry_LOGGER_CLIENT
+	if client {
+		fields[grpclogrus.SideKey] = "client"
+	} else {
+		fields[grpclogrus.SideKey] = "server"
+	}
+	if p := e.GetPeer(); p != nil {
+		fields[grpclogrus.PeerKey] = address(p)
+	}
+	if e.GetPayloadTruncated() {
+		fields[TruncatedKey] = true
+	}
+
+	level := logrus.DebugLevel
+	var eventID, message string
+	switch e.GetType() {
+	case binlogpb.GrpcLogEntry_EVENT_TYPE_CLIENT_HEADER:
+		h := e.GetClientHeader()
+		eventID, message = "grpc.binlog_client_header", "client header"
+		s.setMethod(e.GetCallId(), h.GetMethodName())
+		fields[AuthorityKey] = h.GetAuthority()
+		if t := h.GetTimeout(); t != nil {
+			fields[grpclogrus.DeadlineKey] = t.AsDuration().String()
+		}
+		s.metadata(fields, h.GetMetadata())
+		direction(fields, client)
+	case binlogpb.GrpcLogEntry_EVENT_TYPE_SERVER_HEADER:
+		eventID, message = "grpc.binlog_server_header", "server header"
+		s.metadata(fields, e.GetServerHeader().GetMetadata())
+		direction(fields, !client)
+	case binlogpb.GrpcLogEntry_EVENT_TYPE_CLIENT_MESSAGE:
+		eventID, message = "grpc.binlog_client_message", "client message"
+		fields[grpclogrus.LengthKey] = e.GetMessage().GetLength()
+		direction(fields, client)
+	case binlogpb.GrpcLogEntry_EVENT_TYPE_SERVER_MESSAGE:
+		eventID, message = "grpc.binlog_server_message", "server message"
+		fields[grpclogrus.LengthKey] = e.GetMessage().GetLength()
+		direction(fields, !client)
+	case binlogpb.GrpcLogEntry_EVENT_TYPE_CLIENT_HALF_CLOSE:
+		eventID, message = "grpc.binlog_client_half_close", "client half close"
+		direction(fields, client)
+	case binlogpb.GrpcLogEntry_EVENT_TYPE_SERVER_TRAILER:
+		t := e.GetTrailer()
+		eventID, message = "grpc.binlog_server_trailer", "server trailer"
+		code := codes.Code(t.GetStatusCode())
+		level = grpclogrus.DefaultCodeLevel(code)
+		fields[grpclogrus.CodeKey] = code.String()
+		if t.GetStatusMessage() != "" {
+			fields[StatusMessageKey] = t.GetStatusMessage()
+		}
+		s.metadata(fields, t.GetMetadata())
+		direction(fields, !client)
+	case binlogpb.GrpcLogEntry_EVENT_TYPE_CANCEL:
+		eventID, message = "grpc.binlog_cancel", "cancel"
+	default:
+		return nil
+	}
+
+	fullMethod := s.method(e.GetCallId())
+	switch e.GetType() {
+	case binlogpb.GrpcLogEntry_EVENT_TYPE_SERVER_TRAILER, binlogpb.GrpcLogEntry_EVENT_TYPE_CANCEL:
+		s.mu.Lock()
+		delete(s.methods, e.GetCallId())
+		s.mu.Unlock()
+	}
+	if fullMethod != "" {
+		service, method := path.Split(fullMethod)
+		fields[grpclogrus.ServiceKey] = strings.Trim(service, "/")
+		fields[grpclogrus.MethodKey] = method
+	}
+	fields[parser.EventIDKey] = eventID
+	s.l.WithFields(fields).Log(level, message)
+	return nil
+}
+
+// Close forgets the calls in progress.
+func (s *Sink) Close() error {
+	s.mu.Lock()
+	s.methods = make(map[uint64]string)
+	s.mu.Unlock()
+	return nil
+}
+
+// setMethod remembers the method of a call, which only its client header
+// carries.
+func (s *Sink) setMethod(id uint64, fullMethod string) {
+	s.mu.Lock()
+	s.methods[id] = fullMethod
+	s.mu.Unlock()
+}
+
+func (s *Sink) method(id uint64) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.methods[id]
+}
+
+// metadata adds the entries of md to fields.
+func (s *Sink) metadata(fields logrus.Fields, md *binlogpb.Metadata) {
+	entries := md.GetEntry()
+	fields[MetadataCountKey] = len(entries)
+	for _, e := range entries {
+		key := strings.ToLower(e.GetKey())
+		var v string
+		switch {
+		case s.redacted(key):
+			v = grpclogrus.Redacted
+		case strings.HasSuffix(key, "-bin"):
+			v = base64.StdEncoding.EncodeToString(e.GetValue())
+		default:
+			v = string(e.GetValue())
+		}
+		k := grpclogrus.MetadataPrefix + key
+		if prev, ok := fields[k]; ok {
+			v = prev.(string) + "," + v
+		}
+		fields[k] = v
+	}
+}
+
+func (s *Sink) redacted(key string) bool {
+	for _, k := range s.RedactedKeys {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func direction(fields logrus.Fields, outbound bool) {
+	if outbound {
+		fields[DirectionKey] = "outbound"
+	} else {
+		fields[DirectionKey] = "inbound"
+	}
+}
+
+func address(a *binlogpb.Address) string {
+	if a.GetType() == binlogpb.Address_TYPE_UNIX || a.GetIpPort() == 0 {
+		return a.GetAddress()
+	}
+	return net.JoinHostPort(a.GetAddress(), strconv.Itoa(int(a.GetIpPort())))
+}
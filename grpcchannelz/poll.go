@@ -0,0 +1,203 @@
+/*
+Package grpcchannelz logs periodic summaries of the channelz data of the
+process, for the health of its connections to be trended from its logs.
+*/
+package grpcchannelz
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/parser"
+	"google.golang.org/grpc"
+	channelzpb "google.golang.org/grpc/channelz/grpc_channelz_v1"
+	"google.golang.org/grpc/channelz/service"
+)
+
+// Fields of the entries logged by Poll.
+const (
+	ChannelsKey       = "channels"
+	SubchannelsKey    = "subchannels"
+	ServersKey        = "servers"
+	SocketsKey        = "sockets"
+	CallsStartedKey   = "calls_started"
+	CallsSucceededKey = "calls_succeeded"
+	CallsFailedKey    = "calls_failed"
+)
+
+// DefaultInterval is the interval of Poll when none is given.
+const DefaultInterval = time.Minute
+
+// Poll logs the channelz data of the process at every interval,
+// DefaultInterval when 0, until ctx is done: an entry of l per top channel
+// and per server, with its state, calls started, succeeded and failed, and
+// its subchannels and sockets, and an entry totalling them. Importing this
+// package turns channelz on, which records the data from then on.
+func Poll(ctx context.Context, interval time.Duration, l *logrus.Entry) {
+	if l == nil {
+		l = logrus.WithFields(logrus.Fields{"source": "grpc"})
+	}
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	var r registrar
+	service.RegisterChannelzServiceToServer(&r)
+	p := poller{cz: r.cz, l: l}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			p.poll(ctx)
+		}
+	}
+}
+
+// registrar gets the channelz service, to query it in-process.
+type registrar struct {
+	cz channelzpb.ChannelzServer
+}
+
+func (r *registrar) RegisterService(_ *grpc.ServiceDesc, impl interface{}) {
+	r.cz = impl.(channelzpb.ChannelzServer)
+}
+
+type poller struct {
+	cz channelzpb.ChannelzServer
+	l  *logrus.Entry
+}
+
+// totals of a poll.
+type totals struct {
+	channels, subchannels, servers, sockets int
+	started, succeeded, failed              int64
+}
+
+func (p *poller) poll(ctx context.Context) {
+	var t totals
+	if err := p.channels(ctx, &t); err != nil {
+		p.l.WithError(err).Error("can't get channelz channels")
+		return
+	}
+	if err := p.servers(ctx, &t); err != nil {
+		p.l.WithError(err).Error("can't get channelz servers")
+		return
+	}
+	p.l.WithFields(logrus.Fields{
+		parser.EventIDKey: "grpc.channelz_polled",
+		ChannelsKey:       t.channels,
+		SubchannelsKey:    t.subchannels,
+		ServersKey:        t.servers,
+		SocketsKey:        t.sockets,
+		CallsStartedKey:   t.started,
+		CallsSucceededKey: t.succeeded,
+		CallsFailedKey:    t.failed,
+	}).Info("channelz polled")
+}
+
+func (p *poller) channels(ctx context.Context, t *totals) error {
+	var start int64
+	for {
+		resp, err := p.cz.GetTopChannels(ctx, &channelzpb.GetTopChannelsRequest{StartChannelId: start})
+		if err != nil {
+			return err
+		}
+		for _, ch := range resp.GetChannel() {
+			start = ch.GetRef().GetChannelId() + 1
+			sockets := p.subchannelSockets(ctx, ch.GetSubchannelRef()) + len(ch.GetSocketRef())
+			data := ch.GetData()
+			t.channels++
+			t.subchannels += len(ch.GetSubchannelRef())
+			t.sockets += sockets
+			t.started += data.GetCallsStarted()
+			t.succeeded += data.GetCallsSucceeded()
+			t.failed += data.GetCallsFailed()
+			p.l.WithFields(logrus.Fields{
+				parser.EventIDKey:    "grpc.channelz_channel",
+				grpclogrus.TargetKey: data.GetTarget(),
+				grpclogrus.StateKey:  data.GetState().GetState().String(),
+				SubchannelsKey:       len(ch.GetSubchannelRef()),
+				SocketsKey:           sockets,
+				CallsStartedKey:      data.GetCallsStarted(),
+				CallsSucceededKey:    data.GetCallsSucceeded(),
+				CallsFailedKey:       data.GetCallsFailed(),
+			}).Info("channelz channel")
+		}
+		if resp.GetEnd() || len(resp.GetChannel()) == 0 {
+			return nil
+		}
+	}
+}
+
+// subchannelSockets counts the sockets of subchannels.
+func (p *poller) subchannelSockets(ctx context.Context, refs []*channelzpb.SubchannelRef) int {
+	var n int
+	for _, ref := range refs {
+		resp, err := p.cz.GetSubchannel(ctx, &channelzpb.GetSubchannelRequest{SubchannelId: ref.GetSubchannelId()})
+		if err != nil {
+			// The subchannel went away since the channel was read.
+			continue
+		}
+		n += len(resp.GetSubchannel().GetSocketRef())
+	}
+	return n
+}
+
+func (p *poller) servers(ctx context.Context, t *totals) error {
+	var start int64
+	for {
+		resp, err := p.cz.GetServers(ctx, &channelzpb.GetServersRequest{StartServerId: start})
+		if err != nil {
+			return err
+		}
+		for _, srv := range resp.GetServer() {
+			start = srv.GetRef().GetServerId() + 1
+			sockets, err := p.serverSockets(ctx, srv.GetRef().GetServerId())
+			if err != nil {
+				return err
+			}
+			data := srv.GetData()
+			t.servers++
+			t.sockets += sockets
+			t.started += data.GetCallsStarted()
+			t.succeeded += data.GetCallsSucceeded()
+			t.failed += data.GetCallsFailed()
+			p.l.WithFields(logrus.Fields{
+				parser.EventIDKey: "grpc.channelz_server",
+				SocketsKey:        sockets,
+				CallsStartedKey:   data.GetCallsStarted(),
+				CallsSucceededKey: data.GetCallsSucceeded(),
+				CallsFailedKey:    data.GetCallsFailed(),
+			}).Info("channelz server")
+		}
+		if resp.GetEnd() || len(resp.GetServer()) == 0 {
+			return nil
+		}
+	}
+}
+
+// serverSockets counts the sockets of a server: those it accepted.
+func (p *poller) serverSockets(ctx context.Context, id int64) (int, error) {
+	var (
+		n     int
+		start int64
+	)
+	for {
+		resp, err := p.cz.GetServerSockets(ctx, &channelzpb.GetServerSocketsRequest{ServerId: id, StartSocketId: start})
+		if err != nil {
+			return n, err
+		}
+		for _, ref := range resp.GetSocketRef() {
+			start = ref.GetSocketId() + 1
+			n++
+		}
+		if resp.GetEnd() || len(resp.GetSocketRef()) == 0 {
+			return n, nil
+		}
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Package grpccloudlogging formats logrus entries as the structured JSON
+Google Cloud Logging ingests from stdout, e.g. on GKE.
+*/
+package grpccloudlogging
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+// Special fields recognized by the logging agent.
+const (
+	labelsKey         = "logging.googleapis.com/labels"
+	sourceLocationKey = "logging.googleapis.com/sourceLocation"
+	traceKey          = "logging.googleapis.com/trace"
+	spanIDKey         = "logging.googleapis.com/spanId"
+)
+
+// LabelKeys are the fields sent as labels, which Cloud Logging indexes.
+// Other fields are part of the JSON payload.
+var LabelKeys = []string{parser.EventIDKey, parser.ComponentKey, parser.PackageKey}
+
+var _ logrus.Formatter = (*Formatter)(nil)
+
+// Formatter writes one Cloud Logging JSON entry per line.
+type Formatter struct {
+	// ProjectID qualifies trace ids, as Cloud Logging expects
+	// projects/PROJECT_ID/traces/TRACE_ID. Traces aren't correlated
+	// when it's empty.
+	ProjectID string
+	// TraceKey and SpanKey are the fields holding the trace and span
+	// ids, "trace.id" and "span.id" when empty.
+	TraceKey string
+	SpanKey  string
+}
+
+// Format an entry.
+func (f *Formatter) Format(e *logrus.Entry) ([]byte, error) {
+	doc := make(map[string]interface{}, 4+len(e.Data))
+	for k, v := range e.Data {
+		switch v := v.(type) {
+		case error:
+			doc[k] = v.Error()
+		case string, bool, int, int8, int16, int32, int64,
+			uint, uint8, uint16, uint32, uint64, float32, float64:
+			doc[k] = v
+		default:
+			doc[k] = fmt.Sprint(v)
+		}
+	}
+
+	labels := make(map[string]string, len(LabelKeys))
+	for _, k := range LabelKeys {
+		if v, ok := doc[k]; ok {
+			labels[k] = fmt.Sprint(v)
+			delete(doc, k)
+		}
+	}
+	if len(labels) > 0 {
+		doc[labelsKey] = labels
+	}
+
+	traceField, spanField := f.TraceKey, f.SpanKey
+	if traceField == "" {
+		traceField = "trace.id"
+	}
+	if spanField == "" {
+		spanField = "span.id"
+	}
+	if trace, ok := doc[traceField]; ok && f.ProjectID != "" {
+		doc[traceKey] = fmt.Sprintf("projects/%s/traces/%v", f.ProjectID, trace)
+		delete(doc, traceField)
+		if span, ok := doc[spanField]; ok {
+			doc[spanIDKey] = fmt.Sprint(span)
+			delete(doc, spanField)
+		}
+	}
+
+	if e.HasCaller() {
+		doc[sourceLocationKey] = map[string]string{
+			"file":     e.Caller.File,
+			"line":     strconv.Itoa(e.Caller.Line),
+			"function": e.Caller.Function,
+		}
+	}
+	doc["severity"] = severity(e.Level)
+	doc["message"] = e.Message
+	doc["time"] = e.Time.UTC().Format(time.RFC3339Nano)
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("grpccloudlogging: failed to marshal entry: %v", err)
+	}
+	return append(b, '\n'), nil
+}
+
+func severity(level logrus.Level) string {
+	switch level {
+	case logrus.PanicLevel:
+		return "ALERT"
+	case logrus.FatalLevel:
+		return "CRITICAL"
+	case logrus.ErrorLevel:
+		return "ERROR"
+	case logrus.WarnLevel:
+		return "WARNING"
+	case logrus.InfoLevel:
+		return "INFO"
+	default:
+		return "DEBUG"
+	}
+}
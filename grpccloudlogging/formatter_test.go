@@ -0,0 +1,92 @@
+package grpccloudlogging_test
+
+import (
+	"encoding/json"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/grpccloudlogging"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+func format(t *testing.T, f *grpccloudlogging.Formatter, e *logrus.Entry) map[string]interface{} {
+	t.Helper()
+	b, err := f.Format(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestFormat(t *testing.T) {
+	e := &logrus.Entry{
+		Time:    time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Level:   logrus.WarnLevel,
+		Message: "failed",
+		Data: logrus.Fields{
+			parser.EventIDKey: "grpc.transport.conn_failed",
+			parser.PackageKey: "transport",
+			"addr":            "10.0.0.1:443",
+			"trace.id":        "4bf92f3577b34da6a3ce929d0e0e4736",
+			"span.id":         "00f067aa0ba902b7",
+		},
+	}
+	doc := format(t, &grpccloudlogging.Formatter{ProjectID: "proj"}, e)
+	for k, v := range map[string]interface{}{
+		"severity":                      "WARNING",
+		"message":                       "failed",
+		"time":                          "2020-01-02T03:04:05Z",
+		"addr":                          "10.0.0.1:443",
+		"logging.googleapis.com/trace":  "projects/proj/traces/4bf92f3577b34da6a3ce929d0e0e4736",
+		"logging.googleapis.com/spanId": "00f067aa0ba902b7",
+	} {
+		if doc[k] != v {
+			t.Errorf("%s: want %v, got %v", k, v, doc[k])
+		}
+	}
+	labels, _ := doc["logging.googleapis.com/labels"].(map[string]interface{})
+	if labels[parser.EventIDKey] != "grpc.transport.conn_failed" || labels[parser.PackageKey] != "transport" {
+		t.Errorf("want the event id and package as labels, got %v", labels)
+	}
+	for _, k := range []string{parser.EventIDKey, parser.PackageKey, "trace.id", "span.id"} {
+		if _, ok := doc[k]; ok {
+			t.Errorf("want %s moved out of the payload", k)
+		}
+	}
+}
+
+func TestFormatWithoutProject(t *testing.T) {
+	e := &logrus.Entry{Level: logrus.DebugLevel, Data: logrus.Fields{"trace.id": "abc"}}
+	doc := format(t, &grpccloudlogging.Formatter{}, e)
+	if doc["trace.id"] != "abc" {
+		t.Errorf("want the trace id kept in the payload, got %v", doc)
+	}
+	if _, ok := doc["logging.googleapis.com/trace"]; ok {
+		t.Error("want traces not correlated without a project")
+	}
+	if _, ok := doc["logging.googleapis.com/labels"]; ok {
+		t.Error("want no labels without label fields")
+	}
+	if doc["severity"] != "DEBUG" {
+		t.Errorf("want DEBUG, got %v", doc["severity"])
+	}
+}
+
+func TestFormatSourceLocation(t *testing.T) {
+	l := logrus.New()
+	l.SetReportCaller(true)
+	e := logrus.NewEntry(l)
+	e.Level = logrus.ErrorLevel
+	e.Caller = &runtime.Frame{File: "server.go", Line: 42, Function: "grpc.(*Server).Serve"}
+	doc := format(t, &grpccloudlogging.Formatter{}, e)
+	loc, _ := doc["logging.googleapis.com/sourceLocation"].(map[string]interface{})
+	if loc["file"] != "server.go" || loc["line"] != "42" || loc["function"] != "grpc.(*Server).Serve" {
+		t.Errorf("unexpected source location %v", loc)
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Package grpcecs formats logrus entries as Elastic Common Schema JSON,
+translating the field names of package parser into ECS names.
+*/
+package grpcecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+// Version of ECS the output conforms to.
+const Version = "8.11.0"
+
+// Fields maps native field names to their ECS names. Fields without an
+// ECS equivalent are nested under "grpc.".
+var Fields = map[string]string{
+	parser.EventIDKey:  "event.code",
+	parser.CategoryKey: parser.CategoryKey,
+	"package":          "log.logger",
+	"err":              "error.message",
+	"addr":             "server.address",
+	"source":           "event.module",
+}
+
+var _ logrus.Formatter = (*Formatter)(nil)
+
+// Formatter writes one ECS JSON document per line.
+type Formatter struct{}
+
+// Format an entry.
+func (f *Formatter) Format(e *logrus.Entry) ([]byte, error) {
+	doc := make(map[string]interface{}, 4+len(e.Data))
+	for k, v := range e.Data {
+		name, ok := Fields[k]
+		if !ok {
+			name = "grpc." + k
+		}
+		switch v := v.(type) {
+		case error:
+			doc[name] = v.Error()
+		case string, bool, int, int8, int16, int32, int64,
+			uint, uint8, uint16, uint32, uint64, float32, float64:
+			doc[name] = v
+		default:
+			doc[name] = fmt.Sprint(v)
+		}
+	}
+	doc["@timestamp"] = e.Time.UTC().Format(time.RFC3339Nano)
+	doc["log.level"] = e.Level.String()
+	doc["message"] = e.Message
+	doc["ecs.version"] = Version
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("grpcecs: failed to marshal entry: %v", err)
+	}
+	return append(b, '\n'), nil
+}
@@ -0,0 +1,57 @@
+package grpcecs_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/grpcecs"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+func TestFormat(t *testing.T) {
+	e := &logrus.Entry{
+		Time:    time.Date(2020, 1, 2, 3, 4, 5, 6, time.FixedZone("EST", -5*3600)),
+		Level:   logrus.WarnLevel,
+		Message: "failed",
+		Data: logrus.Fields{
+			parser.EventIDKey: "grpc.transport.conn_failed",
+			"package":         "transport",
+			"err":             errors.New("connection refused"),
+			"backoff":         time.Second,
+			"attempt":         3,
+		},
+	}
+	b, err := new(grpcecs.Formatter).Format(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b[len(b)-1] != '\n' {
+		t.Errorf("want a document per line, got %q", b)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"@timestamp":    "2020-01-02T08:04:05.000000006Z",
+		"log.level":     "warning",
+		"message":       "failed",
+		"ecs.version":   grpcecs.Version,
+		"event.code":    "grpc.transport.conn_failed",
+		"log.logger":    "transport",
+		"error.message": "connection refused",
+		"grpc.backoff":  "1s",
+		"grpc.attempt":  3.0,
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: want %v, got %v", k, v, got[k])
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("want %d fields, got %v", len(want), got)
+	}
+}
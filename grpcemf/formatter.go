@@ -0,0 +1,111 @@
+/*
+Package grpcemf formats logrus entries in CloudWatch Embedded Metric
+Format, so that CloudWatch extracts metrics from the structured logs.
+*/
+package grpcemf
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+// Metric counts the entries it matches.
+type Metric struct {
+	Name string
+	// Dimensions are the fields the metric is broken down by. Entries
+	// lacking one of them don't count toward the metric.
+	Dimensions []string
+	Match      func(e *logrus.Entry) bool
+}
+
+// TransportErrors counts the Error and Fatal entries of grpc's transport,
+// by event id.
+var TransportErrors = Metric{
+	Name:       "TransportErrors",
+	Dimensions: []string{parser.EventIDKey},
+	Match: func(e *logrus.Entry) bool {
+		return parser.Component(parser.Fields(e.Data)) == "transport" && e.Level <= logrus.ErrorLevel
+	},
+}
+
+var _ logrus.Formatter = (*Formatter)(nil)
+
+// Formatter writes one JSON document per line. Documents of entries
+// matching a metric carry the metadata declaring it and a count of 1;
+// others are plain structured logs.
+type Formatter struct {
+	// Namespace of the metrics.
+	Namespace string
+	Metrics   []Metric
+}
+
+type directive struct {
+	Namespace  string             `json:"Namespace"`
+	Dimensions [][]string         `json:"Dimensions"`
+	Metrics    []metricDefinition `json:"Metrics"`
+}
+
+type metricDefinition struct {
+	Name string `json:"Name"`
+	Unit string `json:"Unit"`
+}
+
+// Format an entry.
+func (f *Formatter) Format(e *logrus.Entry) ([]byte, error) {
+	doc := make(map[string]interface{}, 4+len(e.Data))
+	for k, v := range e.Data {
+		switch v := v.(type) {
+		case error:
+			doc[k] = v.Error()
+		case string, bool, int, int8, int16, int32, int64,
+			uint, uint8, uint16, uint32, uint64, float32, float64:
+			doc[k] = v
+		default:
+			doc[k] = fmt.Sprint(v)
+		}
+	}
+	doc["level"] = e.Level.String()
+	doc["message"] = e.Message
+
+	var directives []directive
+	for _, m := range f.Metrics {
+		if !m.Match(e) || !hasAll(doc, m.Dimensions) {
+			continue
+		}
+		for _, dim := range m.Dimensions {
+			doc[dim] = fmt.Sprint(doc[dim])
+		}
+		doc[m.Name] = 1
+		directives = append(directives, directive{
+			Namespace:  f.Namespace,
+			Dimensions: [][]string{m.Dimensions},
+			Metrics:    []metricDefinition{{Name: m.Name, Unit: "Count"}},
+		})
+	}
+	if len(directives) > 0 {
+		doc["_aws"] = map[string]interface{}{
+			"Timestamp":         e.Time.UnixNano() / 1e6,
+			"CloudWatchMetrics": directives,
+		}
+	} else {
+		doc["timestamp"] = e.Time.UnixNano() / 1e6
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("grpcemf: failed to marshal entry: %v", err)
+	}
+	return append(b, '\n'), nil
+}
+
+func hasAll(doc map[string]interface{}, keys []string) bool {
+	for _, k := range keys {
+		if _, ok := doc[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
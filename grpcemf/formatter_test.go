@@ -0,0 +1,63 @@
+package grpcemf_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/grpcemf"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+func format(t *testing.T, level logrus.Level, fields logrus.Fields) map[string]interface{} {
+	t.Helper()
+	f := &grpcemf.Formatter{Namespace: "grpc", Metrics: []grpcemf.Metric{grpcemf.TransportErrors}}
+	e := &logrus.Entry{Time: time.Unix(1577934245, 0), Level: level, Message: "failed", Data: fields}
+	b, err := f.Format(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestFormatMetric(t *testing.T) {
+	doc := format(t, logrus.ErrorLevel, logrus.Fields{
+		parser.EventIDKey: "grpc.transport.conn_failed",
+		parser.PackageKey: "transport",
+	})
+	if doc["TransportErrors"] != 1.0 {
+		t.Errorf("want a count of 1, got %v", doc["TransportErrors"])
+	}
+	aws, ok := doc["_aws"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("want the metric metadata, got %v", doc)
+	}
+	if aws["Timestamp"] != 1577934245000.0 {
+		t.Errorf("want the timestamp in milliseconds, got %v", aws["Timestamp"])
+	}
+	b, _ := json.Marshal(aws["CloudWatchMetrics"])
+	want := `[{"Dimensions":[["event_id"]],"Metrics":[{"Name":"TransportErrors","Unit":"Count"}],"Namespace":"grpc"}]`
+	if string(b) != want {
+		t.Errorf("want\n%s\ngot\n%s", want, b)
+	}
+}
+
+func TestFormatUnmatched(t *testing.T) {
+	for name, doc := range map[string]map[string]interface{}{
+		"warning":         format(t, logrus.WarnLevel, logrus.Fields{parser.EventIDKey: "grpc.transport.conn_failed", parser.PackageKey: "transport"}),
+		"other component": format(t, logrus.ErrorLevel, logrus.Fields{parser.EventIDKey: "grpc.server.serve_failed", parser.PackageKey: "server"}),
+		"no dimension":    format(t, logrus.ErrorLevel, logrus.Fields{parser.PackageKey: "transport"}),
+	} {
+		if _, ok := doc["_aws"]; ok {
+			t.Errorf("%s: want no metric, got %v", name, doc)
+		}
+		if doc["timestamp"] != 1577934245000.0 || doc["message"] != "failed" {
+			t.Errorf("%s: want a plain structured log, got %v", name, doc)
+		}
+	}
+}
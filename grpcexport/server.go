@@ -0,0 +1,149 @@
+/*
+Package grpcexport exposes the structured grpc logs of a process over
+grpc itself: Server is a grpclogrus.Backend serving the LogExport service
+of export.proto, whose Subscribe call streams the entries to remote
+collectors, and Forward subscribes to it.
+*/
+package grpcexport
+
+//go:generate protoc --go_out=.. --go_opt=paths=source_relative --go-grpc_out=.. --go-grpc_opt=paths=source_relative -I.. ../grpcexport/export.proto
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/parser"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var _ grpclogrus.Backend = (*Server)(nil)
+
+// SubscriberBuffer is the number of entries buffered for each subscriber.
+// Entries are dropped for subscribers falling further behind, rather than
+// slowing down grpc.
+const SubscriberBuffer = 256
+
+// Server is a Backend streaming entries to the subscribers of the
+// LogExport service; register it on a grpc.Server with
+// RegisterLogExportServer. Entries emitted while no collector subscribes
+// are dropped.
+type Server struct {
+	UnimplementedLogExportServer
+
+	mu          sync.Mutex
+	subscribers map[chan *LogEntry]logrus.Level
+}
+
+// NewServer makes a Server without subscribers.
+func NewServer() *Server {
+	return &Server{subscribers: make(map[chan *LogEntry]logrus.Level)}
+}
+
+func (s *Server) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.subscribers) == 0 {
+		return
+	}
+	e := newLogEntry(time.Now(), level, fields, message)
+	for c, max := range s.subscribers {
+		if level > max {
+			continue
+		}
+		select {
+		case c <- e:
+		default:
+			grpclogrus.CountDropped(1)
+		}
+	}
+}
+
+// Subscribe streams the entries emitted from now on, until the call is
+// canceled.
+func (s *Server) Subscribe(req *SubscribeRequest, stream grpc.ServerStreamingServer[LogEntry]) error {
+	max := logrus.TraceLevel
+	if req.GetLevel() != Level_LEVEL_UNSPECIFIED {
+		max = logrusLevel(req.GetLevel())
+	}
+	c := make(chan *LogEntry, SubscriberBuffer)
+	s.mu.Lock()
+	s.subscribers[c] = max
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, c)
+		s.mu.Unlock()
+	}()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case e := <-c:
+			if err := stream.Send(e); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Forward subscribes to the LogExport service of cc, emitting the entries
+// of level or more severe to b, until ctx is done or the stream breaks.
+// Entries are emitted as they were, fields rendered as strings, Fatal
+// ones without exiting.
+func Forward(ctx context.Context, cc grpc.ClientConnInterface, level logrus.Level, b grpclogrus.Backend) error {
+	stream, err := NewLogExportClient(cc).Subscribe(ctx, &SubscribeRequest{Level: protoLevel(level)})
+	if err != nil {
+		return err
+	}
+	for {
+		e, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		fields := make(logrus.Fields, len(e.GetFields())+1)
+		for k, v := range e.GetFields() {
+			fields[k] = v
+		}
+		if id := e.GetEventId(); id != "" {
+			fields[parser.EventIDKey] = id
+		}
+		b.Emit(logrusLevel(e.GetLevel()), fields, e.GetMessage())
+	}
+}
+
+func newLogEntry(t time.Time, level logrus.Level, fields logrus.Fields, message string) *LogEntry {
+	e := &LogEntry{
+		Timestamp: timestamppb.New(t),
+		Level:     protoLevel(level),
+		Message:   message,
+		Fields:    make(map[string]string, len(fields)),
+	}
+	for k, v := range fields {
+		if k == parser.EventIDKey {
+			e.EventId = fmt.Sprint(v)
+			continue
+		}
+		e.Fields[k] = fmt.Sprint(v)
+	}
+	return e
+}
+
+// protoLevel converts a logrus level, the proto's levels being shifted by
+// one.
+func protoLevel(level logrus.Level) Level {
+	return Level(level + 1)
+}
+
+func logrusLevel(level Level) logrus.Level {
+	if level == Level_LEVEL_UNSPECIFIED {
+		return logrus.InfoLevel
+	}
+	return logrus.Level(level - 1)
+}
@@ -0,0 +1,95 @@
+package grpcexport
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type emitted struct {
+	level   logrus.Level
+	fields  logrus.Fields
+	message string
+}
+
+type recorder chan emitted
+
+func (r recorder) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	r <- emitted{level, fields, message}
+}
+
+func TestForward(t *testing.T) {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	s := NewServer()
+	RegisterLogExportServer(srv, s)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	cc, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cc.Close()
+
+	s.Emit(logrus.ErrorLevel, nil, "before subscribing")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rec := make(recorder, 4)
+	done := make(chan error, 1)
+	go func() { done <- Forward(ctx, cc, logrus.WarnLevel, rec) }()
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+		s.mu.Lock()
+		n := len(s.subscribers)
+		s.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("never subscribed")
+		}
+	}
+
+	s.Emit(logrus.InfoLevel, logrus.Fields{}, "filtered out")
+	s.Emit(logrus.WarnLevel, logrus.Fields{parser.EventIDKey: "grpc.transport.conn_failed", "code": 14}, "failed")
+	select {
+	case e := <-rec:
+		if e.level != logrus.WarnLevel || e.message != "failed" ||
+			e.fields[parser.EventIDKey] != "grpc.transport.conn_failed" || e.fields["code"] != "14" {
+			t.Errorf("unexpected entry %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("nothing forwarded")
+	}
+
+	cancel()
+	if err := <-done; err == nil {
+		t.Error("want Forward to return once canceled")
+	}
+	select {
+	case e := <-rec:
+		t.Errorf("unexpected entry %+v", e)
+	default:
+	}
+}
+
+func TestLevels(t *testing.T) {
+	for _, level := range logrus.AllLevels {
+		if got := logrusLevel(protoLevel(level)); got != level {
+			t.Errorf("want %v, got %v", level, got)
+		}
+	}
+	if got := logrusLevel(Level_LEVEL_UNSPECIFIED); got != logrus.InfoLevel {
+		t.Errorf("want info for an unspecified level, got %v", got)
+	}
+}
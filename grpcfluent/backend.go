@@ -0,0 +1,226 @@
+/*
+Package grpcfluent is a grpclogrus.Backend shipping entries to Fluentd or
+Fluent Bit over the forward protocol.
+*/
+package grpcfluent
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+)
+
+var _ grpclogrus.Backend = (*Backend)(nil)
+
+// Timeouts and backoff bounds of the connection to the aggregator.
+const (
+	DialTimeout  = 5 * time.Second
+	WriteTimeout = 5 * time.Second
+	AckTimeout   = 5 * time.Second
+	MinBackoff   = 100 * time.Millisecond
+	MaxBackoff   = 30 * time.Second
+)
+
+// Backend forwards entries to an aggregator in batches, each as a message
+// in the forward protocol's forward mode. The record of an entry holds
+// its fields along with "level" and "message"; fields by these names are
+// prefixed with "grpc.".
+type Backend struct {
+	// BatchSize is the most entries forwarded at once, and BatchWait the
+	// longest an entry waits to be forwarded. Set before use.
+	BatchSize int
+	BatchWait time.Duration
+
+	addr string
+	tag  string
+	ack  bool
+
+	once    sync.Once
+	batcher *grpclogrus.Batcher[[]byte]
+
+	mu       sync.Mutex
+	conn     net.Conn
+	r        *bufio.Reader
+	backoff  time.Duration
+	nextDial time.Time
+}
+
+// Dial connects to the aggregator at addr. With ack, every batch waits
+// for the aggregator to acknowledge it, and is resent once on a fresh
+// connection when it doesn't.
+func Dial(addr, tag string, ack bool) (*Backend, error) {
+	b := &Backend{
+		BatchSize: grpclogrus.DefaultBatchSize,
+		BatchWait: grpclogrus.DefaultBatchWait,
+		addr:      addr,
+		tag:       tag,
+		ack:       ack,
+	}
+	if err := b.connect(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *Backend) start() {
+	b.batcher = grpclogrus.NewBatcher(b.forward)
+	b.batcher.MaxSize = b.BatchSize
+	b.batcher.MaxWait = b.BatchWait
+}
+
+// Close forwards the pending entries and closes the connection to the
+// aggregator. Entries emitted afterwards are dropped.
+func (b *Backend) Close() error {
+	b.once.Do(b.start)
+	b.batcher.Close()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.conn == nil {
+		return nil
+	}
+	err := b.conn.Close()
+	b.conn = nil
+	return err
+}
+
+// Emit adds the entry to the batch, which is forwarded once full or once
+// BatchWait elapsed. Fatal entries close the backend, forwarding the
+// batch before grpc exits.
+func (b *Backend) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	b.once.Do(b.start)
+	b.batcher.Add(encodeEntry(time.Now(), level, fields, message))
+	if level <= logrus.FatalLevel {
+		b.Close()
+	}
+}
+
+// forward sends a batch. When the aggregator is unreachable, reconnection
+// is attempted with an exponential backoff, and batches are dropped in
+// the meantime.
+func (b *Backend) forward(batch [][]byte) {
+	var chunk string
+	if b.ack {
+		chunk = newChunkID()
+	}
+	msg := b.encode(batch, chunk)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for attempt := 0; attempt < 2; attempt++ {
+		if b.conn == nil {
+			if time.Now().Before(b.nextDial) {
+				break
+			}
+			if err := b.connect(); err != nil {
+				break
+			}
+		}
+		if err := b.send(msg, chunk); err == nil {
+			return
+		}
+		b.conn.Close()
+		b.conn = nil
+	}
+	grpclogrus.CountDropped(len(batch))
+}
+
+// connect must be called with mu held.
+func (b *Backend) connect() error {
+	conn, err := net.DialTimeout("tcp", b.addr, DialTimeout)
+	if err != nil {
+		switch {
+		case b.backoff == 0:
+			b.backoff = MinBackoff
+		case b.backoff < MaxBackoff:
+			b.backoff *= 2
+			if b.backoff > MaxBackoff {
+				b.backoff = MaxBackoff
+			}
+		}
+		b.nextDial = time.Now().Add(b.backoff)
+		return err
+	}
+	b.backoff = 0
+	b.conn = conn
+	b.r = bufio.NewReader(conn)
+	return nil
+}
+
+// send must be called with mu held.
+func (b *Backend) send(msg []byte, chunk string) error {
+	if err := b.conn.SetWriteDeadline(time.Now().Add(WriteTimeout)); err != nil {
+		return err
+	}
+	if _, err := b.conn.Write(msg); err != nil {
+		return err
+	}
+	if chunk == "" {
+		return nil
+	}
+	if err := b.conn.SetReadDeadline(time.Now().Add(AckTimeout)); err != nil {
+		return err
+	}
+	got, err := readAck(b.r)
+	if err != nil {
+		return err
+	}
+	if got != chunk {
+		return errors.New("grpcfluent: ack doesn't match the chunk sent")
+	}
+	return nil
+}
+
+// encode makes the forward mode message of a batch of encoded entries.
+func (b *Backend) encode(batch [][]byte, chunk string) []byte {
+	var e encoder
+	if chunk != "" {
+		e.arrayHeader(3)
+	} else {
+		e.arrayHeader(2)
+	}
+	e.string(b.tag)
+	e.arrayHeader(len(batch))
+	for _, entry := range batch {
+		e.buf = append(e.buf, entry...)
+	}
+	if chunk != "" {
+		e.mapHeader(1)
+		e.string("chunk")
+		e.string(chunk)
+	}
+	return e.buf
+}
+
+// encodeEntry encodes the time and record of an entry.
+func encodeEntry(now time.Time, level logrus.Level, fields logrus.Fields, message string) []byte {
+	var e encoder
+	e.arrayHeader(2)
+	e.eventTime(now)
+
+	e.mapHeader(2 + len(fields))
+	e.string("level")
+	e.string(level.String())
+	e.string("message")
+	e.string(message)
+	for k, v := range fields {
+		if k == "level" || k == "message" {
+			k = "grpc." + k
+		}
+		e.string(k)
+		e.value(v)
+	}
+	return e.buf
+}
+
+func newChunkID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return base64.StdEncoding.EncodeToString(id[:])
+}
@@ -0,0 +1,112 @@
+package grpcfluent
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+)
+
+func TestEncoderValue(t *testing.T) {
+	for _, tt := range []struct {
+		v    interface{}
+		want string
+	}{
+		{nil, "c0"},
+		{true, "c3"},
+		{"ab", "a26162"},
+		{5, "05"},
+		{-3, "fd"},
+		{int64(300), "d3000000000000012c"},
+		{uint(200), "cf00000000000000c8"},
+		{1.5, "cb3ff8000000000000"},
+		{struct{}{}, "a27b7d"},
+	} {
+		var e encoder
+		e.value(tt.v)
+		if got := hex.EncodeToString(e.buf); got != tt.want {
+			t.Errorf("%#v: want %s, got %s", tt.v, tt.want, got)
+		}
+	}
+}
+
+func TestReadAck(t *testing.T) {
+	var e encoder
+	e.mapHeader(1)
+	e.string("ack")
+	e.string("chunk-id")
+	chunk, err := readAck(bufio.NewReader(bytes.NewReader(e.buf)))
+	if err != nil || chunk != "chunk-id" {
+		t.Errorf("want the chunk acknowledged, got %q %v", chunk, err)
+	}
+	if _, err := readAck(bufio.NewReader(bytes.NewReader([]byte{0x90}))); err == nil {
+		t.Error("want an error for a response other than a map")
+	}
+}
+
+// ackServer acknowledges the chunk of every message it receives, sending
+// the messages on received.
+func ackServer(t *testing.T, received chan<- []byte) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lis.Close() })
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		// The chunk id, 24 bytes of base64, ends the message.
+		var msg []byte
+		i := -1
+		for i < 0 || len(msg) < i+7+24 {
+			buf := make([]byte, 64<<10)
+			n, err := conn.Read(buf)
+			if err != nil {
+				return
+			}
+			msg = append(msg, buf[:n]...)
+			i = bytes.LastIndex(msg, []byte("\xa5chunk"))
+		}
+		var e encoder
+		e.mapHeader(1)
+		e.string("ack")
+		e.string(string(msg[i+7:]))
+		conn.Write(e.buf)
+		received <- msg
+	}()
+	return lis.Addr().String()
+}
+
+func TestEmitAck(t *testing.T) {
+	received := make(chan []byte, 1)
+	b, err := Dial(ackServer(t, received), "grpc.test", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := grpclogrus.Counter(grpclogrus.DroppedVar)
+	b.Emit(logrus.WarnLevel, logrus.Fields{"peer": "10.0.0.1:443", "message": "collides"}, "failed")
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case msg := <-received:
+		for _, want := range []string{"\xa9grpc.test", "\xa7warning", "\xa6failed", "\xacgrpc.message", "\xac10.0.0.1:443"} {
+			if !bytes.Contains(msg, []byte(want)) {
+				t.Errorf("want %q in the message, got %q", want, msg)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("want the batch forwarded on Close")
+	}
+	if dropped := grpclogrus.Counter(grpclogrus.DroppedVar) - before; dropped != 0 {
+		t.Errorf("want the acknowledged batch not dropped, got %d dropped", dropped)
+	}
+}
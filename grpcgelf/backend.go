@@ -0,0 +1,245 @@
+/*
+Package grpcgelf is a grpclogrus.Backend sending GELF messages to
+Graylog, over chunked UDP or null-byte framed TCP.
+*/
+package grpcgelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+)
+
+var _ grpclogrus.Backend = (*Backend)(nil)
+
+// Compression of UDP messages. Graylog doesn't accept compressed
+// messages over TCP.
+type Compression int
+
+// Compressions supported by Graylog.
+const (
+	NoCompression Compression = iota
+	Gzip
+	Zlib
+)
+
+// ChunkSize defaults, depending on the network between the process and
+// Graylog.
+const (
+	ChunkSizeWAN = 1420
+	ChunkSizeLAN = 8154
+)
+
+// maxChunks is the most chunks Graylog reassembles into one message.
+const maxChunks = 128
+
+var chunkMagic = []byte{0x1e, 0x0f}
+
+// Backend sends entries to Graylog. Fields are sent as additional fields.
+// Over TCP, entries are sent in batches; over UDP, every entry is a
+// datagram of its own, sent as it is emitted.
+type Backend struct {
+	// Host is the source of the messages, the hostname by default.
+	Host string
+	// Compression of UDP messages, Gzip by default.
+	Compression Compression
+	// ChunkSize is the size of the chunks of UDP messages, ChunkSizeWAN by
+	// default.
+	ChunkSize int
+	// BatchSize is the most entries sent at once over TCP, and BatchWait
+	// the longest an entry waits to be sent, DefaultBatchSize and
+	// DefaultBatchWait of grpclogrus by default.
+	BatchSize int
+	BatchWait time.Duration
+
+	network string
+	once    sync.Once
+	batcher *grpclogrus.Batcher[[]byte]
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// Dial connects to a GELF input at addr, network being "udp" or "tcp".
+// Set Host, Compression, ChunkSize and the batching before the backend is
+// used.
+func Dial(network, addr string) (*Backend, error) {
+	switch network {
+	case "udp", "udp4", "udp6", "tcp", "tcp4", "tcp6":
+	default:
+		return nil, fmt.Errorf("grpcgelf: unsupported network %q", network)
+	}
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		return nil, err
+	}
+	host, _ := os.Hostname()
+	return &Backend{
+		Host:        host,
+		Compression: Gzip,
+		ChunkSize:   ChunkSizeWAN,
+		BatchSize:   grpclogrus.DefaultBatchSize,
+		BatchWait:   grpclogrus.DefaultBatchWait,
+		network:     network,
+		conn:        conn,
+	}, nil
+}
+
+func (b *Backend) start() {
+	b.batcher = grpclogrus.NewBatcher(b.writeTCP)
+	b.batcher.MaxSize = b.BatchSize
+	b.batcher.MaxWait = b.BatchWait
+}
+
+// Close sends the pending entries and closes the connection to Graylog.
+func (b *Backend) Close() error {
+	b.once.Do(b.start)
+	b.batcher.Close()
+	return b.conn.Close()
+}
+
+// Emit sends the entry over UDP, or adds it to the batch over TCP, which
+// is sent once full or once BatchWait elapsed. Fatal entries close the
+// backend, sending the batch before grpc exits. Entries that can't be
+// sent are dropped.
+func (b *Backend) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	msg, err := b.encode(time.Now(), level, fields, message)
+	if err != nil {
+		return
+	}
+	if b.isUDP() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		_ = b.writeUDP(msg)
+		return
+	}
+	b.once.Do(b.start)
+	b.batcher.Add(msg)
+	if level <= logrus.FatalLevel {
+		b.Close()
+	}
+}
+
+// writeTCP sends a batch in one write, null byte framed.
+func (b *Backend) writeTCP(batch [][]byte) {
+	var n int
+	for _, msg := range batch {
+		n += len(msg) + 1
+	}
+	buf := make([]byte, 0, n)
+	for _, msg := range batch {
+		buf = append(append(buf, msg...), 0)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, err := b.conn.Write(buf); err != nil {
+		grpclogrus.CountDropped(len(batch))
+	}
+}
+
+func (b *Backend) isUDP() bool {
+	return b.network[:3] == "udp"
+}
+
+func (b *Backend) encode(now time.Time, level logrus.Level, fields logrus.Fields, message string) ([]byte, error) {
+	m := make(map[string]interface{}, 5+len(fields))
+	for k, v := range fields {
+		switch v.(type) {
+		case string, bool, int, int8, int16, int32, int64,
+			uint, uint8, uint16, uint32, uint64, float32, float64:
+		default:
+			v = fmt.Sprint(v)
+		}
+		m[additionalField(k)] = v
+	}
+	m["version"] = "1.1"
+	m["host"] = b.Host
+	m["short_message"] = message
+	m["timestamp"] = float64(now.UnixNano()) / float64(time.Second)
+	m["level"] = grpclogrus.SyslogSeverity(level)
+	msg, err := json.Marshal(m)
+	if err != nil || !b.isUDP() {
+		return msg, err
+	}
+
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch b.Compression {
+	case Gzip:
+		w = gzip.NewWriter(&buf)
+	case Zlib:
+		w = zlib.NewWriter(&buf)
+	default:
+		return msg, nil
+	}
+	if _, err := w.Write(msg); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeUDP chunks messages larger than ChunkSize.
+func (b *Backend) writeUDP(msg []byte) error {
+	if len(msg) <= b.ChunkSize {
+		_, err := b.conn.Write(msg)
+		return err
+	}
+	dataSize := b.ChunkSize - 12
+	count := (len(msg) + dataSize - 1) / dataSize
+	if count > maxChunks {
+		return errors.New("grpcgelf: message too large")
+	}
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return err
+	}
+	chunk := make([]byte, 0, b.ChunkSize)
+	for i := 0; i < count; i++ {
+		end := (i + 1) * dataSize
+		if end > len(msg) {
+			end = len(msg)
+		}
+		chunk = append(chunk[:0], chunkMagic...)
+		chunk = append(chunk, id[:]...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, msg[i*dataSize:end]...)
+		if _, err := b.conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// additionalField prefixes k with an underscore, replacing the
+// characters GELF doesn't allow in field names with '_'. Graylog
+// reserves _id, so it's renamed.
+func additionalField(k string) string {
+	name := []byte("_" + k)
+	for i, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9',
+			c == '_', c == '.', c == '-':
+		default:
+			name[i] = '_'
+		}
+	}
+	if string(name) == "_id" {
+		return "_grpc.id"
+	}
+	return string(name)
+}
@@ -0,0 +1,172 @@
+package grpcgelf
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+func TestAdditionalField(t *testing.T) {
+	for k, want := range map[string]string{
+		"grpc.method": "_grpc.method",
+		"a b/c":       "_a_b_c",
+		"id":          "_grpc.id",
+	} {
+		if got := additionalField(k); got != want {
+			t.Errorf("%q: want %q, got %q", k, want, got)
+		}
+	}
+}
+
+func TestEmitUDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+	b, err := Dial("udp", pc.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	b.Host = "host"
+	b.Emit(logrus.WarnLevel, logrus.Fields{"peer": "10.0.0.1:443", "code": 14}, "failed")
+
+	buf := make([]byte, 64<<10)
+	pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(buf[:n]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(msg, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["short_message"] != "failed" || got["host"] != "host" || got["level"] != 4.0 ||
+		got["_peer"] != "10.0.0.1:443" || got["_code"] != 14.0 {
+		t.Errorf("unexpected message %s", msg)
+	}
+}
+
+func TestEmitUDPChunked(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+	b, err := Dial("udp", pc.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	b.Compression = NoCompression
+	b.ChunkSize = 64
+	b.Emit(logrus.InfoLevel, nil, string(bytes.Repeat([]byte("x"), 300)))
+
+	var msg []byte
+	var id []byte
+	pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for i := 0; ; i++ {
+		buf := make([]byte, 1024)
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		chunk := buf[:n]
+		if n > b.ChunkSize {
+			t.Fatalf("chunk of %d bytes, larger than %d", n, b.ChunkSize)
+		}
+		if !bytes.Equal(chunk[:2], chunkMagic) {
+			t.Fatalf("missing the chunk magic: %x", chunk[:2])
+		}
+		if id == nil {
+			id = chunk[2:10]
+		} else if !bytes.Equal(chunk[2:10], id) {
+			t.Fatalf("chunk %d has another message id", i)
+		}
+		if int(chunk[10]) != i {
+			t.Fatalf("want chunk %d, got %d", i, chunk[10])
+		}
+		msg = append(msg, chunk[12:]...)
+		if int(chunk[11]) == i+1 {
+			break
+		}
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(msg, &got); err != nil {
+		t.Fatalf("reassembled message: %v", err)
+	}
+	if len(got["short_message"].(string)) != 300 {
+		t.Errorf("unexpected message %s", msg)
+	}
+}
+
+func TestEmitTCP(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var msgs []string
+		r := bufio.NewReader(conn)
+		for {
+			msg, err := r.ReadBytes(0)
+			if err != nil {
+				received <- msgs
+				return
+			}
+			msgs = append(msgs, string(msg[:len(msg)-1]))
+		}
+	}()
+
+	b, err := Dial("tcp", lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.BatchWait = time.Hour
+	b.Emit(logrus.InfoLevel, nil, "first")
+	b.Emit(logrus.ErrorLevel, nil, "second")
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msgs := <-received:
+		if len(msgs) != 2 {
+			t.Fatalf("want 2 messages sent on close, got %q", msgs)
+		}
+		for i, want := range []string{"first", "second"} {
+			var got map[string]interface{}
+			if err := json.Unmarshal([]byte(msgs[i]), &got); err != nil {
+				t.Fatalf("message %d is not uncompressed JSON: %v", i, err)
+			}
+			if got["short_message"] != want {
+				t.Errorf("want %q, got %q", want, got["short_message"])
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("nothing received")
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Package grpcgokit emits the grpc-go logs structured by package parser
+as go-kit key/value pairs.
+
+Entries carry a level key compatible with go-kit's level package, so
+they can be filtered with level.NewFilter.
+*/
+package grpcgokit
+
+import (
+	"os"
+	"sort"
+
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/parser"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"google.golang.org/grpc/grpclog"
+)
+
+type logger struct {
+	l log.Logger
+}
+
+// New makes a grpclog.Logger from a go-kit log.Logger.
+func New(l log.Logger) grpclog.Logger {
+	if l == nil {
+		l = log.With(log.NewLogfmtLogger(log.NewSyncWriter(os.Stderr)), "source", "grpc")
+	}
+	return &logger{l: l}
+}
+
+// Inject a go-kit logger in grpclog.
+func Inject(l log.Logger) {
+	grpclogrus.SetLogger(New(l))
+}
+
+func (l *logger) Fatal(args ...interface{}) { l.fatal(parser.Parseln(args...)) }
+func (l *logger) Fatalf(format string, args ...interface{}) {
+	l.fatal(parser.Parsef(format, args...))
+}
+func (l *logger) Fatalln(args ...interface{}) { l.fatal(parser.Parseln(args...)) }
+func (l *logger) Print(args ...interface{})   { l.print(parser.Parseln(args...)) }
+func (l *logger) Printf(format string, args ...interface{}) {
+	l.print(parser.Parsef(format, args...))
+}
+func (l *logger) Println(args ...interface{}) { l.print(parser.Parseln(args...)) }
+
+// fatal logs at Error, go-kit having no fatal level, then exits.
+func (l *logger) fatal(fields parser.Fields, message string, _ parser.Level) {
+	l.log(level.Error(l.l), fields, message)
+	os.Exit(1)
+}
+
+func (l *logger) print(fields parser.Fields, message string, lvl parser.Level) {
+	switch lvl {
+	case parser.FatalLevel, parser.ErrorLevel:
+		l.log(level.Error(l.l), fields, message)
+	case parser.WarnLevel:
+		l.log(level.Warn(l.l), fields, message)
+	case parser.InfoLevel:
+		l.log(level.Info(l.l), fields, message)
+	default:
+		l.log(level.Debug(l.l), fields, message)
+	}
+}
+
+// log emits the message followed by the fields, sorted by key so that
+// the output is stable.
+func (l *logger) log(kl log.Logger, fields parser.Fields, message string) {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	keyvals := make([]interface{}, 0, 2+2*len(keys))
+	keyvals = append(keyvals, "msg", message)
+	for _, k := range keys {
+		keyvals = append(keyvals, k, fields[k])
+	}
+	_ = kl.Log(keyvals...)
+}
@@ -0,0 +1,29 @@
+package grpcgokit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aybabtme/grpclogrus"
+	"github.com/go-kit/log"
+
+	"google.golang.org/grpc/grpclog"
+)
+
+func TestInjectRestoredAfterNewTesting(t *testing.T) {
+	var buf bytes.Buffer
+	Inject(log.NewLogfmtLogger(&buf))
+	t.Cleanup(func() { grpclogrus.SetLogger(nil) })
+
+	t.Run("testing", func(t *testing.T) {
+		grpclogrus.NewTesting(t)
+		grpclog.Println("Server Address: ", "localhost:50051")
+	})
+	if buf.Len() != 0 {
+		t.Fatalf("want nothing logged to go-kit during the subtest, got %q", buf.String())
+	}
+	grpclog.Println("Server Address: ", "localhost:50051")
+	if buf.Len() == 0 {
+		t.Fatal("want the go-kit logger restored")
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Package grpcjournald is a grpclogrus.Backend sending entries to
+systemd-journald over its native protocol, with the parsed fields as
+journal fields.
+*/
+package grpcjournald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+)
+
+var _ grpclogrus.Backend = (*Backend)(nil)
+
+// SocketPath is where journald listens for native protocol datagrams.
+const SocketPath = "/run/systemd/journal/socket"
+
+// Backend sends entries to journald.
+type Backend struct {
+	identifier string
+	conn       *net.UnixConn
+}
+
+// New connects to the local journald. An empty identifier defaults to
+// the program's name.
+func New(identifier string) (*Backend, error) {
+	if identifier == "" {
+		identifier = filepath.Base(os.Args[0])
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: SocketPath, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return &Backend{identifier: identifier, conn: conn}, nil
+}
+
+// Close the connection to journald.
+func (b *Backend) Close() error {
+	return b.conn.Close()
+}
+
+// Emit sends the entry. Fields are uppercased, and those colliding with
+// the fields set by the backend are prefixed with GRPC_. Entries that
+// can't be sent are dropped.
+func (b *Backend) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	var buf bytes.Buffer
+	writeField(&buf, "MESSAGE", message)
+	writeField(&buf, "PRIORITY", strconv.Itoa(grpclogrus.SyslogSeverity(level)))
+	writeField(&buf, "SYSLOG_IDENTIFIER", b.identifier)
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		writeField(&buf, fieldName(k), fmt.Sprint(fields[k]))
+	}
+	_ = send(b.conn, buf.Bytes())
+}
+
+// fieldName makes a valid journal field name of k: uppercase letters,
+// digits and underscores, not starting with a digit or an underscore
+// (which are reserved to trusted fields), at most 64 characters.
+func fieldName(k string) string {
+	name := []byte(strings.ToUpper(k))
+	for i, c := range name {
+		if !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') {
+			name[i] = '_'
+		}
+	}
+	switch s := string(name); {
+	case s == "", s[0] == '_', s[0] >= '0' && s[0] <= '9',
+		s == "MESSAGE", s == "PRIORITY", s == "SYSLOG_IDENTIFIER":
+		name = append([]byte("GRPC_"), name...)
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return string(name)
+}
+
+// writeField uses the binary form for values spanning several lines.
+func writeField(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(name)
+	if !strings.Contains(value, "\n") {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+	buf.WriteByte('\n')
+	_ = binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,61 @@
+//go:build !windows
+
+package grpcjournald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+)
+
+func TestFieldName(t *testing.T) {
+	for k, want := range map[string]string{
+		"peer":         "PEER",
+		"service.name": "SERVICE_NAME",
+		"_hidden":      "GRPC__HIDDEN",
+		"2xx":          "GRPC_2XX",
+		"message":      "GRPC_MESSAGE",
+		"priority":     "GRPC_PRIORITY",
+	} {
+		if got := fieldName(k); got != want {
+			t.Errorf("%q: want %q, got %q", k, want, got)
+		}
+	}
+	if got := fieldName(strings.Repeat("a", 70)); got != strings.Repeat("A", 64) {
+		t.Errorf("want long names cut to 64 characters, got %q", got)
+	}
+}
+
+func TestEmit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "socket")
+	lis, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &Backend{identifier: "test", conn: conn}
+	defer b.Close()
+
+	b.Emit(logrus.WarnLevel, logrus.Fields{"peer": "10.0.0.1:443", "stack": "a\nb"}, "failed")
+	buf := make([]byte, 4096)
+	n, err := lis.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want bytes.Buffer
+	want.WriteString("MESSAGE=failed\nPRIORITY=4\nSYSLOG_IDENTIFIER=test\nPEER=10.0.0.1:443\nSTACK\n")
+	binary.Write(&want, binary.LittleEndian, uint64(3))
+	want.WriteString("a\nb\n")
+	if got := buf[:n]; !bytes.Equal(got, want.Bytes()) {
+		t.Errorf("want\n%q\ngot\n%q", want.Bytes(), got)
+	}
+}
@@ -0,0 +1,36 @@
+//go:build !windows
+
+package grpcjournald
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+)
+
+// send writes data as one datagram, falling back to passing a file
+// descriptor holding it when it's too large for a datagram.
+func send(conn *net.UnixConn, data []byte) error {
+	_, err := conn.Write(data)
+	if err == nil || !isTooLarge(err) {
+		return err
+	}
+	f, err := os.CreateTemp("/dev/shm", "grpcjournald.*")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := os.Remove(f.Name()); err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	_, _, err = conn.WriteMsgUnix(nil, syscall.UnixRights(int(f.Fd())), nil)
+	return err
+}
+
+func isTooLarge(err error) bool {
+	return errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS)
+}
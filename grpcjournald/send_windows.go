@@ -0,0 +1,10 @@
+package grpcjournald
+
+import (
+	"errors"
+	"net"
+)
+
+func send(conn *net.UnixConn, data []byte) error {
+	return errors.New("grpcjournald: journald isn't available on windows")
+}
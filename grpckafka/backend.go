@@ -0,0 +1,107 @@
+/*
+Package grpckafka is a grpclogrus.Backend publishing entries to a Kafka
+topic, as JSON.
+*/
+package grpckafka
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/segmentio/kafka-go"
+)
+
+var _ grpclogrus.Backend = (*Backend)(nil)
+
+// Defaults of the batching of the Writer made by New.
+const (
+	DefaultBatchSize    = 100
+	DefaultBatchTimeout = time.Second
+)
+
+// Backend publishes entries to Kafka, each as a message holding the JSON
+// of the entry, as logrus' JSONFormatter renders it. Messages are keyed
+// by the first of KeyFields an entry has, so that entries of the same
+// event, or of the same peer, land in the same partition, in order.
+type Backend struct {
+	// Writer publishes the messages, asynchronously and in batches. Tune
+	// its batching and acks before use; its Completion is set by New.
+	Writer *kafka.Writer
+	// KeyFields are the fields keying messages, event_id by default. Use
+	// e.g. addr to partition by peer. Entries with none of them are
+	// spread across partitions. Set before use.
+	KeyFields []string
+	// OnError is called, from the Writer's goroutines, with the messages
+	// that couldn't be delivered, once the Writer gave up retrying. Set
+	// before use.
+	OnError func(err error, messages []kafka.Message)
+
+	formatter logrus.JSONFormatter
+	dropped   int64
+}
+
+// New makes a Backend publishing to the topic of the brokers.
+func New(brokers []string, topic string) *Backend {
+	b := &Backend{KeyFields: []string{"event_id"}}
+	b.Writer = &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		BatchSize:    DefaultBatchSize,
+		BatchTimeout: DefaultBatchTimeout,
+		Async:        true,
+		Completion:   b.completion,
+	}
+	return b
+}
+
+// Emit queues the entry for publication. Fatal entries close the
+// Writer, publishing the queued entries before grpc exits.
+func (b *Backend) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	value, err := b.formatter.Format(&logrus.Entry{
+		Data:    fields,
+		Time:    time.Now(),
+		Level:   level,
+		Message: message,
+	})
+	if err != nil {
+		return
+	}
+	msg := kafka.Message{Value: bytes.TrimRight(value, "\n")}
+	for _, k := range b.KeyFields {
+		if v, ok := fields[k]; ok {
+			msg.Key = []byte(fmt.Sprint(v))
+			break
+		}
+	}
+	_ = b.Writer.WriteMessages(context.Background(), msg)
+	if level <= logrus.FatalLevel {
+		b.Close()
+	}
+}
+
+func (b *Backend) completion(messages []kafka.Message, err error) {
+	if err == nil {
+		return
+	}
+	atomic.AddInt64(&b.dropped, int64(len(messages)))
+	grpclogrus.CountDropped(len(messages))
+	if b.OnError != nil {
+		b.OnError(err, messages)
+	}
+}
+
+// Dropped is the number of entries that couldn't be delivered so far.
+func (b *Backend) Dropped() int64 {
+	return atomic.LoadInt64(&b.dropped)
+}
+
+// Close publishes the queued entries and closes the Writer.
+func (b *Backend) Close() error {
+	return b.Writer.Close()
+}
@@ -0,0 +1,122 @@
+package grpckafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/protocol"
+	"github.com/segmentio/kafka-go/protocol/metadata"
+	"github.com/segmentio/kafka-go/protocol/produce"
+)
+
+// broker is a kafka.RoundTripper of a topic of 2 partitions, recording
+// the messages produced, or failing every produce with err.
+type broker struct {
+	err error
+
+	mu       sync.Mutex
+	messages map[int32][]kafka.Message
+}
+
+func (b *broker) RoundTrip(_ context.Context, _ net.Addr, req kafka.Request) (kafka.Response, error) {
+	switch req := req.(type) {
+	case *metadata.Request:
+		return &metadata.Response{Topics: []metadata.ResponseTopic{{
+			Name:       req.TopicNames[0],
+			Partitions: []metadata.ResponsePartition{{PartitionIndex: 0}, {PartitionIndex: 1}},
+		}}}, nil
+	case *produce.Request:
+		if b.err != nil {
+			return nil, b.err
+		}
+		p := req.Topics[0].Partitions[0]
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for {
+			r, err := p.RecordSet.Records.ReadRecord()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			key, _ := protocol.ReadAll(r.Key)
+			value, _ := protocol.ReadAll(r.Value)
+			b.messages[p.Partition] = append(b.messages[p.Partition], kafka.Message{Key: key, Value: value})
+		}
+		return &produce.Response{Topics: []produce.ResponseTopic{{
+			Topic:      req.Topics[0].Topic,
+			Partitions: []produce.ResponsePartition{{Partition: p.Partition}},
+		}}}, nil
+	}
+	return nil, errors.New("unexpected request")
+}
+
+func TestEmit(t *testing.T) {
+	br := &broker{messages: make(map[int32][]kafka.Message)}
+	b := New([]string{"localhost:9092"}, "grpc")
+	b.Writer.Transport = br
+	b.KeyFields = []string{"event_id", "addr"}
+	for _, fields := range []logrus.Fields{
+		{"event_id": "grpc.transport.conn_failed", "addr": "10.0.0.1:443"},
+		{"addr": "10.0.0.1:443"},
+		{"event_id": "grpc.transport.conn_failed"},
+		{},
+	} {
+		b.Emit(logrus.WarnLevel, fields, "failed")
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := make(map[string]int32)
+	var n int
+	for partition, messages := range br.messages {
+		for _, m := range messages {
+			n++
+			var entry map[string]interface{}
+			if err := json.Unmarshal(m.Value, &entry); err != nil {
+				t.Fatalf("want the entry as JSON, got %q", m.Value)
+			}
+			if entry["msg"] != "failed" || entry["level"] != "warning" {
+				t.Errorf("unexpected entry %s", m.Value)
+			}
+			if len(m.Key) == 0 {
+				continue
+			}
+			if p, ok := keys[string(m.Key)]; ok && p != partition {
+				t.Errorf("want the messages of key %s in one partition, got %d and %d", m.Key, p, partition)
+			}
+			keys[string(m.Key)] = partition
+		}
+	}
+	if n != 4 {
+		t.Errorf("want 4 messages, got %d", n)
+	}
+	if len(keys) != 2 {
+		t.Errorf("want messages keyed by event id, then addr, got %v", keys)
+	}
+	if b.Dropped() != 0 {
+		t.Errorf("want no message dropped, got %d", b.Dropped())
+	}
+}
+
+func TestEmitDropped(t *testing.T) {
+	var failed int
+	b := New([]string{"localhost:9092"}, "grpc")
+	b.Writer.Transport = &broker{err: errors.New("broker down")}
+	b.Writer.MaxAttempts = 1
+	b.OnError = func(err error, messages []kafka.Message) { failed += len(messages) }
+	b.Emit(logrus.ErrorLevel, logrus.Fields{"event_id": "grpc.transport.conn_failed"}, "failed")
+	b.Close()
+	if b.Dropped() != 1 || failed != 1 {
+		t.Errorf("want the message dropped and reported, got %d dropped, %d reported", b.Dropped(), failed)
+	}
+}
@@ -0,0 +1,239 @@
+/*
+Package grpcloki is a grpclogrus.Backend pushing entries to Grafana Loki
+over its HTTP push API.
+*/
+package grpcloki
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+var _ grpclogrus.Backend = (*Backend)(nil)
+
+// Defaults of the batching controls, and bounds of the backoff between
+// retries.
+const (
+	DefaultBatchSize = 1000
+	DefaultBatchWait = time.Second
+	DefaultRetries   = 5
+	MinBackoff       = 500 * time.Millisecond
+	MaxBackoff       = 30 * time.Second
+)
+
+// PushPath is the path of Loki's push API.
+const PushPath = "/loki/api/v1/push"
+
+// Backend batches entries and pushes them to Loki, in the background.
+// Entries are labeled by component, that of parser.Component, by level, and
+// by the fields of LabelKeys; the line is the JSON of the message and
+// the other fields.
+type Backend struct {
+	url string
+
+	// Labels are added to every stream, e.g. job or instance. Set before
+	// use.
+	Labels map[string]string
+	// LabelKeys are the fields turned into labels. Keep them to fields
+	// of few values, like event_id, every combination being a stream.
+	// Set before use.
+	LabelKeys []string
+	// BatchSize is the maximum number of entries pushed at once, and
+	// BatchWait the longest an entry waits to be pushed. Set before use.
+	BatchSize int
+	BatchWait time.Duration
+	// Retries is the number of times a failed push is retried, with an
+	// exponential backoff, before the batch is dropped. Set before use.
+	Retries int
+	// Client pushes the batches, http.DefaultClient when nil. Set before
+	// use.
+	Client *http.Client
+
+	formatter logrus.JSONFormatter
+	once      sync.Once
+	batcher   *grpclogrus.Batcher[entry]
+}
+
+type entry struct {
+	labels string
+	stream map[string]string
+	time   time.Time
+	line   string
+}
+
+// New makes a Backend pushing to the Loki at url, e.g.
+// http://loki:3100.
+func New(url string) *Backend {
+	return &Backend{
+		url:       strings.TrimRight(url, "/") + PushPath,
+		BatchSize: DefaultBatchSize,
+		BatchWait: DefaultBatchWait,
+		Retries:   DefaultRetries,
+	}
+}
+
+func (b *Backend) start() {
+	b.formatter.DisableTimestamp = true
+	b.batcher = grpclogrus.NewBatcher(b.push)
+	b.batcher.MaxSize = b.BatchSize
+	b.batcher.MaxWait = b.BatchWait
+}
+
+// Emit adds the entry to the batch, which is pushed once full or once
+// BatchWait elapsed. Fatal entries close the backend, pushing the batch
+// before grpc exits.
+func (b *Backend) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	b.once.Do(b.start)
+
+	stream := make(map[string]string, len(b.Labels)+2+len(b.LabelKeys))
+	for k, v := range b.Labels {
+		stream[k] = v
+	}
+	stream["level"] = level.String()
+	if c := parser.Component(parser.Fields(fields)); c != "" {
+		stream["component"] = c
+	}
+	line := make(logrus.Fields, len(fields))
+	for k, v := range fields {
+		line[k] = v
+	}
+	for _, k := range b.LabelKeys {
+		if v, ok := fields[k]; ok {
+			stream[labelName(k)] = fmt.Sprint(v)
+			delete(line, k)
+		}
+	}
+	formatted, err := b.formatter.Format(&logrus.Entry{Data: line, Level: level, Message: message})
+	if err != nil {
+		return
+	}
+	e := entry{
+		labels: labelsKey(stream),
+		stream: stream,
+		time:   time.Now(),
+		line:   string(bytes.TrimRight(formatted, "\n")),
+	}
+	b.batcher.Add(e)
+	if level <= logrus.FatalLevel {
+		b.Close()
+	}
+}
+
+// Close pushes the pending entries, waiting for the pushes in progress.
+// Entries emitted afterwards are dropped.
+func (b *Backend) Close() error {
+	b.once.Do(b.start)
+	return b.batcher.Close()
+}
+
+// push pushes a batch, retrying with an exponential backoff.
+func (b *Backend) push(batch []entry) {
+	body, err := encode(batch)
+	if err != nil {
+		grpclogrus.CountDropped(len(batch))
+		return
+	}
+	backoff := MinBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := b.post(body)
+		if err != nil && (!retry || attempt >= b.Retries) {
+			grpclogrus.CountDropped(len(batch))
+		}
+		if err == nil || !retry || attempt >= b.Retries {
+			return
+		}
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > MaxBackoff {
+			backoff = MaxBackoff
+		}
+	}
+}
+
+// post reports whether a failed push is worth retrying: on network
+// errors, rate limiting and server errors, not on rejected entries.
+func (b *Backend) post(body []byte) (retry bool, err error) {
+	client := b.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Post(b.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return true, err
+	}
+	res.Body.Close()
+	if res.StatusCode/100 == 2 {
+		return false, nil
+	}
+	err = fmt.Errorf("grpcloki: push: %s", res.Status)
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode/100 == 5, err
+}
+
+type pushRequest struct {
+	Streams []pushStream `json:"streams"`
+}
+
+type pushStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// encode groups the entries of a batch by stream, in order.
+func encode(batch []entry) ([]byte, error) {
+	var (
+		req     pushRequest
+		streams = make(map[string]int)
+	)
+	for _, e := range batch {
+		i, ok := streams[e.labels]
+		if !ok {
+			i = len(req.Streams)
+			streams[e.labels] = i
+			req.Streams = append(req.Streams, pushStream{Stream: e.stream})
+		}
+		ts := strconv.FormatInt(e.time.UnixNano(), 10)
+		req.Streams[i].Values = append(req.Streams[i].Values, [2]string{ts, e.line})
+	}
+	return json.Marshal(req)
+}
+
+// labelsKey identifies the stream of a set of labels.
+func labelsKey(stream map[string]string) string {
+	keys := make([]string, 0, len(stream))
+	for k := range stream {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(stream[k]))
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+// labelName makes a valid label name of a field name, Loki's label names
+// being restricted to [a-zA-Z_][a-zA-Z0-9_]*.
+func labelName(k string) string {
+	if k != "" && k[0] >= '0' && k[0] <= '9' {
+		k = "_" + k
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, k)
+}
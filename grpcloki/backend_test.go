@@ -0,0 +1,115 @@
+package grpcloki
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+// lokiServer records the pushes it receives, answering with the statuses
+// given, then 204.
+type lokiServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	pushes   []pushRequest
+}
+
+func newLokiServer(t *testing.T, statuses ...int) *lokiServer {
+	s := &lokiServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != PushPath || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected push to %s of %s", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		var req pushRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.pushes = append(s.pushes, req)
+		status := http.StatusNoContent
+		if len(s.statuses) > 0 {
+			status, s.statuses = s.statuses[0], s.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestEmit(t *testing.T) {
+	s := newLokiServer(t)
+	b := New(s.URL + "/")
+	b.Labels = map[string]string{"job": "api"}
+	b.LabelKeys = []string{parser.EventIDKey}
+	b.BatchWait = time.Hour
+	b.Emit(logrus.WarnLevel, logrus.Fields{parser.PackageKey: "transport", parser.EventIDKey: "grpc.transport.conn_failed", "addr": "10.0.0.1:443"}, "failed")
+	b.Emit(logrus.InfoLevel, logrus.Fields{}, "started")
+	b.Emit(logrus.WarnLevel, logrus.Fields{parser.PackageKey: "transport", parser.EventIDKey: "grpc.transport.conn_failed", "addr": "10.0.0.2:443"}, "failed")
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s.pushes) != 1 {
+		t.Fatalf("want 1 push on close, got %d", len(s.pushes))
+	}
+	streams := s.pushes[0].Streams
+	if len(streams) != 2 {
+		t.Fatalf("want 2 streams, got %v", streams)
+	}
+	want := map[string]string{"job": "api", "level": "warning", "component": "transport", "event_id": "grpc.transport.conn_failed"}
+	if len(streams[0].Stream) != len(want) {
+		t.Errorf("want labels %v, got %v", want, streams[0].Stream)
+	}
+	for k, v := range want {
+		if streams[0].Stream[k] != v {
+			t.Errorf("%s: want %q, got %q", k, v, streams[0].Stream[k])
+		}
+	}
+	if len(streams[0].Values) != 2 {
+		t.Fatalf("want both failures in one stream, got %v", streams[0].Values)
+	}
+	if line := streams[0].Values[1][1]; line != `{"addr":"10.0.0.2:443","level":"warning","msg":"failed","package":"transport"}` {
+		t.Errorf("unexpected line %s", line)
+	}
+	if _, ok := streams[1].Stream["component"]; ok {
+		t.Errorf("want no component label without a component, got %v", streams[1].Stream)
+	}
+}
+
+func TestPushRetries(t *testing.T) {
+	s := newLokiServer(t, http.StatusServiceUnavailable, http.StatusNoContent)
+	b := New(s.URL)
+	b.Emit(logrus.InfoLevel, nil, "started")
+	b.Close()
+	if len(s.pushes) != 2 {
+		t.Errorf("want the push retried once, got %d pushes", len(s.pushes))
+	}
+
+	s = newLokiServer(t, http.StatusBadRequest)
+	b = New(s.URL)
+	b.Emit(logrus.InfoLevel, nil, "started")
+	b.Close()
+	if len(s.pushes) != 1 {
+		t.Errorf("want rejected entries not retried, got %d pushes", len(s.pushes))
+	}
+}
+
+func TestLabelName(t *testing.T) {
+	for k, want := range map[string]string{
+		"event_id":    "event_id",
+		"grpc.method": "grpc_method",
+		"1xx":         "_1xx",
+	} {
+		if got := labelName(k); got != want {
+			t.Errorf("%q: want %q, got %q", k, want, got)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Package grpcotel bridges the entries structured by grpclogrus into
+OpenTelemetry.
+*/
+package grpcotel
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+)
+
+// ScopeName is the instrumentation scope of the records emitted.
+const ScopeName = "github.com/aybabtme/grpclogrus"
+
+var _ grpclogrus.Backend = (*Backend)(nil)
+
+// Backend emits entries as OpenTelemetry log records, fields being
+// record attributes. The resource records are attached to is the one of
+// the provider's SDK.
+type Backend struct {
+	logger log.Logger
+}
+
+// New makes a Backend emitting through provider, or the global provider
+// when nil.
+func New(provider log.LoggerProvider) *Backend {
+	if provider == nil {
+		provider = global.GetLoggerProvider()
+	}
+	return &Backend{logger: provider.Logger(ScopeName)}
+}
+
+// Emit the entry.
+func (b *Backend) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	now := time.Now()
+	var r log.Record
+	r.SetTimestamp(now)
+	r.SetObservedTimestamp(now)
+	r.SetSeverity(severity(level))
+	r.SetSeverityText(level.String())
+	r.SetBody(log.StringValue(message))
+	attrs := make([]log.KeyValue, 0, len(fields))
+	for k, v := range fields {
+		attrs = append(attrs, attribute(k, v))
+	}
+	r.AddAttributes(attrs...)
+	b.logger.Emit(context.Background(), r)
+}
+
+func severity(level logrus.Level) log.Severity {
+	switch level {
+	case logrus.PanicLevel:
+		return log.SeverityFatal4
+	case logrus.FatalLevel:
+		return log.SeverityFatal
+	case logrus.ErrorLevel:
+		return log.SeverityError
+	case logrus.WarnLevel:
+		return log.SeverityWarn
+	case logrus.InfoLevel:
+		return log.SeverityInfo
+	case logrus.DebugLevel:
+		return log.SeverityDebug
+	default:
+		return log.SeverityTrace
+	}
+}
+
+func attribute(k string, v interface{}) log.KeyValue {
+	switch v := v.(type) {
+	case string:
+		return log.String(k, v)
+	case bool:
+		return log.Bool(k, v)
+	case int:
+		return log.Int(k, v)
+	case int32:
+		return log.Int64(k, int64(v))
+	case int64:
+		return log.Int64(k, v)
+	case uint32:
+		return log.Int64(k, int64(v))
+	case float32:
+		return log.Float64(k, float64(v))
+	case float64:
+		return log.Float64(k, v)
+	case error:
+		return log.String(k, v.Error())
+	default:
+		return log.String(k, fmt.Sprint(v))
+	}
+}
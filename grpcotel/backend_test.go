@@ -0,0 +1,93 @@
+package grpcotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/embedded"
+)
+
+type provider struct {
+	embedded.LoggerProvider
+	scope  string
+	logger recorder
+}
+
+func (p *provider) Logger(name string, _ ...log.LoggerOption) log.Logger {
+	p.scope = name
+	return &p.logger
+}
+
+type recorder struct {
+	embedded.Logger
+	records []log.Record
+}
+
+func (r *recorder) Emit(_ context.Context, record log.Record) {
+	r.records = append(r.records, record)
+}
+
+func (r *recorder) Enabled(context.Context, log.EnabledParameters) bool { return true }
+
+func TestEmit(t *testing.T) {
+	p := new(provider)
+	b := New(p)
+	if p.scope != ScopeName {
+		t.Errorf("want the scope %q, got %q", ScopeName, p.scope)
+	}
+	rec := &p.logger
+	b.Emit(logrus.WarnLevel, logrus.Fields{
+		"addr":    "10.0.0.1:443",
+		"attempt": 3,
+		"ok":      false,
+		"err":     errors.New("connection refused"),
+		"backoff": time.Second,
+	}, "failed")
+
+	if len(rec.records) != 1 {
+		t.Fatalf("want 1 record, got %d", len(rec.records))
+	}
+	r := rec.records[0]
+	if r.Severity() != log.SeverityWarn || r.SeverityText() != "warning" || r.Body().AsString() != "failed" {
+		t.Errorf("unexpected record %v %q %v", r.Severity(), r.SeverityText(), r.Body())
+	}
+	if r.Timestamp().IsZero() || !r.Timestamp().Equal(r.ObservedTimestamp()) {
+		t.Errorf("want the time of the entry as both timestamps, got %v and %v", r.Timestamp(), r.ObservedTimestamp())
+	}
+	want := map[string]log.Value{
+		"addr":    log.StringValue("10.0.0.1:443"),
+		"attempt": log.IntValue(3),
+		"ok":      log.BoolValue(false),
+		"err":     log.StringValue("connection refused"),
+		"backoff": log.StringValue("1s"),
+	}
+	r.WalkAttributes(func(kv log.KeyValue) bool {
+		if v, ok := want[kv.Key]; !ok || !v.Equal(kv.Value) {
+			t.Errorf("%s: want %v, got %v", kv.Key, v, kv.Value)
+		}
+		delete(want, kv.Key)
+		return true
+	})
+	if len(want) > 0 {
+		t.Errorf("missing attributes %v", want)
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	for level, want := range map[logrus.Level]log.Severity{
+		logrus.PanicLevel: log.SeverityFatal4,
+		logrus.FatalLevel: log.SeverityFatal,
+		logrus.ErrorLevel: log.SeverityError,
+		logrus.InfoLevel:  log.SeverityInfo,
+		logrus.DebugLevel: log.SeverityDebug,
+		logrus.TraceLevel: log.SeverityTrace,
+	} {
+		if got := severity(level); got != want {
+			t.Errorf("%v: want %v, got %v", level, want, got)
+		}
+	}
+}
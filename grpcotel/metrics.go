@@ -0,0 +1,93 @@
+package grpcotel
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/parser"
+	"go.opentelemetry.io/otel"
+	attr "go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc/grpclog"
+)
+
+var _ grpclogrus.Backend = (*Metrics)(nil)
+
+// Metrics records the entries emitted as OpenTelemetry metrics, under
+// the attribute names the Backend gives their fields and level:
+//
+//	grpclogrus.entries{severity, package}    entries emitted
+//	grpclogrus.parse.duration                time to parse a message, in seconds
+//	grpclogrus.sampled_out                   calls left out by grpclogrus.WithSampling
+//	grpclogrus.<name>                        entries of the rules counting toward name
+//
+// The names are those of the counters of grpclogrus.DefaultRuleCounters,
+// e.g. grpclogrus.reconnects. The parse duration is recorded by the loggers of Tee only.
+type Metrics struct {
+	entries metric.Int64Counter
+	parse   metric.Float64Histogram
+	signals map[string]metric.Int64Counter
+}
+
+// NewMetrics makes a Metrics recording through provider, or the global
+// provider when nil.
+func NewMetrics(provider metric.MeterProvider) (*Metrics, error) {
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+	meter := provider.Meter(ScopeName)
+	entries, err := meter.Int64Counter("grpclogrus.entries",
+		metric.WithDescription("Entries structured from grpclog, by severity and component."))
+	if err != nil {
+		return nil, err
+	}
+	parse, err := meter.Float64Histogram("grpclogrus.parse.duration",
+		metric.WithDescription("Time to parse a grpclog message."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	_, err = meter.Int64ObservableCounter("grpclogrus.sampled_out",
+		metric.WithDescription("Calls left out of the logs by sampling."),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			o.Observe(grpclogrus.Counter(grpclogrus.SampledOutVar))
+			return nil
+		}))
+	if err != nil {
+		return nil, err
+	}
+	signals := make(map[string]metric.Int64Counter)
+	for _, name := range grpclogrus.RuleCounterNames() {
+		c, err := meter.Int64Counter("grpclogrus."+name,
+			metric.WithDescription("Entries of the rules counting toward "+name+"."))
+		if err != nil {
+			return nil, err
+		}
+		signals[name] = c
+	}
+	return &Metrics{entries: entries, parse: parse, signals: signals}, nil
+}
+
+// Tee makes a grpclog.Logger emitting to m and the backends, as
+// grpclogrus.Tee does, recording the time each message takes to parse.
+func (m *Metrics) Tee(backends ...grpclogrus.Backend) grpclog.Logger {
+	return grpclogrus.TeeObserved(func(elapsed time.Duration) {
+		m.parse.Record(context.Background(), elapsed.Seconds())
+	}, append([]grpclogrus.Backend{m}, backends...)...)
+}
+
+// Emit counts the entry.
+func (m *Metrics) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	attrs := []attr.KeyValue{attr.String(parser.SeverityKey, level.String())}
+	if c := parser.Component(parser.Fields(fields)); c != "" {
+		attrs = append(attrs, attr.String(parser.PackageKey, c))
+	}
+	m.entries.Add(context.Background(), 1, metric.WithAttributes(attrs...))
+	if id, ok := fields[parser.EventIDKey].(string); ok {
+		if c, ok := m.signals[grpclogrus.DefaultRuleCounters[id]]; ok {
+			c.Add(context.Background(), 1)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package grpcotel
+
+import (
+	"fmt"
+
+	"github.com/Sirupsen/logrus"
+	attr "go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanHook is a logrus.Hook adding the error entries logged within a call
+// to the span active in its context, as events with the fields of the
+// entry as attributes, and setting the status of the span to error: the
+// traces then show what failed underneath. The interceptors of grpclogrus
+// log entries with the context of their call:
+//
+//	logger.AddHook(grpcotel.SpanHook{})
+type SpanHook struct{}
+
+var _ logrus.Hook = SpanHook{}
+
+// Levels are the error levels.
+func (SpanHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel}
+}
+
+// Fire adds the entry to its span, if it has a recording one.
+func (SpanHook) Fire(e *logrus.Entry) error {
+	if e.Context == nil {
+		return nil
+	}
+	span := trace.SpanFromContext(e.Context)
+	if !span.IsRecording() {
+		return nil
+	}
+	attrs := make([]attr.KeyValue, 0, len(e.Data)+1)
+	attrs = append(attrs, attr.String("level", e.Level.String()))
+	for k, v := range e.Data {
+		attrs = append(attrs, spanAttribute(k, v))
+	}
+	span.AddEvent(e.Message, trace.WithAttributes(attrs...))
+	span.SetStatus(otelcodes.Error, e.Message)
+	return nil
+}
+
+func spanAttribute(k string, v interface{}) attr.KeyValue {
+	switch v := v.(type) {
+	case string:
+		return attr.String(k, v)
+	case bool:
+		return attr.Bool(k, v)
+	case int:
+		return attr.Int(k, v)
+	case int64:
+		return attr.Int64(k, v)
+	case uint32:
+		return attr.Int64(k, int64(v))
+	case float64:
+		return attr.Float64(k, v)
+	case error:
+		return attr.String(k, v.Error())
+	default:
+		return attr.String(k, fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,61 @@
+package grpcotel
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+// Fields holding the trace and span ids of a call, as ECS names them.
+const (
+	TraceIDKey = "trace.id"
+	SpanIDKey  = "span.id"
+)
+
+// TraceFields are the trace and span ids of the span active in ctx, or
+// else of the W3C traceparent metadata of an incoming call, so that the
+// entries of the call join with its trace. Use it with
+// grpclogrus.WithContextFields.
+func TraceFields(ctx context.Context) logrus.Fields {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		sc = traceparent(ctx)
+	}
+	if !sc.IsValid() {
+		return nil
+	}
+	return logrus.Fields{
+		TraceIDKey: sc.TraceID().String(),
+		SpanIDKey:  sc.SpanID().String(),
+	}
+}
+
+// traceparent parses the traceparent metadata of an incoming call,
+// version-traceid-spanid-flags.
+func traceparent(ctx context.Context) trace.SpanContext {
+	md, _ := metadata.FromIncomingContext(ctx)
+	v := md.Get("traceparent")
+	if len(v) == 0 {
+		return trace.SpanContext{}
+	}
+	parts := strings.Split(strings.TrimSpace(v[0]), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return trace.SpanContext{}
+	}
+	traceID, err := trace.TraceIDFromHex(parts[1])
+	if err != nil {
+		return trace.SpanContext{}
+	}
+	spanID, err := trace.SpanIDFromHex(parts[2])
+	if err != nil {
+		return trace.SpanContext{}
+	}
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Remote:  true,
+	})
+}
@@ -0,0 +1,76 @@
+package grpcotel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestTraceFields(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	active := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+	traceparent := func(v string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", v))
+	}
+
+	for name, ctx := range map[string]context.Context{
+		"active span": active,
+		"traceparent": traceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"),
+		"future":      traceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra"),
+	} {
+		fields := TraceFields(ctx)
+		if fields[TraceIDKey] != "4bf92f3577b34da6a3ce929d0e0e4736" || fields[SpanIDKey] != "00f067aa0ba902b7" {
+			t.Errorf("%s: unexpected fields %v", name, fields)
+		}
+	}
+	for name, ctx := range map[string]context.Context{
+		"none":            context.Background(),
+		"invalid version": traceparent("ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"),
+		"invalid trace":   traceparent("00-00000000000000000000000000000000-00f067aa0ba902b7-01"),
+		"invalid span":    traceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-xyz-01"),
+		"short":           traceparent("00-4bf92f3577b34da6a3ce929d0e0e4736"),
+	} {
+		if fields := TraceFields(ctx); fields != nil {
+			t.Errorf("%s: want no fields, got %v", name, fields)
+		}
+	}
+}
+
+func TestTraceFieldsOfCalls(t *testing.T) {
+	var out bytes.Buffer
+	l := logrus.New()
+	l.Out = &out
+	l.Level = logrus.DebugLevel
+	l.Formatter = &logrus.JSONFormatter{}
+	interceptor := grpclogrus.UnaryServerInterceptor(logrus.NewEntry(l), grpclogrus.WithContextFields(TraceFields))
+	ctx := metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpc.testing.TestService/UnaryCall"}
+	interceptor(ctx, "req", info, func(context.Context, interface{}) (interface{}, error) { return nil, nil })
+
+	dec := json.NewDecoder(&out)
+	var n int
+	for ; dec.More(); n++ {
+		var e map[string]interface{}
+		if err := dec.Decode(&e); err != nil {
+			t.Fatal(err)
+		}
+		if e[TraceIDKey] != "4bf92f3577b34da6a3ce929d0e0e4736" || e[SpanIDKey] != "00f067aa0ba902b7" {
+			t.Errorf("want the trace and span ids on every entry, got %v", e)
+		}
+	}
+	if n != 2 {
+		t.Errorf("want 2 entries, got %d", n)
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Package grpcprom is a grpclogrus.Backend counting entries in Prometheus,
+to alert on grpc logging what the rules don't know.
+*/
+package grpcprom
+
+import (
+	"fmt"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/parser"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	_ grpclogrus.Backend   = (*Backend)(nil)
+	_ prometheus.Collector = (*Backend)(nil)
+)
+
+// Backend counts the entries emitted, by event_id and level, and the
+// unmatched ones, structured from no rule, along with the rule panics of
+// the parser. Register it with a prometheus.Registerer, and add it to a
+// grpclogrus.Tee.
+type Backend struct {
+	entries   *prometheus.CounterVec
+	unmatched prometheus.Counter
+	panics    prometheus.CounterFunc
+	signals   map[string]prometheus.Counter
+}
+
+// New makes a Backend of metrics in namespace, e.g. "grpclogrus":
+//
+//	<namespace>_entries_total{event_id, level}
+//	<namespace>_unmatched_entries_total
+//	<namespace>_rule_panics_total
+//
+// along with a <namespace>_<name>_total counter per counter of
+// grpclogrus.DefaultRuleCounters, e.g. grpclogrus_reconnects_total.
+func New(namespace string) *Backend {
+	signals := make(map[string]prometheus.Counter)
+	for _, name := range grpclogrus.RuleCounterNames() {
+		signals[name] = prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      name + "_total",
+			Help:      "Entries of the rules counting toward " + name + ".",
+		})
+	}
+	return &Backend{
+		signals: signals,
+		entries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "entries_total",
+			Help:      "Entries structured from grpclog, by event id and level.",
+		}, []string{"event_id", "level"}),
+		unmatched: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "unmatched_entries_total",
+			Help:      "Entries structured from grpclog that matched no rule.",
+		}),
+		panics: prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rule_panics_total",
+			Help:      "Rules that panicked on the args of a message.",
+		}, func() float64 { return float64(parser.RulePanics()) }),
+	}
+}
+
+// Emit counts the entry.
+func (b *Backend) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	id, ok := fields[parser.EventIDKey]
+	if !ok {
+		b.unmatched.Inc()
+		id = ""
+	}
+	b.entries.WithLabelValues(fmt.Sprint(id), level.String()).Inc()
+	if c, ok := b.signals[grpclogrus.DefaultRuleCounters[fmt.Sprint(id)]]; ok {
+		c.Inc()
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (b *Backend) Describe(ch chan<- *prometheus.Desc) {
+	b.entries.Describe(ch)
+	b.unmatched.Describe(ch)
+	b.panics.Describe(ch)
+	for _, c := range b.signals {
+		c.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (b *Backend) Collect(ch chan<- prometheus.Metric) {
+	b.entries.Collect(ch)
+	b.unmatched.Collect(ch)
+	b.panics.Collect(ch)
+	for _, c := range b.signals {
+		c.Collect(ch)
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Package grpcsentry is a grpclogrus.Backend forwarding Error and Fatal
+entries to Sentry.
+*/
+package grpcsentry
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/parser"
+	"github.com/getsentry/sentry-go"
+)
+
+var _ grpclogrus.Backend = (*Backend)(nil)
+
+// FlushTimeout bounds how long a Fatal entry waits to be delivered
+// before the process exits.
+const FlushTimeout = 2 * time.Second
+
+// tagKeys are the fields sent as tags, all others being sent as extra.
+var tagKeys = []string{parser.EventIDKey, parser.ComponentKey, parser.PackageKey}
+
+// Backend captures entries as Sentry events, fingerprinted by event id so
+// that each rule groups into its own issue.
+type Backend struct {
+	hub   *sentry.Hub
+	every time.Duration
+
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+// New makes a Backend capturing with hub, or the current hub when nil.
+// Events sharing a fingerprint are sent at most once every interval, to
+// avoid flooding Sentry during bursts; a zero interval sends them all.
+func New(hub *sentry.Hub, every time.Duration) *Backend {
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+	return &Backend{hub: hub, every: every, last: make(map[string]time.Time)}
+}
+
+// Emit captures Error and Fatal entries and ignores others. Fatal entries
+// are flushed before returning, since the process is about to exit.
+func (b *Backend) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	var sentryLevel sentry.Level
+	switch level {
+	case logrus.PanicLevel, logrus.FatalLevel:
+		sentryLevel = sentry.LevelFatal
+	case logrus.ErrorLevel:
+		sentryLevel = sentry.LevelError
+	default:
+		return
+	}
+
+	fingerprint := message
+	if id, ok := fields[parser.EventIDKey].(string); ok {
+		fingerprint = id
+	}
+	if !b.sample(fingerprint) {
+		return
+	}
+
+	event := sentry.NewEvent()
+	event.Level = sentryLevel
+	event.Logger = "grpc"
+	event.Message = message
+	event.Fingerprint = []string{fingerprint}
+	for k, v := range fields {
+		event.Extra[k] = v
+	}
+	for _, k := range tagKeys {
+		if v, ok := fields[k]; ok {
+			event.Tags[k] = fmt.Sprint(v)
+			delete(event.Extra, k)
+		}
+	}
+	b.hub.CaptureEvent(event)
+
+	if sentryLevel == sentry.LevelFatal {
+		b.hub.Flush(FlushTimeout)
+	}
+}
+
+// sample reports whether an event with this fingerprint may be sent now.
+func (b *Backend) sample(fingerprint string) bool {
+	if b.every <= 0 {
+		return true
+	}
+	now := time.Now()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if last, ok := b.last[fingerprint]; ok && now.Sub(last) < b.every {
+		return false
+	}
+	b.last[fingerprint] = now
+	return true
+}
@@ -0,0 +1,80 @@
+package grpcsentry
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+	"github.com/getsentry/sentry-go"
+)
+
+// transport records the events sent, and the flushes.
+type transport struct {
+	mu      sync.Mutex
+	events  []*sentry.Event
+	flushes int
+}
+
+func (t *transport) Configure(sentry.ClientOptions) {}
+
+func (t *transport) SendEvent(e *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, e)
+}
+
+func (t *transport) Flush(time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.flushes++
+	return true
+}
+
+func newBackend(t *testing.T, every time.Duration) (*Backend, *transport) {
+	tr := new(transport)
+	client, err := sentry.NewClient(sentry.ClientOptions{Transport: tr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(sentry.NewHub(client, sentry.NewScope()), every), tr
+}
+
+func TestEmit(t *testing.T) {
+	b, tr := newBackend(t, 0)
+	b.Emit(logrus.InfoLevel, logrus.Fields{parser.EventIDKey: "grpc.ignored"}, "ignored")
+	b.Emit(logrus.ErrorLevel, logrus.Fields{parser.EventIDKey: "grpc.failed", parser.ComponentKey: "core", "peer": "10.0.0.1:443"}, "failed")
+	b.Emit(logrus.FatalLevel, nil, "exiting")
+
+	if len(tr.events) != 2 {
+		t.Fatalf("want the error and fatal entries captured, got %d events", len(tr.events))
+	}
+	e := tr.events[0]
+	if e.Level != sentry.LevelError || e.Message != "failed" || e.Fingerprint[0] != "grpc.failed" {
+		t.Errorf("want an error event fingerprinted by event id, got %v %q %v", e.Level, e.Message, e.Fingerprint)
+	}
+	if e.Tags[parser.EventIDKey] != "grpc.failed" || e.Tags[parser.ComponentKey] != "core" || e.Extra["peer"] != "10.0.0.1:443" {
+		t.Errorf("want the event id and component as tags, other fields as extra, got %v %v", e.Tags, e.Extra)
+	}
+	if _, ok := e.Extra[parser.EventIDKey]; ok {
+		t.Errorf("want the tags left out of extra, got %v", e.Extra)
+	}
+	if e := tr.events[1]; e.Level != sentry.LevelFatal || e.Fingerprint[0] != "exiting" {
+		t.Errorf("want a fatal event fingerprinted by message, got %v %v", e.Level, e.Fingerprint)
+	}
+	if tr.flushes != 1 {
+		t.Errorf("want the fatal event flushed, got %d flushes", tr.flushes)
+	}
+}
+
+func TestEmitSampled(t *testing.T) {
+	b, tr := newBackend(t, time.Hour)
+	for i := 0; i < 3; i++ {
+		b.Emit(logrus.ErrorLevel, logrus.Fields{parser.EventIDKey: "grpc.failed"}, "failed")
+	}
+	b.Emit(logrus.ErrorLevel, logrus.Fields{parser.EventIDKey: "grpc.other"}, "other")
+	if len(tr.events) != 2 {
+		t.Errorf("want one event per fingerprint in the interval, got %d", len(tr.events))
+	}
+}
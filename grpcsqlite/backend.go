@@ -0,0 +1,221 @@
+/*
+Package grpcsqlite is a grpclogrus.Backend writing entries to a SQLite
+database, for querying a day of grpc events with SQL on a single box:
+
+	SELECT event_id, count(*) FROM entries
+	WHERE level = 'error' GROUP BY event_id;
+
+	SELECT time, json_extract(fields, '$.addr') FROM entries
+	WHERE component = 'transport';
+
+The backend takes a *sql.DB, opened with the SQLite driver of your
+choice, such as github.com/mattn/go-sqlite3 or modernc.org/sqlite.
+*/
+package grpcsqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+var _ grpclogrus.Backend = (*Backend)(nil)
+
+// Schema is created by New when missing. Times are RFC 3339 in UTC, which
+// sort as text; fields holds the JSON object of every field.
+const Schema = `
+CREATE TABLE IF NOT EXISTS entries (
+	id        INTEGER PRIMARY KEY,
+	time      TEXT NOT NULL,
+	level     TEXT NOT NULL,
+	message   TEXT NOT NULL,
+	event_id  TEXT,
+	component TEXT,
+	fields    TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS entries_time ON entries (time);
+CREATE INDEX IF NOT EXISTS entries_event_id ON entries (event_id);
+`
+
+// Entries are written in a transaction per batch, rather than one per
+// entry, SQLite syncing every commit.
+const (
+	BatchSize = 500
+	BatchWait = time.Second
+)
+
+// timeFormat is RFC 3339 with a fixed number of decimals, for times to
+// sort as text.
+const timeFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+// queued is the number of entries waiting to be written beyond which new
+// entries are dropped.
+const queued = 10 * BatchSize
+
+type entry struct {
+	time      time.Time
+	level     logrus.Level
+	message   string
+	eventID   interface{}
+	component interface{}
+	fields    []byte
+}
+
+// Backend writes entries in the background.
+type Backend struct {
+	db      *sql.DB
+	entries chan entry
+	done    chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// New makes a Backend writing to db, creating its Schema.
+func New(db *sql.DB) (*Backend, error) {
+	if _, err := db.Exec(Schema); err != nil {
+		return nil, fmt.Errorf("grpcsqlite: creating schema: %v", err)
+	}
+	b := &Backend{
+		db:      db,
+		entries: make(chan entry, queued),
+		done:    make(chan struct{}),
+	}
+	go b.run()
+	return b, nil
+}
+
+// Emit queues the entry, dropping it when the queue is full or the
+// backend closed. Fatal entries close the backend, writing the queued
+// entries before grpc exits.
+func (b *Backend) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	e := entry{
+		time:      time.Now(),
+		level:     level,
+		message:   message,
+		eventID:   text(fields[parser.EventIDKey]),
+		component: parser.Component(parser.Fields(fields)),
+	}
+	var err error
+	if e.fields, err = json.Marshal(jsonFields(fields)); err != nil {
+		grpclogrus.CountDropped(1)
+		return
+	}
+	b.mu.Lock()
+	if b.closed {
+		grpclogrus.CountDropped(1)
+	} else {
+		select {
+		case b.entries <- e:
+		default:
+			grpclogrus.CountDropped(1)
+		}
+	}
+	b.mu.Unlock()
+	if level <= logrus.FatalLevel {
+		b.Close()
+	}
+}
+
+// Close writes the queued entries. Entries emitted afterwards are
+// dropped. The database is left open.
+func (b *Backend) Close() error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.entries)
+	}
+	b.mu.Unlock()
+	<-b.done
+	return nil
+}
+
+func (b *Backend) run() {
+	defer close(b.done)
+	var (
+		batch []entry
+		tick  = time.NewTicker(BatchWait)
+	)
+	defer tick.Stop()
+	for {
+		select {
+		case e, ok := <-b.entries:
+			if !ok {
+				b.write(batch)
+				return
+			}
+			if batch = append(batch, e); len(batch) >= BatchSize {
+				b.write(batch)
+				batch = batch[:0]
+			}
+		case <-tick.C:
+			b.write(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+// write drops the batch when it can't be written.
+func (b *Backend) write(batch []entry) {
+	if len(batch) == 0 {
+		return
+	}
+	tx, err := b.db.Begin()
+	if err != nil {
+		grpclogrus.CountDropped(len(batch))
+		return
+	}
+	stmt, err := tx.Prepare(`INSERT INTO entries (time, level, message, event_id, component, fields) VALUES (?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		tx.Rollback()
+		grpclogrus.CountDropped(len(batch))
+		return
+	}
+	defer stmt.Close()
+	for _, e := range batch {
+		ts := e.time.UTC().Format(timeFormat)
+		if _, err := stmt.Exec(ts, e.level.String(), e.message, e.eventID, e.component, string(e.fields)); err != nil {
+			tx.Rollback()
+			grpclogrus.CountDropped(len(batch))
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		grpclogrus.CountDropped(len(batch))
+	}
+}
+
+// text is the SQL value of a field: NULL when missing.
+func text(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return fmt.Sprint(v)
+}
+
+// jsonFields renders errors and other values encoding/json can't handle
+// as strings.
+func jsonFields(fields logrus.Fields) map[string]interface{} {
+	m := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		switch v := v.(type) {
+		case error:
+			m[k] = v.Error()
+		case string, bool, int, int32, int64, uint, uint32, uint64, float32, float64, time.Time, nil:
+			m[k] = v
+		default:
+			if _, err := json.Marshal(v); err != nil {
+				m[k] = fmt.Sprint(v)
+			} else {
+				m[k] = v
+			}
+		}
+	}
+	return m
+}
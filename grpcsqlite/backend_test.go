@@ -0,0 +1,36 @@
+package grpcsqlite
+
+import (
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+)
+
+// newQueue makes a Backend queueing up to n entries, without writing
+// them.
+func newQueue(n int) *Backend {
+	b := &Backend{entries: make(chan entry, n), done: make(chan struct{})}
+	close(b.done)
+	return b
+}
+
+func TestEmitFullQueueCountsDrop(t *testing.T) {
+	b := newQueue(1)
+	before := grpclogrus.Counter(grpclogrus.DroppedVar)
+	b.Emit(logrus.InfoLevel, logrus.Fields{}, "queued")
+	b.Emit(logrus.InfoLevel, logrus.Fields{}, "dropped")
+	if n := grpclogrus.Counter(grpclogrus.DroppedVar) - before; n != 1 {
+		t.Errorf("want 1 entry dropped, got %d", n)
+	}
+}
+
+func TestEmitAfterClose(t *testing.T) {
+	b := newQueue(1)
+	b.Close()
+	before := grpclogrus.Counter(grpclogrus.DroppedVar)
+	b.Emit(logrus.InfoLevel, logrus.Fields{}, "dropped")
+	if n := grpclogrus.Counter(grpclogrus.DroppedVar) - before; n != 1 {
+		t.Errorf("want 1 entry dropped, got %d", n)
+	}
+}
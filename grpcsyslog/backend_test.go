@@ -0,0 +1,68 @@
+package grpcsyslog
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+func TestFormat(t *testing.T) {
+	b := &Backend{SDID: DefaultSDID, facility: Local0, hostname: "host", appName: "app", procID: "42"}
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	fields := logrus.Fields{"peer": "10.0.0.1:443", "err": `bad "quote" ]`, "a b": 1}
+	got := string(b.format(now, logrus.WarnLevel, fields, "failed"))
+	want := `<132>1 2020-01-02T03:04:05.000000Z host app 42 - [grpc@32473 a_b="1" err="bad \"quote\" \]" peer="10.0.0.1:443"] failed`
+	if got != want {
+		t.Errorf("want\n%s\ngot\n%s", want, got)
+	}
+	if got := string(b.format(now, logrus.InfoLevel, nil, "done")); got != "<134>1 2020-01-02T03:04:05.000000Z host app 42 - - done" {
+		t.Errorf("want the nil structured data without fields, got %s", got)
+	}
+}
+
+func TestEmitTCP(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		length, err := r.ReadString(' ')
+		if err != nil {
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimSpace(length))
+		msg := make([]byte, n)
+		if _, err := io.ReadFull(r, msg); err == nil {
+			received <- string(msg)
+		}
+	}()
+
+	b, err := Dial("tcp", lis.Addr().String(), Daemon, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	b.Emit(logrus.ErrorLevel, logrus.Fields{"event_id": "grpc.test"}, "failed")
+	select {
+	case msg := <-received:
+		if !strings.HasPrefix(msg, "<27>1 ") || !strings.HasSuffix(msg, ` test `+b.procID+` - [grpc@32473 event_id="grpc.test"] failed`) {
+			t.Errorf("want an octet-counted message, got %q", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("want a message received")
+	}
+}
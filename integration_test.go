@@ -0,0 +1,202 @@
+//go:build integration
+
+package grpclogrus_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/parser"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/grpclog"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// The tests of this file run the grpc-go the tree builds against, failing
+// when its messages drift from the rules. Run them with:
+//
+//	go test -tags integration -run Integration
+
+// entry is an entry captured, written as JSON by the process registering
+// a service twice.
+type entry struct {
+	Level   string        `json:"level"`
+	Message string        `json:"message"`
+	Fields  logrus.Fields `json:"fields"`
+}
+
+// captured is a Backend keeping the entries it emits.
+type captured struct {
+	mu      sync.Mutex
+	entries []entry
+}
+
+func (c *captured) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	copied := make(logrus.Fields, len(fields))
+	for k, v := range fields {
+		copied[k] = v
+	}
+	c.mu.Lock()
+	c.entries = append(c.entries, entry{Level: level.String(), Message: message, Fields: copied})
+	c.mu.Unlock()
+}
+
+func (c *captured) Entries() []entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]entry(nil), c.entries...)
+}
+
+// find reports whether an entry was captured at level, of the event id
+// given, with an error containing errSubstr and the fields of subset.
+func (c *captured) find(level logrus.Level, eventID, errSubstr string, subset logrus.Fields) bool {
+	for _, e := range c.Entries() {
+		if e.Level != level.String() || e.Fields[parser.EventIDKey] != eventID {
+			continue
+		}
+		if err, _ := e.Fields["err"].(string); !strings.Contains(err, errSubstr) {
+			continue
+		}
+		contains := true
+		for k, v := range subset {
+			if e.Fields[k] != v {
+				contains = false
+			}
+		}
+		if contains {
+			return true
+		}
+	}
+	return false
+}
+
+// serve captures the entries of grpclog while a grpc server runs, for
+// the test to induce failures, returning the address of the server.
+func serve(t *testing.T) (*captured, string) {
+	c := new(captured)
+	grpclog.SetLogger(grpclogrus.Tee(c))
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer()
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	return c, lis.Addr().String()
+}
+
+// waitEntry waits for the server to log an entry of the event id given,
+// with an error containing errSubstr.
+func waitEntry(t *testing.T, c *captured, level logrus.Level, eventID, errSubstr string, subset logrus.Fields) {
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if c.find(level, eventID, errSubstr, subset) {
+			return
+		}
+	}
+	t.Errorf("want a %v %s entry with an error containing %q and %v, got:", level, eventID, errSubstr, subset)
+	for _, e := range c.Entries() {
+		t.Logf("%v %q %v", e.Level, e.Message, e.Fields)
+	}
+}
+
+func TestIntegrationBadPreface(t *testing.T) {
+	c, addr := serve(t)
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("GET / HTTP/1.1\r\nHost: grpc\r\n\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	waitEntry(t, c, logrus.ErrorLevel, "grpc.server_serve_failed_to_create_servertransport", "bogus greeting", logrus.Fields{
+		parser.ComponentKey: "core",
+		parser.CategoryKey:  string(parser.CategoryConnection),
+	})
+}
+
+func TestIntegrationAbruptClose(t *testing.T) {
+	c, addr := serve(t)
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Half of the preface.
+	if _, err := conn.Write([]byte("PRI * HTTP/2.0\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	waitEntry(t, c, logrus.ErrorLevel, "grpc.server_serve_failed_to_create_servertransport", "failed to receive the preface", logrus.Fields{
+		parser.ComponentKey: "core",
+	})
+}
+
+// duplicateChild is set in the environment of the process registering a
+// service twice, which grpc exits on.
+const duplicateChild = "GRPCLOGRUS_DUPLICATE_REGISTRATION"
+
+func TestIntegrationDuplicateRegistration(t *testing.T) {
+	if os.Getenv(duplicateChild) != "" {
+		registerTwice()
+		return
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestIntegrationDuplicateRegistration$")
+	cmd.Env = append(os.Environ(), duplicateChild+"=1")
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err == nil {
+		t.Fatal("want the process to exit with an error")
+	}
+
+	var c captured
+	dec := json.NewDecoder(&stdout)
+	for dec.More() {
+		var e entry
+		if err := dec.Decode(&e); err != nil {
+			t.Fatal(err)
+		}
+		c.entries = append(c.entries, e)
+	}
+	subset := logrus.Fields{"service.name": "grpc.health.v1.Health", parser.ComponentKey: "core"}
+	if !c.find(logrus.FatalLevel, "grpc.server_registerservice_found_duplicate_service_registration", "", subset) {
+		t.Errorf("want a fatal entry of the duplicate registration with %v, got %v", subset, c.Entries())
+	}
+}
+
+// registerTwice registers a service twice, writing the entries captured
+// to stdout as JSON, as grpc exits.
+func registerTwice() {
+	var c captured
+	grpclog.SetLogger(grpclogrus.Tee(&dump{captured: &c}))
+	s := grpc.NewServer()
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	healthpb.RegisterHealthServer(s, health.NewServer())
+}
+
+// dump writes the entries captured to stdout on a fatal entry, as the
+// process exits once it returns.
+type dump struct {
+	captured *captured
+}
+
+func (d *dump) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	d.captured.Emit(level, fields, message)
+	if level != logrus.FatalLevel {
+		return
+	}
+	enc := json.NewEncoder(os.Stdout)
+	for _, e := range d.captured.Entries() {
+		enc.Encode(e)
+	}
+}
@@ -0,0 +1,410 @@
+package grpclogrus
+
+import (
+	"context"
+	"math/rand"
+	"path"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Fields of the entries logged by the interceptors. The peer, target and
+// code are found under the same names in entries parsed from grpclog.
+const (
+	ServiceKey  = "service"
+	MethodKey   = "method"
+	PeerKey     = "peer"
+	CodeKey     = "code"
+	DurationKey = "duration"
+	ErrKey      = "err"
+	TargetKey   = "target"
+	AttemptsKey = "attempts"
+	PushbackKey = "retry_pushback"
+)
+
+// Fields of the entries of finished calls, along with the code and
+// duration. The deadline is the time left to the call when it started.
+const (
+	CodeNumberKey       = "code_number"
+	DurationMSKey       = "duration_ms"
+	DeadlineKey         = "deadline"
+	DeadlineExceededKey = "deadline_exceeded"
+)
+
+// Option configures the interceptors.
+type Option func(*options)
+
+type options struct {
+	codeLevel func(codes.Code) logrus.Level
+	start     bool
+	prefix    string
+
+	payloadMax   int
+	payloadAllow func(fullMethod string) bool
+	redacted     map[string]bool
+
+	requestIDHeader string
+	contextFields   []func(context.Context) logrus.Fields
+	peerDetails     bool
+	metadataKeys    []string
+	metadataFields  []func(metadata.MD) logrus.Fields
+
+	methods  []methodRule
+	sampling float64
+
+	maxFieldLength int
+	mapping        *FieldMapping
+}
+
+// WithCodeLevel sets the level of the entries of finished calls, from
+// their status code. DefaultCodeLevel is used otherwise: OK at info,
+// Canceled at warning, Internal at error, and so on.
+func WithCodeLevel(fn func(codes.Code) logrus.Level) Option {
+	return func(o *options) { o.codeLevel = fn }
+}
+
+// WithoutStart skips the entries of started calls, only logging finished
+// ones.
+func WithoutStart() Option {
+	return func(o *options) { o.start = false }
+}
+
+// WithFieldPrefix prefixes the fields of the interceptors' entries, but
+// for event_id, e.g. with "grpc." for them not to collide with those of
+// the application.
+func WithFieldPrefix(prefix string) Option {
+	return func(o *options) { o.prefix = prefix }
+}
+
+// WithContextFields adds the fields fn returns from the context of a
+// call to its entries, as they are: for instance, the trace and span ids
+// of grpcotel.TraceFields.
+func WithContextFields(fn func(ctx context.Context) logrus.Fields) Option {
+	return func(o *options) { o.contextFields = append(o.contextFields, fn) }
+}
+
+func newOptions(opts []Option) options {
+	o := options{codeLevel: DefaultCodeLevel, start: true, sampling: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// fields truncates the long values of fields, and applies the mapping
+// and the prefix.
+func (o *options) fields(fields logrus.Fields) logrus.Fields {
+	if o.maxFieldLength > 0 {
+		fields = truncateFields(fields, o.maxFieldLength)
+	}
+	if o.mapping != nil {
+		return o.mapping.rename(fields, o.prefixed)
+	}
+	if o.prefix == "" {
+		return fields
+	}
+	prefixed := make(logrus.Fields, len(fields))
+	for k, v := range fields {
+		prefixed[o.prefixed(k)] = v
+	}
+	return prefixed
+}
+
+func (o *options) prefixed(k string) string {
+	if k == parser.EventIDKey {
+		return k
+	}
+	return o.prefix + k
+}
+
+// withContext adds the context fields of a call to its entry, and the
+// details of its peer, for incoming calls. The entry carries the context,
+// for hooks such as grpcotel.SpanHook.
+func (o *options) withContext(ctx context.Context, entry *logrus.Entry) *logrus.Entry {
+	entry = entry.WithContext(ctx)
+	if p, ok := peer.FromContext(ctx); ok && o.peerDetails {
+		entry = entry.WithFields(o.fields(peerFields(p)))
+	}
+	for _, fn := range o.contextFields {
+		if fields := fn(ctx); len(fields) > 0 {
+			entry = entry.WithFields(fields)
+		}
+	}
+	return entry
+}
+
+// DefaultCodeLevel logs successful calls at info, calls failed by the
+// client at warning, and calls failed by the server at error.
+func DefaultCodeLevel(code codes.Code) logrus.Level {
+	switch code {
+	case codes.OK:
+		return logrus.InfoLevel
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound,
+		codes.AlreadyExists, codes.PermissionDenied, codes.Unauthenticated,
+		codes.ResourceExhausted, codes.FailedPrecondition, codes.Aborted,
+		codes.OutOfRange:
+		return logrus.WarnLevel
+	default:
+		return logrus.ErrorLevel
+	}
+}
+
+// UnaryServerInterceptor logs an entry of l when a unary call starts, at
+// debug level, and when it finishes, at the level of its status code,
+// with the method, peer, code and duration of the call, and its attempt
+// when the client retried it. The entry of the call is stored in the
+// context of the handler, for FromContext.
+func UnaryServerInterceptor(l *logrus.Entry, opts ...Option) grpc.UnaryServerInterceptor {
+	if l == nil {
+		l = logrus.WithFields(logrus.Fields{"source": "grpc"})
+	}
+	o := newOptions(opts)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		c, ok := o.startCall(ctx, info.FullMethod)
+		if !ok {
+			return handler(ctx, req)
+		}
+		entry := l.WithFields(o.fields(callFields(ctx, info.FullMethod)))
+		ctx, entry = o.incomingRequestID(ctx, entry)
+		entry = o.withContext(ctx, entry)
+		entry = o.withMetadata(incomingMetadata(ctx), entry)
+		ctx = IntoContext(ctx, entry)
+		if o.start {
+			entry.WithField(parser.EventIDKey, "grpc.unary_call_started").Debug("unary call started")
+		}
+		resp, err := handler(ctx, req)
+		if err != nil {
+			entry = o.payloads(entry, info.FullMethod, req, nil)
+		} else {
+			entry = o.payloads(entry, info.FullMethod, req, resp)
+		}
+		finished(entry, o, "grpc.unary_call_finished", "unary call finished", c, err)
+		return resp, err
+	}
+}
+
+// callFields are the fields of the entries of a call.
+func callFields(ctx context.Context, fullMethod string) logrus.Fields {
+	fields := methodFields(fullMethod)
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields[PeerKey] = p.Addr.String()
+	}
+	if n := attempt(ctx); n > 1 {
+		fields[AttemptsKey] = n
+	}
+	return fields
+}
+
+func methodFields(fullMethod string) logrus.Fields {
+	service, method := path.Split(fullMethod)
+	return logrus.Fields{
+		ServiceKey: path.Clean(service)[1:],
+		MethodKey:  method,
+	}
+}
+
+// call is what's known of a call when it starts.
+type call struct {
+	start       time.Time
+	deadline    time.Duration
+	hasDeadline bool
+	level       func(codes.Code) logrus.Level
+}
+
+// startCall starts a call of a method, unless its calls aren't logged, or
+// it's left out of the sample.
+func (o *options) startCall(ctx context.Context, fullMethod string) (call, bool) {
+	log, level := o.method(fullMethod)
+	if !log || !o.sampled() {
+		return call{}, false
+	}
+	c := call{start: time.Now(), level: level}
+	if d, ok := ctx.Deadline(); ok {
+		c.deadline, c.hasDeadline = d.Sub(c.start), true
+	}
+	return c, true
+}
+
+// sampled picks a call to log WithSampling, counting those left out.
+func (o *options) sampled() bool {
+	if o.sampling < 1 && rand.Float64() >= o.sampling {
+		vars.Add(SampledOutVar, 1)
+		return false
+	}
+	return true
+}
+
+func finished(entry *logrus.Entry, o options, eventID, message string, c call, err error) {
+	code := status.Code(err)
+	if code == codes.Unknown {
+		// Handlers often return the error of their context as is.
+		code = status.FromContextError(err).Code()
+	}
+	elapsed := time.Since(c.start)
+	fields := logrus.Fields{
+		parser.EventIDKey: eventID,
+		CodeKey:           code.String(),
+		CodeNumberKey:     uint32(code),
+		DurationKey:       elapsed.String(),
+		DurationMSKey:     float64(elapsed) / float64(time.Millisecond),
+	}
+	if c.hasDeadline {
+		fields[DeadlineKey] = c.deadline.String()
+		fields[DeadlineExceededKey] = code == codes.DeadlineExceeded || elapsed > c.deadline
+	}
+	if err != nil {
+		fields[ErrKey] = err
+	}
+	entry.WithFields(o.fields(fields)).Log(c.level(code), message)
+}
+
+// Fields of the entries of finished streams.
+const (
+	SentKey     = "sent"
+	ReceivedKey = "received"
+)
+
+// StreamServerInterceptor logs an entry of l when a stream opens, at
+// debug level, and when it closes, at the level of its status code, with
+// the method, peer, code and duration of the stream, and the number of
+// messages sent and received. The entry of the stream is stored in its
+// context, for FromContext.
+func StreamServerInterceptor(l *logrus.Entry, opts ...Option) grpc.StreamServerInterceptor {
+	if l == nil {
+		l = logrus.WithFields(logrus.Fields{"source": "grpc"})
+	}
+	o := newOptions(opts)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		c, ok := o.startCall(ss.Context(), info.FullMethod)
+		if !ok {
+			return handler(srv, ss)
+		}
+		entry := l.WithFields(o.fields(callFields(ss.Context(), info.FullMethod)))
+		ctx, entry := o.incomingRequestID(ss.Context(), entry)
+		entry = o.withContext(ctx, entry)
+		entry = o.withMetadata(incomingMetadata(ctx), entry)
+		ctx = IntoContext(ctx, entry)
+		if o.start {
+			entry.WithField(parser.EventIDKey, "grpc.stream_opened").Debug("stream opened")
+		}
+		cs := &countingStream{ServerStream: ss, ctx: ctx, payload: o.streamPayloads(entry, info.FullMethod)}
+		err := handler(srv, cs)
+		entry = entry.WithFields(o.fields(logrus.Fields{
+			SentKey:     atomic.LoadInt64(&cs.sent),
+			ReceivedKey: atomic.LoadInt64(&cs.received),
+		}))
+		finished(entry, o, "grpc.stream_closed", "stream closed", c, err)
+		return err
+	}
+}
+
+// countingStream counts the messages of a stream, which a handler uses
+// from a single goroutine per direction, logging them with payload when
+// set, and carries the context of the interceptor.
+type countingStream struct {
+	grpc.ServerStream
+	ctx            context.Context
+	sent, received int64
+	payload        func(key string, m interface{})
+}
+
+func (s *countingStream) Context() context.Context { return s.ctx }
+
+func (s *countingStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		atomic.AddInt64(&s.sent, 1)
+		if s.payload != nil {
+			s.payload(ResponseKey, m)
+		}
+	}
+	return err
+}
+
+func (s *countingStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		atomic.AddInt64(&s.received, 1)
+		if s.payload != nil {
+			s.payload(RequestKey, m)
+		}
+	}
+	return err
+}
+
+// UnaryClientInterceptor logs an entry of l when an outbound unary call
+// starts, at debug level, and when it finishes, at the level of its
+// status code, with the target, method, peer, code and duration of the
+// call, and the retry pushback the server asked for, if any. The number of
+// attempts grpc made of the call is added when the AttemptHandler is
+// installed on the connection too.
+func UnaryClientInterceptor(l *logrus.Entry, opts ...Option) grpc.UnaryClientInterceptor {
+	if l == nil {
+		l = logrus.WithFields(logrus.Fields{"source": "grpc"})
+	}
+	o := newOptions(opts)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		c, ok := o.startCall(ctx, method)
+		if !ok {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+		fields := methodFields(method)
+		fields[TargetKey] = cc.Target()
+		entry := l.WithFields(o.fields(fields))
+		ctx, entry = o.outgoingRequestID(ctx, entry)
+		entry = o.withContext(ctx, entry)
+		entry = o.withMetadata(outgoingMetadata(ctx), entry)
+		ctx, calls := withAttempts(ctx)
+		if o.start {
+			entry.WithField(parser.EventIDKey, "grpc.unary_client_call_started").Debug("unary client call started")
+		}
+
+		var (
+			p       peer.Peer
+			trailer metadata.MD
+		)
+		callOpts = append(callOpts, grpc.Peer(&p), grpc.Trailer(&trailer))
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		if err != nil {
+			reply = nil
+		}
+		entry = o.payloads(entry, method, req, reply)
+
+		fields = logrus.Fields{}
+		if o.peerDetails {
+			fields = peerFields(&p)
+		}
+		if p.Addr != nil {
+			fields[PeerKey] = p.Addr.String()
+		}
+		if v := trailer.Get("grpc-retry-pushback-ms"); len(v) > 0 {
+			fields[PushbackKey] = v[0] + "ms"
+		}
+		if n := calls.total(); n > 0 {
+			fields[AttemptsKey] = n
+		}
+		finished(entry.WithFields(o.fields(fields)), o, "grpc.unary_client_call_finished", "unary client call finished", c, err)
+		return err
+	}
+}
+
+// attempt reads the number of the attempt of a call, which grpc sends
+// from the second one on when it retries a call.
+func attempt(ctx context.Context) int {
+	md, _ := metadata.FromIncomingContext(ctx)
+	v := md.Get("grpc-previous-rpc-attempts")
+	if len(v) == 0 {
+		return 1
+	}
+	n, _ := strconv.Atoi(v[0])
+	return n + 1
+}
@@ -0,0 +1,353 @@
+package grpclogrus_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/logtest"
+	"github.com/aybabtme/grpclogrus/parser"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// captureHook records the entries of a logrus logger.
+type captureHook struct{ captured *logtest.Captured }
+
+func (captureHook) Levels() []logrus.Level { return logrus.AllLevels }
+
+func (h captureHook) Fire(e *logrus.Entry) error {
+	h.captured.Emit(e.Level, e.Data, e.Message)
+	return nil
+}
+
+// capture makes an entry for the interceptors, logging at every level,
+// whose entries are recorded.
+func capture() (*logrus.Entry, *logtest.Captured) {
+	captured := new(logtest.Captured)
+	l := logrus.New()
+	l.Out = io.Discard
+	l.Level = logrus.TraceLevel
+	l.AddHook(captureHook{captured})
+	return logrus.NewEntry(l), captured
+}
+
+// incoming is the context of a call from 10.0.0.1, with the metadata of
+// the key/value pairs given.
+func incoming(kv ...string) context.Context {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 4242}})
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(kv...))
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	l, captured := capture()
+	interceptor := grpclogrus.UnaryServerInterceptor(l)
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpc.testing.TestService/UnaryCall"}
+	var fromContext *logrus.Entry
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		fromContext = grpclogrus.FromContext(ctx)
+		return req, nil
+	}
+	resp, err := interceptor(incoming("grpc-previous-rpc-attempts", "2"), "req", info, handler)
+	if err != nil || resp != "req" {
+		t.Fatalf("want the response of the handler, got %v, %v", resp, err)
+	}
+
+	call := logrus.Fields{
+		grpclogrus.ServiceKey:  "grpc.testing.TestService",
+		grpclogrus.MethodKey:   "UnaryCall",
+		grpclogrus.PeerKey:     "10.0.0.1:4242",
+		grpclogrus.AttemptsKey: 3,
+	}
+	if !captured.HasEntry(logrus.DebugLevel, "grpc.unary_call_started", call) {
+		t.Errorf("want the call started at debug, got %v", captured.Entries())
+	}
+	if !captured.HasEntry(logrus.InfoLevel, "grpc.unary_call_finished", logrus.Fields{grpclogrus.CodeKey: "OK"}) {
+		t.Errorf("want the call finished at info, got %v", captured.Entries())
+	}
+	finished, _ := captured.Find(func(e logtest.Entry) bool { return e.EventID() == "grpc.unary_call_finished" })
+	if !logtest.Contains(finished.Fields, call) || finished.Fields[grpclogrus.DurationKey] == nil {
+		t.Errorf("want the fields of the call and its duration, got %v", finished.Fields)
+	}
+	if fromContext == nil || fromContext.Data[grpclogrus.MethodKey] != "UnaryCall" {
+		t.Errorf("want the entry of the call in the context of the handler, got %v", fromContext)
+	}
+}
+
+func TestUnaryServerInterceptorLevels(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpc.testing.TestService/UnaryCall"}
+	for err, want := range map[error]logrus.Level{
+		status.Error(codes.NotFound, "no such user"): logrus.WarnLevel,
+		status.Error(codes.Internal, "boom"):         logrus.ErrorLevel,
+		context.Canceled:                             logrus.WarnLevel,
+		errors.New("plain"):                          logrus.ErrorLevel,
+	} {
+		l, captured := capture()
+		interceptor := grpclogrus.UnaryServerInterceptor(l, grpclogrus.WithoutStart())
+		_, got := interceptor(incoming(), "req", info, func(context.Context, interface{}) (interface{}, error) { return nil, err })
+		if got != err {
+			t.Errorf("want the error of the handler, got %v", got)
+		}
+		entries := captured.Entries()
+		if len(entries) != 1 || entries[0].Level != want || entries[0].Fields[grpclogrus.ErrKey] != err {
+			t.Errorf("%v: want the call finished at %v, got %v", err, want, entries)
+		}
+	}
+
+	l, captured := capture()
+	interceptor := grpclogrus.UnaryServerInterceptor(l, grpclogrus.WithoutStart(),
+		grpclogrus.WithCodeLevel(func(codes.Code) logrus.Level { return logrus.TraceLevel }))
+	interceptor(incoming(), "req", info, func(context.Context, interface{}) (interface{}, error) { return nil, nil })
+	if entries := captured.Entries(); len(entries) != 1 || entries[0].Level != logrus.TraceLevel {
+		t.Errorf("want the level of WithCodeLevel, got %v", entries)
+	}
+}
+
+func TestUnaryServerInterceptorSampling(t *testing.T) {
+	var out bytes.Buffer
+	l := logrus.New()
+	l.Out = &out
+	l.Level = logrus.DebugLevel
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpc.testing.TestService/EmptyCall"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return req, nil }
+
+	before := grpclogrus.Counter(grpclogrus.SampledOutVar)
+	none := grpclogrus.UnaryServerInterceptor(logrus.NewEntry(l), grpclogrus.WithSampling(0))
+	if _, err := none(context.Background(), "req", info, handler); err != nil {
+		t.Fatal(err)
+	}
+	if n := bytes.Count(out.Bytes(), []byte("\n")); n != 0 {
+		t.Errorf("want the call left out, got %d entries", n)
+	}
+	if got := grpclogrus.Counter(grpclogrus.SampledOutVar) - before; got != 1 {
+		t.Errorf("want the call counted as sampled out, got %d", got)
+	}
+
+	all := grpclogrus.UnaryServerInterceptor(logrus.NewEntry(l), grpclogrus.WithSampling(1))
+	if _, err := all(context.Background(), "req", info, handler); err != nil {
+		t.Fatal(err)
+	}
+	if n := bytes.Count(out.Bytes(), []byte("\n")); n != 2 {
+		t.Errorf("want the started and finished entries of the call, got %d", n)
+	}
+}
+
+// fakeStream is a server stream receiving one message.
+type fakeStream struct {
+	grpc.ServerStream
+	received bool
+}
+
+func (s *fakeStream) Context() context.Context  { return context.Background() }
+func (s *fakeStream) SendMsg(interface{}) error { return nil }
+
+func (s *fakeStream) RecvMsg(m interface{}) error {
+	if s.received {
+		return context.Canceled
+	}
+	s.received = true
+	*m.(*map[string]interface{}) = map[string]interface{}{"user": "a", "password": "hunter2", "id": 12345678901234567}
+	return nil
+}
+
+func TestStreamServerInterceptorPayloads(t *testing.T) {
+	var out bytes.Buffer
+	l := logrus.New()
+	l.Out = &out
+	l.Level = logrus.DebugLevel
+	l.Formatter = &logrus.JSONFormatter{}
+	interceptor := grpclogrus.StreamServerInterceptor(logrus.NewEntry(l),
+		grpclogrus.WithPayloads(0, func(string) bool { return true }),
+		grpclogrus.WithRedactedFields("password"),
+	)
+	info := &grpc.StreamServerInfo{FullMethod: "/grpc.testing.TestService/FullDuplexCall"}
+	err := interceptor(nil, &fakeStream{}, info, func(srv interface{}, ss grpc.ServerStream) error {
+		var req map[string]interface{}
+		if err := ss.RecvMsg(&req); err != nil {
+			return err
+		}
+		return ss.SendMsg(map[string]string{"ok": "yes"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payloads := make(map[string]interface{})
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var e map[string]interface{}
+		if err := dec.Decode(&e); err != nil {
+			t.Fatal(err)
+		}
+		switch e[parser.EventIDKey] {
+		case "grpc.stream_message_received":
+			payloads["received"] = e[grpclogrus.RequestKey]
+		case "grpc.stream_message_sent":
+			payloads["sent"] = e[grpclogrus.ResponseKey]
+		}
+	}
+	if want := `{"id":12345678901234567,"password":"[REDACTED]","user":"a"}`; payloads["received"] != want {
+		t.Errorf("want the request received %s, got %v", want, payloads["received"])
+	}
+	if want := `{"ok":"yes"}`; payloads["sent"] != want {
+		t.Errorf("want the response sent %s, got %v", want, payloads["sent"])
+	}
+}
+
+// countedStream is a server stream receiving n messages.
+type countedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	n   int
+}
+
+func (s *countedStream) Context() context.Context  { return s.ctx }
+func (s *countedStream) SendMsg(interface{}) error { return nil }
+
+func (s *countedStream) RecvMsg(interface{}) error {
+	if s.n == 0 {
+		return io.EOF
+	}
+	s.n--
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	l, captured := capture()
+	interceptor := grpclogrus.StreamServerInterceptor(l)
+	info := &grpc.StreamServerInfo{FullMethod: "/grpc.testing.TestService/FullDuplexCall"}
+	var fromContext *logrus.Entry
+	err := interceptor(nil, &countedStream{ctx: incoming(), n: 3}, info, func(srv interface{}, ss grpc.ServerStream) error {
+		fromContext = grpclogrus.FromContext(ss.Context())
+		for ss.RecvMsg(nil) == nil {
+			if err := ss.SendMsg(nil); err != nil {
+				return err
+			}
+		}
+		ss.SendMsg(nil)
+		return status.Error(codes.Unavailable, "draining")
+	})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("want the error of the handler, got %v", err)
+	}
+
+	call := logrus.Fields{
+		grpclogrus.ServiceKey: "grpc.testing.TestService",
+		grpclogrus.MethodKey:  "FullDuplexCall",
+		grpclogrus.PeerKey:    "10.0.0.1:4242",
+	}
+	if !captured.HasEntry(logrus.DebugLevel, "grpc.stream_opened", call) {
+		t.Errorf("want the stream opened at debug, got %v", captured.Entries())
+	}
+	closed := logrus.Fields{
+		grpclogrus.CodeKey:     "Unavailable",
+		grpclogrus.SentKey:     int64(4),
+		grpclogrus.ReceivedKey: int64(3),
+	}
+	if !captured.HasEntry(logrus.ErrorLevel, "grpc.stream_closed", closed) {
+		t.Errorf("want the stream closed with the messages counted, got %v", captured.Entries())
+	}
+	if fromContext == nil || fromContext.Data[grpclogrus.MethodKey] != "FullDuplexCall" {
+		t.Errorf("want the entry of the stream in its context, got %v", fromContext)
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	cc, err := grpc.NewClient("passthrough:///backend:443", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cc.Close()
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		for _, opt := range opts {
+			switch opt := opt.(type) {
+			case grpc.PeerCallOption:
+				opt.PeerAddr.Addr = &net.TCPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 443}
+			case grpc.TrailerCallOption:
+				*opt.TrailerAddr = metadata.Pairs("grpc-retry-pushback-ms", "250")
+			}
+		}
+		return status.Error(codes.ResourceExhausted, "slow down")
+	}
+
+	l, captured := capture()
+	interceptor := grpclogrus.UnaryClientInterceptor(l, grpclogrus.WithFieldPrefix("grpc."))
+	err = interceptor(context.Background(), "/grpc.testing.TestService/UnaryCall", "req", nil, cc, invoker)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("want the error of the call, got %v", err)
+	}
+
+	if !captured.HasEntry(logrus.DebugLevel, "grpc.unary_client_call_started", logrus.Fields{
+		"grpc." + grpclogrus.TargetKey: "passthrough:///backend:443",
+		"grpc." + grpclogrus.MethodKey: "UnaryCall",
+	}) {
+		t.Errorf("want the call started at debug, with prefixed fields, got %v", captured.Entries())
+	}
+	if !captured.HasEntry(logrus.WarnLevel, "grpc.unary_client_call_finished", logrus.Fields{
+		"grpc." + grpclogrus.ServiceKey:  "grpc.testing.TestService",
+		"grpc." + grpclogrus.PeerKey:     "10.0.0.2:443",
+		"grpc." + grpclogrus.PushbackKey: "250ms",
+		"grpc." + grpclogrus.CodeKey:     "ResourceExhausted",
+	}) {
+		t.Errorf("want the call finished with the peer and pushback, got %v", captured.Entries())
+	}
+	for _, e := range captured.Entries() {
+		if _, ok := e.Fields[parser.EventIDKey]; !ok {
+			t.Errorf("want event_id left unprefixed, got %v", e.Fields)
+		}
+	}
+}
+
+func TestFinishedCallFields(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpc.testing.TestService/UnaryCall"}
+	l, captured := capture()
+	interceptor := grpclogrus.UnaryServerInterceptor(l, grpclogrus.WithoutStart())
+
+	interceptor(incoming(), "req", info, func(context.Context, interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "no such user")
+	})
+	e := captured.Entries()[0]
+	if e.Fields[grpclogrus.CodeNumberKey] != uint32(codes.NotFound) {
+		t.Errorf("want the numeric code, got %v", e.Fields[grpclogrus.CodeNumberKey])
+	}
+	if ms, ok := e.Fields[grpclogrus.DurationMSKey].(float64); !ok || ms < 0 {
+		t.Errorf("want the duration in milliseconds, got %v", e.Fields[grpclogrus.DurationMSKey])
+	}
+	if _, ok := e.Fields[grpclogrus.DeadlineKey]; ok {
+		t.Errorf("want no deadline for calls without one, got %v", e.Fields)
+	}
+
+	captured.Reset()
+	ctx, cancel := context.WithTimeout(incoming(), time.Millisecond)
+	defer cancel()
+	interceptor(ctx, "req", info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	e = captured.Entries()[0]
+	if e.Fields[grpclogrus.CodeKey] != "DeadlineExceeded" || e.Fields[grpclogrus.DeadlineExceededKey] != true {
+		t.Errorf("want the deadline exceeded, got %v", e.Fields)
+	}
+	if d, err := time.ParseDuration(e.Fields[grpclogrus.DeadlineKey].(string)); err != nil || d > time.Millisecond {
+		t.Errorf("want the time left to the call when it started, got %v", e.Fields[grpclogrus.DeadlineKey])
+	}
+
+	captured.Reset()
+	ctx, cancel = context.WithTimeout(incoming(), time.Hour)
+	defer cancel()
+	interceptor(ctx, "req", info, func(context.Context, interface{}) (interface{}, error) { return nil, nil })
+	if e := captured.Entries()[0]; e.Fields[grpclogrus.DeadlineExceededKey] != false {
+		t.Errorf("want the deadline not exceeded, got %v", e.Fields)
+	}
+}
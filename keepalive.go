@@ -0,0 +1,199 @@
+package grpclogrus
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+var _ Backend = (*KeepaliveAggregator)(nil)
+
+// Fields of the summary entries of the KeepaliveAggregator. The events
+// are all those about keepalive of a peer, the ENHANCE_YOUR_CALM ones
+// those of a server finding its client pinged too often, and the ping
+// timeouts those of pings left unacknowledged. The last error is the
+// message of the latest event, with its error if it has one.
+const (
+	KeepaliveEventsKey = "keepalive_events"
+	EnhanceYourCalmKey = "enhance_your_calm"
+	PingTimeoutsKey    = "ping_timeouts"
+	LastErrorKey       = "last_error"
+	IntervalKey        = "interval"
+)
+
+// DefaultKeepaliveInterval is the interval of the summaries of a
+// KeepaliveAggregator.
+const DefaultKeepaliveInterval = time.Minute
+
+// KeepaliveAggregator is a Backend passing entries to another, but for
+// those about keepalive pings and ENHANCE_YOUR_CALM goaways, which it
+// counts per peer instead, emitting a summary entry per peer every
+// Interval.
+type KeepaliveAggregator struct {
+	next Backend
+
+	// Interval is the time between summaries, DefaultKeepaliveInterval
+	// when not positive. Set before use.
+	Interval time.Duration
+
+	once sync.Once
+	stop chan struct{}
+	done chan struct{}
+
+	mu     sync.Mutex
+	peers  map[string]*keepaliveCounts
+	closed bool
+}
+
+type keepaliveCounts struct {
+	events, calm, timeouts int
+	level                  logrus.Level
+	lastError              string
+}
+
+// NewKeepaliveAggregator makes a KeepaliveAggregator passing entries to
+// next.
+func NewKeepaliveAggregator(next Backend) *KeepaliveAggregator {
+	return &KeepaliveAggregator{next: next, Interval: DefaultKeepaliveInterval}
+}
+
+func (a *KeepaliveAggregator) start() {
+	if a.Interval <= 0 {
+		a.Interval = DefaultKeepaliveInterval
+	}
+	a.peers = make(map[string]*keepaliveCounts)
+	a.stop = make(chan struct{})
+	a.done = make(chan struct{})
+	go a.run()
+}
+
+// Emit counts entries about keepalive, and passes the others to the next
+// backend. Fatal entries close the aggregator first, emitting the
+// summaries before grpc exits.
+func (a *KeepaliveAggregator) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	a.once.Do(a.start)
+	text := message
+	if err, ok := fields["err"]; ok {
+		text += ": " + fmt.Sprint(err)
+	}
+	if level == logrus.FatalLevel {
+		// Summarize before the process exits.
+		a.Close()
+		a.next.Emit(level, fields, message)
+		return
+	}
+	calm, timeout, ok := keepaliveEvent(text)
+	if !ok {
+		a.next.Emit(level, fields, message)
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		a.next.Emit(level, fields, message)
+		return
+	}
+	peer := keepalivePeer(fields)
+	c, ok := a.peers[peer]
+	if !ok {
+		c = &keepaliveCounts{level: logrus.InfoLevel}
+		a.peers[peer] = c
+	}
+	c.events++
+	if calm {
+		c.calm++
+	}
+	if timeout {
+		c.timeouts++
+	}
+	if level < c.level {
+		c.level = level
+	}
+	c.lastError = text
+}
+
+// Close emits the last summaries. Entries emitted afterwards are passed
+// to the next backend as they are.
+func (a *KeepaliveAggregator) Close() error {
+	a.once.Do(a.start)
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.stop)
+	}
+	a.mu.Unlock()
+	<-a.done
+	return nil
+}
+
+func (a *KeepaliveAggregator) run() {
+	defer close(a.done)
+	t := time.NewTicker(a.Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			a.summarize()
+		case <-a.stop:
+			a.summarize()
+			return
+		}
+	}
+}
+
+// summarize emits the counts of every peer, in order, and resets them.
+func (a *KeepaliveAggregator) summarize() {
+	a.mu.Lock()
+	peers := a.peers
+	a.peers = make(map[string]*keepaliveCounts)
+	a.mu.Unlock()
+
+	names := make([]string, 0, len(peers))
+	for peer := range peers {
+		names = append(names, peer)
+	}
+	sort.Strings(names)
+	for _, peer := range names {
+		c := peers[peer]
+		fields := logrus.Fields{
+			parser.EventIDKey:  "grpc.keepalive_summary",
+			KeepaliveEventsKey: c.events,
+			EnhanceYourCalmKey: c.calm,
+			PingTimeoutsKey:    c.timeouts,
+			LastErrorKey:       c.lastError,
+			IntervalKey:        a.Interval.String(),
+		}
+		if peer != "" {
+			fields[PeerKey] = peer
+		}
+		a.next.Emit(c.level, fields, "keepalive summary")
+	}
+}
+
+// keepaliveEvent reports whether the text of an entry is about keepalive,
+// and whether it is an ENHANCE_YOUR_CALM or a ping timeout.
+func keepaliveEvent(text string) (calm, timeout, ok bool) {
+	lower := strings.ToLower(text)
+	calm = strings.Contains(lower, "enhance_your_calm") ||
+		strings.Contains(lower, "too_many_pings") ||
+		strings.Contains(lower, "too many pings")
+	keepalive := strings.Contains(lower, "keepalive")
+	timeout = keepalive && (strings.Contains(lower, "timeout") || strings.Contains(lower, "not acked"))
+	return calm, timeout, calm || keepalive
+}
+
+// keepalivePeer is the peer an entry is about, as grpclog names it.
+func keepalivePeer(fields logrus.Fields) string {
+	for _, k := range []string{"addr", TargetKey, PeerKey} {
+		if v, ok := fields[k]; ok {
+			return fmt.Sprint(v)
+		}
+	}
+	return ""
+}
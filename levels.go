@@ -0,0 +1,215 @@
+package grpclogrus
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+var _ Backend = (*Levels)(nil)
+
+// Levels is a Backend passing the entries of a level to another, which
+// can be changed at runtime: overall, and per component, as told by
+// parser.Component. It also holds the verbosity reported by V, the level
+// of detail of grpc's info logs, which grpc asks the Tee emitting to
+// Levels once it's set with SetLogger.
+type Levels struct {
+	next Backend
+
+	// mu serializes the changes, each publishing new settings to
+	// current, which entries are emitted by without locking.
+	mu      sync.Mutex
+	current atomic.Pointer[levelSettings]
+}
+
+// levelSettings are the settings of Levels, read-only once published.
+type levelSettings struct {
+	level      logrus.Level
+	verbosity  int
+	components map[string]logrus.Level
+}
+
+// NewLevels makes Levels passing the entries of level and above to next.
+func NewLevels(next Backend, level logrus.Level) *Levels {
+	l := &Levels{next: next}
+	l.current.Store(&levelSettings{level: level})
+	return l
+}
+
+// Emit passes the entry to the next backend if its level is enabled for
+// its component. Fatal entries always are.
+func (l *Levels) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	if level > logrus.FatalLevel && !l.enabled(level, parser.Component(parser.Fields(fields))) {
+		return
+	}
+	l.next.Emit(level, fields, message)
+}
+
+func (l *Levels) enabled(level logrus.Level, component string) bool {
+	s := l.current.Load()
+	max, ok := s.components[component]
+	if !ok {
+		max = s.level
+	}
+	return level <= max
+}
+
+// update publishes the settings fn makes of a copy of the current ones.
+func (l *Levels) update(fn func(s *levelSettings)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s := *l.current.Load()
+	s.components = maps.Clone(s.components)
+	if s.components == nil {
+		s.components = make(map[string]logrus.Level)
+	}
+	fn(&s)
+	l.current.Store(&s)
+}
+
+// SetLevel sets the level of the components without one of their own.
+func (l *Levels) SetLevel(level logrus.Level) {
+	l.update(func(s *levelSettings) { s.level = level })
+}
+
+// SetComponentLevel sets the level of a component, e.g. "transport".
+func (l *Levels) SetComponentLevel(component string, level logrus.Level) {
+	l.update(func(s *levelSettings) { s.components[component] = level })
+}
+
+// ClearComponentLevel removes the level of a component, which gets the
+// overall level again.
+func (l *Levels) ClearComponentLevel(component string) {
+	l.update(func(s *levelSettings) { delete(s.components, component) })
+}
+
+// Level is the level of the components without one of their own.
+func (l *Levels) Level() logrus.Level {
+	return l.current.Load().level
+}
+
+// ComponentLevels are the levels of the components with one of their own.
+func (l *Levels) ComponentLevels() map[string]logrus.Level {
+	levels := maps.Clone(l.current.Load().components)
+	if levels == nil {
+		levels = make(map[string]logrus.Level)
+	}
+	return levels
+}
+
+// Verbosity is the verbosity reported by V.
+func (l *Levels) Verbosity() int {
+	return l.current.Load().verbosity
+}
+
+// SetVerbosity sets the verbosity reported by V.
+func (l *Levels) SetVerbosity(verbosity int) {
+	l.update(func(s *levelSettings) { s.verbosity = verbosity })
+}
+
+// V reports whether the verbosity is at least verbosity.
+func (l *Levels) V(verbosity int) bool {
+	return l.current.Load().verbosity >= verbosity
+}
+
+// levelsState is the JSON of Levels. Components replace those set
+// before, the level of a component being empty to remove it.
+type levelsState struct {
+	Level      *string           `json:"level,omitempty"`
+	Verbosity  *int              `json:"verbosity,omitempty"`
+	Components map[string]string `json:"components,omitempty"`
+}
+
+func (l *Levels) state() levelsState {
+	current := l.current.Load()
+	level, verbosity := current.level.String(), current.verbosity
+	s := levelsState{Level: &level, Verbosity: &verbosity, Components: make(map[string]string, len(current.components))}
+	for c, lvl := range current.components {
+		s.Components[c] = lvl.String()
+	}
+	return s
+}
+
+// set applies a state, entirely or not at all.
+func (l *Levels) set(s levelsState) error {
+	var level logrus.Level
+	if s.Level != nil {
+		var err error
+		if level, err = logrus.ParseLevel(*s.Level); err != nil {
+			return err
+		}
+	}
+	components := make(map[string]logrus.Level, len(s.Components))
+	for c, name := range s.Components {
+		if name == "" {
+			continue
+		}
+		lvl, err := logrus.ParseLevel(name)
+		if err != nil {
+			return fmt.Errorf("component %q: %v", c, err)
+		}
+		components[c] = lvl
+	}
+
+	l.update(func(current *levelSettings) {
+		if s.Level != nil {
+			current.level = level
+		}
+		if s.Verbosity != nil {
+			current.verbosity = *s.Verbosity
+		}
+		if s.Components != nil {
+			current.components = components
+		}
+	})
+	return nil
+}
+
+// Handler serves the levels as JSON on GET, and changes them on PUT,
+// from a JSON body of the same shape, whose missing keys are left as
+// they are:
+//
+//	{"level": "info", "verbosity": 2, "components": {"transport": "debug"}}
+//
+// PUTs are authorized by authorize, which refuses them with an error;
+// they are all refused when authorize is nil. Mount it under
+// /debug/grpclogrus/level, e.g.
+//
+//	http.Handle("/debug/grpclogrus/level", levels.Handler(authorize))
+func (l *Levels) Handler(authorize func(r *http.Request) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			if authorize == nil {
+				http.Error(w, "changing levels isn't authorized", http.StatusForbidden)
+				return
+			}
+			if err := authorize(r); err != nil {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+			var s levelsState
+			if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := l.set(s); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(l.state())
+	})
+}
@@ -0,0 +1,75 @@
+package grpclogrus_test
+
+import (
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/logtest"
+	"google.golang.org/grpc/grpclog"
+)
+
+func TestSetLoggerVerbosity(t *testing.T) {
+	var captured logtest.Captured
+	levels := grpclogrus.NewLevels(&captured, logrus.InfoLevel)
+	defer grpclogrus.SetLogger(grpclogrus.SetLogger(grpclogrus.Tee(levels)))
+
+	levels.SetVerbosity(2)
+	if !grpclog.V(2) {
+		t.Error("want grpc's verbosity 2 enabled at verbosity 2")
+	}
+	levels.SetVerbosity(0)
+	if grpclog.V(2) || !grpclog.V(0) {
+		t.Error("want only grpc's verbosity 0 enabled at verbosity 0")
+	}
+}
+
+func TestTeeSeverities(t *testing.T) {
+	var captured logtest.Captured
+	g := grpclogrus.Tee(&captured).(grpclog.LoggerV2)
+	g.Warningln("[core]", "[Channel #1]something no rule knows of")
+	g.Errorf("nor %s", "this")
+	g.Errorln("EmptyUnaryCall done")
+
+	entries := captured.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("want 3 entries, got %v", entries)
+	}
+	for i, want := range []logrus.Level{logrus.WarnLevel, logrus.ErrorLevel, logrus.InfoLevel} {
+		if entries[i].Level != want {
+			t.Errorf("%q: want %v, got %v", entries[i].Message, want, entries[i].Level)
+		}
+	}
+}
+
+func TestLevelsComponent(t *testing.T) {
+	var captured logtest.Captured
+	levels := grpclogrus.NewLevels(&captured, logrus.InfoLevel)
+	levels.SetComponentLevel("transport", logrus.WarnLevel)
+	levels.Emit(logrus.InfoLevel, logrus.Fields{"component": "transport"}, "of a component logger")
+	levels.Emit(logrus.InfoLevel, logrus.Fields{"package": "transport"}, "of a rule")
+	levels.Emit(logrus.InfoLevel, logrus.Fields{"component": "core"}, "of another component")
+
+	entries := captured.Entries()
+	if len(entries) != 1 || entries[0].Message != "of another component" {
+		t.Errorf("want the info entries of transport dropped, got %v", entries)
+	}
+}
+
+// TestLevelsConcurrently is meant for -race: levels are changed while
+// entries are emitted.
+func TestLevelsConcurrently(t *testing.T) {
+	levels := grpclogrus.NewLevels(grpclogrus.BackendFunc(func(logrus.Level, logrus.Fields, string) {}), logrus.InfoLevel)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			levels.Emit(logrus.DebugLevel, logrus.Fields{"component": "transport"}, "emitted")
+		}
+	}()
+	for i := 0; i < 1000; i++ {
+		levels.SetComponentLevel("transport", logrus.DebugLevel)
+		levels.ClearComponentLevel("transport")
+	}
+	<-done
+}
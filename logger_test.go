@@ -0,0 +1,50 @@
+package grpclogrus_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/logtest"
+	"google.golang.org/grpc/grpclog"
+)
+
+func TestTeeRawMessageField(t *testing.T) {
+	var captured logtest.Captured
+	g := grpclogrus.TeeWith([]grpclogrus.LoggerOption{grpclogrus.WithRawMessageField("grpc.raw")}, &captured).(grpclog.LoggerV2)
+	g.Infof("grpc: addrConn.resetTransport failed to create client transport: %v; Reconnecting to %v", "eof", "10.0.0.1:443")
+	g.Info("EmptyUnaryCall", "done", 1)
+	g.Infoln("EmptyUnaryCall", "done", 1)
+
+	want := []string{
+		"grpc: addrConn.resetTransport failed to create client transport: eof; Reconnecting to 10.0.0.1:443",
+		"EmptyUnaryCalldone1",
+		"EmptyUnaryCall done 1",
+	}
+	entries := captured.Entries()
+	if len(entries) != len(want) {
+		t.Fatalf("want %d entries, got %v", len(want), entries)
+	}
+	for i, e := range entries {
+		if e.Fields["grpc.raw"] != want[i] {
+			t.Errorf("want the raw message %q, got %v", want[i], e.Fields["grpc.raw"])
+		}
+	}
+}
+
+func TestNewRawMessageField(t *testing.T) {
+	var out bytes.Buffer
+	l := logrus.New()
+	l.Out = &out
+	l.Formatter = &logrus.JSONFormatter{}
+	grpclogrus.New(logrus.NewEntry(l), grpclogrus.WithRawMessageField("grpc.raw")).Println("EmptyUnaryCall", "done")
+	var entry map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["grpc.raw"] != "EmptyUnaryCall done" {
+		t.Errorf("want the raw message, got %v", entry)
+	}
+}
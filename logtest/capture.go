@@ -0,0 +1,122 @@
+/*
+Package logtest records the entries emitted to a grpclogrus.Backend in
+memory, for tests to assert that specific grpc conditions were logged.
+*/
+package logtest
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+var _ grpclogrus.Backend = (*Captured)(nil)
+
+// Entry is an entry recorded by Captured.
+type Entry struct {
+	Level   logrus.Level
+	Message string
+	Fields  logrus.Fields
+}
+
+// EventID is the event id of the entry, empty if it has none.
+func (e Entry) EventID() string {
+	id, _ := e.Fields[parser.EventIDKey].(string)
+	return id
+}
+
+// Captured is a Backend recording every entry emitted to it. The zero
+// Captured is ready to use, e.g. with grpclogrus.Tee:
+//
+//	var captured logtest.Captured
+//	grpclogrus.SetLogger(grpclogrus.Tee(&captured))
+//
+// Fatal entries are recorded too, though grpc exits right after them.
+type Captured struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// Emit records the entry, copying its fields.
+func (c *Captured) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	copied := make(logrus.Fields, len(fields))
+	for k, v := range fields {
+		copied[k] = v
+	}
+	c.mu.Lock()
+	c.entries = append(c.entries, Entry{Level: level, Message: message, Fields: copied})
+	c.mu.Unlock()
+}
+
+// Entries are the entries recorded, in order.
+func (c *Captured) Entries() []Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Entry(nil), c.entries...)
+}
+
+// Reset forgets the entries recorded.
+func (c *Captured) Reset() {
+	c.mu.Lock()
+	c.entries = nil
+	c.mu.Unlock()
+}
+
+// HasEntry reports whether an entry was recorded at level, with the event
+// id given, and the fields of subset among its own, with equal values.
+func (c *Captured) HasEntry(level logrus.Level, eventID string, subset logrus.Fields) bool {
+	_, ok := c.Find(func(e Entry) bool {
+		return e.Level == level && e.EventID() == eventID && Contains(e.Fields, subset)
+	})
+	return ok
+}
+
+// Find returns the first entry recorded that match reports true for.
+func (c *Captured) Find(match func(Entry) bool) (Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, e := range c.entries {
+		if match(e) {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Count is the number of entries recorded with the event id given.
+func (c *Captured) Count(eventID string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var n int
+	for _, e := range c.entries {
+		if e.EventID() == eventID {
+			n++
+		}
+	}
+	return n
+}
+
+// Contains reports whether fields holds those of subset, with equal
+// values. Errors equal their message, for subsets to be written as
+// strings.
+func Contains(fields, subset logrus.Fields) bool {
+	for k, want := range subset {
+		got, ok := fields[k]
+		if !ok {
+			return false
+		}
+		if err, ok := got.(error); ok {
+			if s, ok := want.(string); ok {
+				got = err.Error()
+				want = s
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+package logtest_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/logtest"
+	"github.com/aybabtme/grpclogrus/parser"
+	"google.golang.org/grpc/grpclog"
+)
+
+func TestCaptured(t *testing.T) {
+	var captured logtest.Captured
+	fields := logrus.Fields{parser.EventIDKey: "grpc.transport.conn_failed", "err": errors.New("connection refused"), "code": 14}
+	captured.Emit(logrus.WarnLevel, fields, "failed")
+	captured.Emit(logrus.WarnLevel, logrus.Fields{parser.EventIDKey: "grpc.transport.conn_failed"}, "failed again")
+	captured.Emit(logrus.InfoLevel, nil, "started")
+	fields["code"] = 0
+
+	entries := captured.Entries()
+	if len(entries) != 3 || entries[0].Message != "failed" || entries[2].EventID() != "" {
+		t.Fatalf("unexpected entries %v", entries)
+	}
+	if entries[0].Fields["code"] != 14 {
+		t.Errorf("want the fields copied as emitted, got %v", entries[0].Fields)
+	}
+	if !captured.HasEntry(logrus.WarnLevel, "grpc.transport.conn_failed", logrus.Fields{"err": "connection refused", "code": 14}) {
+		t.Error("want the entry found, errors equal to their message")
+	}
+	for _, subset := range []logrus.Fields{{"code": 0}, {"code": "14"}, {"peer": "10.0.0.1:443"}} {
+		if captured.HasEntry(logrus.WarnLevel, "grpc.transport.conn_failed", subset) {
+			t.Errorf("want no entry with %v", subset)
+		}
+	}
+	if captured.HasEntry(logrus.ErrorLevel, "grpc.transport.conn_failed", nil) {
+		t.Error("want no entry at another level")
+	}
+	if n := captured.Count("grpc.transport.conn_failed"); n != 2 {
+		t.Errorf("want 2 entries counted, got %d", n)
+	}
+	if e, ok := captured.Find(func(e logtest.Entry) bool { return e.Level == logrus.InfoLevel }); !ok || e.Message != "started" {
+		t.Errorf("want the info entry found, got %v", e)
+	}
+
+	captured.Reset()
+	if len(captured.Entries()) != 0 {
+		t.Error("want no entries once reset")
+	}
+}
+
+func TestCapturedGrpclog(t *testing.T) {
+	var captured logtest.Captured
+	previous := grpclogrus.SetLogger(grpclogrus.Tee(&captured))
+	defer grpclogrus.SetLogger(previous)
+	grpclog.Warningf("grpc: Server.RegisterService found duplicate service registration for %q", "foo")
+	if !captured.HasEntry(logrus.ErrorLevel, "grpc.server_registerservice_found_duplicate_service_registration", logrus.Fields{"service.name": "foo"}) {
+		t.Errorf("want the entry of grpclog captured, got %v", captured.Entries())
+	}
+}
@@ -0,0 +1,70 @@
+package grpclogrus
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataPrefix prefixes the fields of the metadata logged WithMetadata,
+// e.g. md.user-agent.
+const MetadataPrefix = "md."
+
+// WithMetadata logs the metadata of the keys given, and only those: the
+// incoming metadata of the server interceptors' calls, and the outgoing
+// metadata of the client interceptor's. Values of a key are joined with
+// commas.
+func WithMetadata(keys ...string) Option {
+	return func(o *options) {
+		for _, k := range keys {
+			o.metadataKeys = append(o.metadataKeys, strings.ToLower(k))
+		}
+	}
+}
+
+// WithMetadataFields adds the fields fn extracts from the metadata of a
+// call to its entries, as they are, e.g. the tenant_id of multi-tenant gateways. As the
+// entry of the call is stored in the context of the server's handler,
+// they are on the entries of FromContext too. Grpc's own logs don't carry
+// the context of a call, and so don't get them.
+func WithMetadataFields(fn func(md metadata.MD) logrus.Fields) Option {
+	return func(o *options) { o.metadataFields = append(o.metadataFields, fn) }
+}
+
+// withMetadata adds the allowed metadata of md to entry, and the fields
+// extracted from it.
+func (o *options) withMetadata(md metadata.MD, entry *logrus.Entry) *logrus.Entry {
+	if len(md) == 0 {
+		return entry
+	}
+	for _, fn := range o.metadataFields {
+		if fields := fn(md); len(fields) > 0 {
+			entry = entry.WithFields(fields)
+		}
+	}
+	if len(o.metadataKeys) == 0 {
+		return entry
+	}
+	fields := logrus.Fields{}
+	for _, k := range o.metadataKeys {
+		if v := md.Get(k); len(v) > 0 {
+			fields[MetadataPrefix+k] = strings.Join(v, ",")
+		}
+	}
+	if len(fields) == 0 {
+		return entry
+	}
+	return entry.WithFields(o.fields(fields))
+}
+
+func incomingMetadata(ctx context.Context) metadata.MD {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return md
+}
+
+func outgoingMetadata(ctx context.Context) metadata.MD {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	return md
+}
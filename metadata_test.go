@@ -0,0 +1,66 @@
+package grpclogrus_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestMetadataIncoming(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpc.testing.TestService/UnaryCall"}
+	l, captured := capture()
+	interceptor := grpclogrus.UnaryServerInterceptor(l,
+		grpclogrus.WithMetadata("User-Agent", "x-forwarded-for", "x-missing"),
+		grpclogrus.WithMetadataFields(func(md metadata.MD) logrus.Fields {
+			return logrus.Fields{"tenant_id": md.Get("x-tenant")[0]}
+		}),
+	)
+	ctx := incoming("user-agent", "grpc-go/1.70.0", "x-forwarded-for", "10.0.0.1", "x-forwarded-for", "10.0.0.2",
+		"authorization", "Bearer secret", "x-tenant", "acme")
+	var fromContext *logrus.Entry
+	interceptor(ctx, "req", info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+		fromContext = grpclogrus.FromContext(ctx)
+		return nil, nil
+	})
+
+	want := logrus.Fields{
+		"md.user-agent":      "grpc-go/1.70.0",
+		"md.x-forwarded-for": "10.0.0.1,10.0.0.2",
+		"tenant_id":          "acme",
+	}
+	if !captured.HasEntry(logrus.InfoLevel, "grpc.unary_call_finished", want) {
+		t.Errorf("want the allowed metadata and the fields extracted, got %v", captured.Entries())
+	}
+	for _, e := range captured.Entries() {
+		for _, k := range []string{"md.authorization", "md.x-missing", "md.x-tenant"} {
+			if _, ok := e.Fields[k]; ok {
+				t.Errorf("want only the metadata allowed, got %s", k)
+			}
+		}
+	}
+	if fromContext.Data["tenant_id"] != "acme" {
+		t.Errorf("want the fields on the entry of FromContext, got %v", fromContext.Data)
+	}
+}
+
+func TestMetadataOutgoing(t *testing.T) {
+	cc, err := grpc.NewClient("passthrough:///backend:443", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cc.Close()
+	invoker := func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error { return nil }
+
+	l, captured := capture()
+	interceptor := grpclogrus.UnaryClientInterceptor(l, grpclogrus.WithMetadata("x-tenant"), grpclogrus.WithFieldPrefix("grpc."))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-tenant", "acme")
+	interceptor(ctx, "/grpc.testing.TestService/UnaryCall", "req", nil, cc, invoker)
+	if !captured.HasEntry(logrus.InfoLevel, "grpc.unary_client_call_finished", logrus.Fields{"grpc.md.x-tenant": "acme"}) {
+		t.Errorf("want the outgoing metadata, prefixed, got %v", captured.Entries())
+	}
+}
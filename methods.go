@@ -0,0 +1,66 @@
+package grpclogrus
+
+import (
+	"regexp"
+
+	"github.com/Sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+)
+
+// methodRule is what WithMethodLevel and WithoutMethods set for the
+// methods matching re.
+type methodRule struct {
+	re    *regexp.Regexp
+	skip  bool
+	level logrus.Level
+}
+
+// WithMethodLevel logs the successful calls of the methods matching
+// pattern at level, rather than at the level of their code: for instance
+// "/billing\..*" at debug. Failed calls keep the level of their code.
+//
+// Patterns are regular expressions matched against the whole full method,
+// such as "/pkg.Service/Method", which an exact name matches as is. The
+// first rule matching a method applies, WithMethodLevel or WithoutMethods.
+// It panics if pattern doesn't compile.
+func WithMethodLevel(pattern string, level logrus.Level) Option {
+	rule := methodRule{re: compileMethod(pattern), level: level}
+	return func(o *options) { o.methods = append(o.methods, rule) }
+}
+
+// WithoutMethods doesn't log the calls of the methods matching the
+// patterns, such as "/grpc.health.v1.Health/Check" or
+// "/grpc.reflection.v1alpha.ServerReflection/.*". Patterns are as for
+// WithMethodLevel. It panics if a pattern doesn't compile.
+func WithoutMethods(patterns ...string) Option {
+	rules := make([]methodRule, 0, len(patterns))
+	for _, pattern := range patterns {
+		rules = append(rules, methodRule{re: compileMethod(pattern), skip: true})
+	}
+	return func(o *options) { o.methods = append(o.methods, rules...) }
+}
+
+func compileMethod(pattern string) *regexp.Regexp {
+	return regexp.MustCompile(`^(?:` + pattern + `)$`)
+}
+
+// method returns whether to log the calls of a method, and the level of
+// their finished entries.
+func (o *options) method(fullMethod string) (bool, func(codes.Code) logrus.Level) {
+	for _, rule := range o.methods {
+		if !rule.re.MatchString(fullMethod) {
+			continue
+		}
+		if rule.skip {
+			return false, nil
+		}
+		level := rule.level
+		return true, func(code codes.Code) logrus.Level {
+			if code == codes.OK {
+				return level
+			}
+			return o.codeLevel(code)
+		}
+	}
+	return true, o.codeLevel
+}
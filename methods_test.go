@@ -0,0 +1,65 @@
+package grpclogrus_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMethodRules(t *testing.T) {
+	l, captured := capture()
+	interceptor := grpclogrus.UnaryServerInterceptor(l, grpclogrus.WithoutStart(),
+		grpclogrus.WithoutMethods("/grpc.health.v1.Health/Check"),
+		grpclogrus.WithMethodLevel(`/billing\..*`, logrus.DebugLevel),
+		grpclogrus.WithMethodLevel(`/billing\.Invoices/.*`, logrus.TraceLevel),
+		grpclogrus.WithoutMethods(`/billing\.Invoices/List`),
+	)
+	call := func(fullMethod string, err error) []logrus.Level {
+		t.Helper()
+		captured.Reset()
+		called := false
+		interceptor(incoming(), "req", &grpc.UnaryServerInfo{FullMethod: fullMethod}, func(context.Context, interface{}) (interface{}, error) {
+			called = true
+			return nil, err
+		})
+		if !called {
+			t.Errorf("%s: want the handler called", fullMethod)
+		}
+		var levels []logrus.Level
+		for _, e := range captured.Entries() {
+			levels = append(levels, e.Level)
+		}
+		return levels
+	}
+
+	for _, tc := range []struct {
+		method string
+		err    error
+		want   []logrus.Level
+	}{
+		{"/grpc.health.v1.Health/Check", nil, nil},
+		{"/grpc.health.v1.Health/Watch", nil, []logrus.Level{logrus.InfoLevel}},
+		{"/billing.Invoices/List", nil, []logrus.Level{logrus.DebugLevel}},
+		{"/billing.Invoices/Get", status.Error(codes.Internal, "boom"), []logrus.Level{logrus.ErrorLevel}},
+		{"/xbilling.Invoices/Get", nil, []logrus.Level{logrus.InfoLevel}},
+	} {
+		got := call(tc.method, tc.err)
+		if len(got) != len(tc.want) || len(got) > 0 && got[0] != tc.want[0] {
+			t.Errorf("%s: want %v, got %v", tc.method, tc.want, got)
+		}
+	}
+}
+
+func TestMethodRulesInvalidPattern(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("want a panic for a pattern that doesn't compile")
+		}
+	}()
+	grpclogrus.WithoutMethods("/pkg.Service/(")
+}
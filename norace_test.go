@@ -0,0 +1,5 @@
+//go:build !race
+
+package grpclogrus_test
+
+const raceEnabled = false
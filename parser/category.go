@@ -0,0 +1,128 @@
+package parser
+
+import "sync/atomic"
+
+// Category classifies rules coarsely, for dashboards and routing not to
+// depend on every event id.
+type Category string
+
+// CategoryKey is the field holding the category of the rule a message
+// matched.
+const CategoryKey = "grpc.category"
+
+// Categories of rules.
+const (
+	// CategoryConnection is about dialing, listening, and transports.
+	CategoryConnection Category = "connection"
+	// CategoryRPC is about calls and streams on established connections.
+	CategoryRPC Category = "rpc"
+	// CategorySecurity is about credentials, TLS and handshakes.
+	CategorySecurity Category = "security"
+	// CategoryConfig is about the setup of clients and servers:
+	// registrations, addresses, and their data.
+	CategoryConfig Category = "config"
+	// CategoryInternal is about grpc's own tests, examples, benchmarks,
+	// and assertions on grpc's internals, which should never fire.
+	CategoryInternal Category = "internal"
+)
+
+// categories classifies the rules by event id. Rules not found are
+// internal.
+var categories = map[string]Category{
+	"grpc.clientconn_resettransport_failed":                                         CategoryConnection,
+	"grpc.clientconn_transportmonitor_exits":                                        CategoryConnection,
+	"grpc.server_serve_failed_to_create_servertransport":                            CategoryConnection,
+	"transport.http2client_notifyerror_client_transport_broken":                     CategoryConnection,
+	"transport.http2server_error":                                                   CategoryConnection,
+	"transport.http2server_handlestreams_failed_to_read_frame":                      CategoryConnection,
+	"transport.http2server_handlestreams_failed_to_receive_the_preface_from_client": CategoryConnection,
+	"transport.http2server_handlestreams_received_bogus_greeting_from_client":       CategoryConnection,
+	"transport.http2server_handlestreams_saw_invalid_preface_type_from_client":      CategoryConnection,
+	"dial":                        CategoryConnection,
+	"fail_to_dial":                CategoryConnection,
+	"failed_to_dial_please_retry": CategoryConnection,
+	"failed_to_listen":            CategoryConnection,
+	"failed_to_serve":             CategoryConnection,
+	"newclientconn_failed_to_create_a_clientconn": CategoryConnection,
+
+	"grpc.sendheader_stream_has_no_servertransport":                     CategoryRPC,
+	"grpc.server_failed_to_encode_response":                             CategoryRPC,
+	"grpc.server_handlestream_failed_to_write_status":                   CategoryRPC,
+	"grpc.server_processunaryrpc_failed_to_write_status":                CategoryRPC,
+	"transport.http2client_handlerststream_no_mapped_grpc_status":       CategoryRPC,
+	"transport.http2client_reader_got_unhandled_frame_type":             CategoryRPC,
+	"transport.http2server_handlestreams_found_unhandled_frame_type":    CategoryRPC,
+	"transport.http2server_handlestreams_received_an_illegal_stream_id": CategoryRPC,
+	"transport.http2server_operateheader_found":                         CategoryRPC,
+
+	"grpc.server_serve_failed_to_complete_security_handshake": CategorySecurity,
+	"failed_to_create_credentials":                            CategorySecurity,
+	"failed_to_create_jwt_credentials":                        CategorySecurity,
+	"failed_to_create_tls_credentials":                        CategorySecurity,
+	"failed_to_generate_credentials":                          CategorySecurity,
+	"failed_to_read_the_service_account_key_file":             CategorySecurity,
+	"got_unexpected_oauth_scope":                              CategorySecurity,
+	"tls_required_for_compute_engine_creds":                   CategorySecurity,
+	"tls_required_for_service_account_creds":                  CategorySecurity,
+
+	"grpc.server_registerservice_found_duplicate_service_registration": CategoryConfig,
+	"grpc.server_registerservice_found_unsatisfying_handler":           CategoryConfig,
+	"failed_to_load_default_features":                                  CategoryConfig,
+	"failed_to_parse_listener_address":                                 CategoryConfig,
+}
+
+// categoryOf is the category of the rule of an event id.
+func categoryOf(id string) Category {
+	if c, ok := categories[id]; ok {
+		return c
+	}
+	return CategoryInternal
+}
+
+// CategoryLevel adjusts the level of the messages of a category, after
+// that of their rule.
+type CategoryLevel struct {
+	// Min is the least severe level of the messages: those less severe
+	// are raised to it, e.g. WarnLevel for security. Zero leaves them.
+	Min Level
+	// Override is the level of all the messages, when not zero, e.g.
+	// DebugLevel for internal.
+	Override Level
+}
+
+var categoryLevels atomic.Pointer[map[Category]CategoryLevel]
+
+// SetCategoryLevels sets the levels of the messages of categories, nil
+// for those of their rules. Strict mode's level of unknown formats, which
+// have no category, isn't adjusted; nor is the level of lines whose
+// prefix gives their severity, in ParseLine.
+func SetCategoryLevels(levels map[Category]CategoryLevel) {
+	if len(levels) == 0 {
+		categoryLevels.Store(nil)
+		return
+	}
+	copied := make(map[Category]CategoryLevel, len(levels))
+	for c, l := range levels {
+		copied[c] = l
+	}
+	categoryLevels.Store(&copied)
+}
+
+// levelOf is the level of the messages of a rule, adjusted for its
+// category.
+func (c *compiledRule) levelOf() Level {
+	levels := categoryLevels.Load()
+	if levels == nil {
+		return c.level
+	}
+	adjust, ok := (*levels)[c.category]
+	switch {
+	case !ok:
+		return c.level
+	case adjust.Override != 0:
+		return adjust.Override
+	case adjust.Min != 0 && c.level > adjust.Min:
+		return adjust.Min
+	}
+	return c.level
+}
@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Fields of the messages of grpc-go's component loggers.
+const (
+	// ComponentKey is the component logging, e.g. "core" or "transport".
+	ComponentKey = "component"
+	// ComponentPrefixKey is what the component prefixed its message
+	// with, e.g. the channelz id of a channel, "Channel #5", or the
+	// address of a transport.
+	ComponentPrefixKey = "component_prefix"
+)
+
+// componentName matches the first arg of the messages of the component
+// loggers of newer grpc-go versions: the name of the component.
+var componentName = regexp.MustCompile(`^\[[\w-]+\]$`)
+
+// componentLine matches the start of the messages of the component
+// loggers: the name of the component, then an optional prefix.
+var componentLine = regexp.MustCompile(`^\[([\w-]+)\] (?:\[([^\]]+)\] ?)?`)
+
+// parseComponent structures a Println call of a component logger, the
+// name of the component first. The loggers render their messages before
+// passing them: they're matched as lines are, rather than by format.
+func parseComponent(args []interface{}) (Fields, string, Level) {
+	rendered := make([]string, len(args))
+	for i, arg := range args {
+		rendered[i] = sprint(arg)
+	}
+	line := strings.Join(rendered, " ")
+	fields, message, level, matched := matchComponent(line, componentLine.FindStringSubmatch(line), 0)
+	if !matched {
+		level = unknown(fields[MsgTemplateKey].(string), level)
+	}
+	return fields, message, level
+}
+
+// matchComponent structures a message of a component logger, m being the
+// match of componentLine. Unmatched messages get fields sized for extra
+// more.
+func matchComponent(line string, m []string, extra int) (fields Fields, message string, level Level, matched bool) {
+	fields, message, level, matched = matchLine(line[len(m[0]):], extra+2)
+	fields[ComponentKey] = m[1]
+	if m[2] != "" {
+		fields[ComponentPrefixKey] = m[2]
+	}
+	return fields, message, level, matched
+}
+
+// PackageKey is the component of the messages of the rules of older
+// grpc-go versions, which log without component loggers.
+const PackageKey = "package"
+
+// Component is the component an entry was logged by: its ComponentKey,
+// or its PackageKey for the messages of older grpc-go versions, empty if
+// it has neither.
+func Component(fields Fields) string {
+	for _, k := range [...]string{ComponentKey, PackageKey} {
+		if c, ok := fields[k]; ok {
+			return sprint(c)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package parser
+
+import "testing"
+
+func TestParselnComponent(t *testing.T) {
+	fields, message, level := Parseln("[core]", `[Server #1]grpc: Server.RegisterService found duplicate service registration for "grpc.health.v1.Health"`)
+	if fields[EventIDKey] != "grpc.server_registerservice_found_duplicate_service_registration" {
+		t.Fatalf("want the rule of the message matched, got %v", fields)
+	}
+	if fields["service.name"] != "grpc.health.v1.Health" {
+		t.Errorf("want the service name unquoted, got %v", fields["service.name"])
+	}
+	if fields[ComponentKey] != "core" || fields[ComponentPrefixKey] != "Server #1" {
+		t.Errorf("want the component and its prefix, got %v", fields)
+	}
+	if message != "Server.RegisterService found duplicate service registration" || level != ErrorLevel {
+		t.Errorf("want the message and level of the rule, got %q at %v", message, level)
+	}
+}
+
+func TestParselnComponentUnmatched(t *testing.T) {
+	fields, message, level := Parseln("[transport]", "[server-transport 0xc000] Closing:", "EOF")
+	if _, ok := fields[EventIDKey]; ok {
+		t.Errorf("want no event id, got %v", fields)
+	}
+	if message != "Closing: EOF" || level != InfoLevel {
+		t.Errorf("want the message without component at info, got %q at %v", message, level)
+	}
+	if fields[ComponentKey] != "transport" || fields[ComponentPrefixKey] != "server-transport 0xc000" {
+		t.Errorf("want the component and its prefix, got %v", fields)
+	}
+}
+
+func TestParselnComponentStrict(t *testing.T) {
+	var reported []string
+	SetStrict(true, func(format string) { reported = append(reported, format) })
+	defer SetStrict(false, nil)
+	_, _, level := Parseln("[core]", "[Channel #5]Channel created")
+	if level != ErrorLevel {
+		t.Errorf("want unmatched messages at error in strict mode, got %v", level)
+	}
+	if len(reported) != 1 || reported[0] != "Channel created" {
+		t.Errorf("want the message reported, got %q", reported)
+	}
+}
+
+func TestComponent(t *testing.T) {
+	for _, tt := range []struct {
+		fields Fields
+		want   string
+	}{
+		{Fields{ComponentKey: "core", PackageKey: "grpc"}, "core"},
+		{Fields{PackageKey: "transport"}, "transport"},
+		{Fields{}, ""},
+	} {
+		if got := Component(tt.fields); got != tt.want {
+			t.Errorf("%v: want %q, got %q", tt.fields, tt.want, got)
+		}
+	}
+}
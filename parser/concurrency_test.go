@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestParseConcurrently is meant for -race: strict mode is switched while
+// messages are parsed on many goroutines.
+func TestParseConcurrently(t *testing.T) {
+	defer SetStrict(false, nil)
+	const (
+		parsers  = 16
+		switches = 200
+	)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < parsers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if fields, _, _ := Parseln("grpc: Server.Serve failed to create ServerTransport: ", "eof"); fields[EventIDKey] != "grpc.server_serve_failed_to_create_servertransport" {
+					t.Errorf("Parseln: unexpected event id %v", fields[EventIDKey])
+				}
+				switch _, _, level := Parsef("stress: %v failed", "dial"); level {
+				case InfoLevel, ErrorLevel:
+				default:
+					t.Errorf("Parsef: unexpected level %v", level)
+				}
+				ParseLine("WARNING: 2020/01/01 00:00:00 stress: dial failed")
+				Rules()
+			}
+		}()
+	}
+	for i := 0; i < switches; i++ {
+		SetStrict(i%2 == 0, func(string) {})
+	}
+	close(done)
+	wg.Wait()
+}
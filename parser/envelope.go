@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ContainerStreamKey is the field holding the stream, stdout or stderr, a
+// container runtime captured a line from. grpc's own rules already use
+// "stream" for RPC streams.
+const ContainerStreamKey = "container_stream"
+
+// criLine matches the lines of the CRI log format written by containerd
+// and CRI-O: the time, the stream, a tag, F for full lines or P for
+// partial ones, and the line itself.
+var criLine = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}T\S+) (stdout|stderr) ([FP])(?:[:|]\S*)? (.*)$`)
+
+// dockerLine is a line of Docker's json-file logging driver, as shown by
+// kubectl logs on nodes running Docker.
+type dockerLine struct {
+	Log    *string `json:"log"`
+	Stream string  `json:"stream"`
+	Time   string  `json:"time"`
+}
+
+// Unwrapper strips the envelopes container runtimes wrap lines in: the
+// JSON objects of Docker's json-file driver, and the CRI format of
+// containerd and CRI-O. Lines the runtime split in partial pieces are
+// joined back. Lines in neither format pass through unchanged. The zero
+// value is ready to use.
+type Unwrapper struct {
+	// MaxLineLength bounds the lines unwrapped, DefaultMaxLineLength when
+	// zero. Longer lines, of pieces joined or not, are cut to it, the
+	// rest of them dropped, and their fields marked with TruncatedKey.
+	MaxLineLength int
+
+	partial map[string]partialLine
+}
+
+// partialLine is a line joined from the pieces of a stream so far.
+type partialLine struct {
+	log       string
+	truncated bool
+}
+
+// Unwrap returns the line carried by line, with the runtime's stream and
+// time as fields. It reports false for partial pieces, the line being
+// returned with its last piece.
+func (u *Unwrapper) Unwrap(line string) (string, Fields, bool) {
+	var (
+		stream, ts, log string
+		complete        bool
+	)
+	if m := criLine.FindStringSubmatch(line); m != nil {
+		ts, stream, complete, log = m[1], m[2], m[3] == "F", m[4]
+	} else if d, ok := decodeDockerLine(line); ok {
+		ts, stream, log = d.Time, d.Stream, *d.Log
+		complete = strings.HasSuffix(log, "\n")
+		log = strings.TrimRight(log, "\r\n")
+	} else {
+		return line, nil, true
+	}
+
+	p := u.partial[stream]
+	delete(u.partial, stream)
+	log, truncated := appendCut(p.log, log, maxLineLength(u.MaxLineLength))
+	truncated = truncated || p.truncated
+	if !complete {
+		if u.partial == nil {
+			u.partial = make(map[string]partialLine)
+		}
+		u.partial[stream] = partialLine{log: log, truncated: truncated}
+		return "", nil, false
+	}
+
+	fields := Fields{}
+	if truncated {
+		fields[TruncatedKey] = true
+	}
+	if stream != "" {
+		fields[ContainerStreamKey] = stream
+	}
+	if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+		fields[TimestampKey] = t
+	}
+	return log, fields, true
+}
+
+// appendCut appends add to s, of at most max bytes, cutting what's over
+// max.
+func appendCut(s, add string, max int) (string, bool) {
+	if len(s)+len(add) <= max {
+		return s + add, false
+	}
+	return s + add[:max-len(s)], true
+}
+
+func decodeDockerLine(line string) (dockerLine, bool) {
+	var d dockerLine
+	if !strings.HasPrefix(line, "{") {
+		return d, false
+	}
+	if err := json.Unmarshal([]byte(line), &d); err != nil || d.Log == nil {
+		return d, false
+	}
+	return d, true
+}
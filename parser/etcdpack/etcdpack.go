@@ -0,0 +1,71 @@
+/*
+Package etcdpack is a rule pack for the grpc-go messages of etcd
+deployments, whose servers and clientv3 embed a grpc-go version other than
+that of the rules of package parser, logging connection failures, TLS
+handshake failures and balancer and resolver updates in formats of their
+own. Load it with:
+
+	parser.LoadPack(etcdpack.Rules)
+
+The rules are valid for grpc-go v1.26, which etcd v3.4 vendors. The lease
+and watch stream errors of etcd are logged by etcd itself, through zap
+rather than grpclog: they only reach the pack as the errors of grpc's
+messages, such as the err of grpc.addrconn_createtransport_failed.
+*/
+package etcdpack
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+// Name is the source of the rules of the pack.
+const Name = "etcd@grpc-go-v1.26"
+
+// Rules is the pack.
+var Rules = parser.Pack{
+	Name: Name,
+	Parsef: map[string]parser.Rule{
+		"grpc: addrConn.createTransport failed to connect to %v. Err :%v. Reconnecting...": {ID: "grpc.addrconn_createtransport_failed", Level: parser.WarnLevel, Category: parser.CategoryConnection, Parse: func(args ...interface{}) (parser.Fields, string) {
+			return parser.Fields{"package": "grpc", "addr": address(args[0]), "err": args[1]}, "addrConn.createTransport failed to connect, reconnecting"
+		}},
+		"grpc: Server.Serve failed to complete security handshake from %q: %v": {ID: "grpc.server_serve_failed_to_complete_security_handshake", Level: parser.WarnLevel, Category: parser.CategorySecurity, Parse: func(args ...interface{}) (parser.Fields, string) {
+			return parser.Fields{"package": "grpc", "addr": args[0], "err": args[1]}, "Server.Serve failed to complete security handshake"
+		}},
+		"ccResolverWrapper: sending update to cc: %v": {ID: "grpc.ccresolverwrapper_sending_update", Level: parser.InfoLevel, Category: parser.CategoryConnection, Parse: func(args ...interface{}) (parser.Fields, string) {
+			return parser.Fields{"package": "grpc", "state": args[0]}, "ccResolverWrapper sending update to cc"
+		}},
+		"pickfirstBalancer: HandleSubConnStateChange: %p, %v": {ID: "grpc.pickfirstbalancer_subconn_state_change", Level: parser.InfoLevel, Category: parser.CategoryConnection, Parse: func(args ...interface{}) (parser.Fields, string) {
+			return parser.Fields{"package": "grpc", "subconn": pointer(args[0]), "state": args[1]}, "pickfirstBalancer subconn state changed"
+		}},
+		"Subchannel Connectivity change to %v": {ID: "grpc.subchannel_connectivity_change", Level: parser.InfoLevel, Category: parser.CategoryConnection, Parse: func(args ...interface{}) (parser.Fields, string) {
+			return parser.Fields{"package": "grpc", "state": args[0]}, "Subchannel connectivity changed"
+		}},
+		"Channel Connectivity change to %v": {ID: "grpc.channel_connectivity_change", Level: parser.InfoLevel, Category: parser.CategoryConnection, Parse: func(args ...interface{}) (parser.Fields, string) {
+			return parser.Fields{"package": "grpc", "state": args[0]}, "Channel connectivity changed"
+		}},
+	},
+}
+
+// address returns the Addr of a resolver.Address, read by reflection not
+// to depend on the grpc-go version it comes from, or v itself, as when
+// ParseLine passes its rendering.
+func address(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Struct {
+		if addr := rv.FieldByName("Addr"); addr.IsValid() && addr.Kind() == reflect.String {
+			return addr.String()
+		}
+	}
+	return v
+}
+
+// pointer renders v as %p does, but for its rendering.
+func pointer(v interface{}) interface{} {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%p", v)
+}
@@ -0,0 +1,121 @@
+package parser
+
+import "strings"
+
+// StackKey is the field holding the continuation lines of an entry, such
+// as a stack trace or a wrapped error.
+const StackKey = "stack"
+
+// TruncatedKey is set on the entries of which a line was cut short for
+// being longer than the MaxLineLength of the Parser reading them.
+const TruncatedKey = "truncated"
+
+// Entry is a parsed entry of grpclog output.
+type Entry struct {
+	Fields  Fields
+	Message string
+	Level   Level
+	// Matched reports whether a rule matched the message.
+	Matched bool
+}
+
+// Folder parses raw lines into entries, folding multi-line entries. A
+// line continues the previous entry when it's indented or blank, or when
+// it has no prefix while the entry's first line had one. Lines wrapped by
+// a container runtime are unwrapped first, the runtime's stream and time
+// becoming fields of the entry. The zero value is ready to use.
+type Folder struct {
+	// MaxLineLength bounds the lines unwrapped, and the stack of an
+	// entry, DefaultMaxLineLength when zero. What's over it is cut, and
+	// the entry marked with TruncatedKey. Set before use.
+	MaxLineLength int
+
+	unwrapper Unwrapper
+	head      string
+	envelope  Fields
+	prefixed  bool
+	stack     []string
+	stackLen  int
+	pending   bool
+	truncated bool
+}
+
+// Push a raw line. When it starts a new entry, the previous one is
+// complete and returned.
+func (f *Folder) Push(line string) (Entry, bool) {
+	f.unwrapper.MaxLineLength = f.MaxLineLength
+	line, envelope, ok := f.unwrapper.Unwrap(strings.TrimRight(line, "\r\n"))
+	if !ok {
+		return Entry{}, false
+	}
+	if f.pending && f.continues(line) {
+		if envelope[TruncatedKey] == true {
+			f.truncated = true
+		}
+		f.appendStack(line)
+		return Entry{}, false
+	}
+	e, ok := f.Flush()
+	if strings.TrimSpace(line) != "" {
+		f.head, f.envelope, f.pending = line, envelope, true
+		f.prefixed = prefix.FindString(line) != ""
+	}
+	return e, ok
+}
+
+// Flush returns the pending entry, if any, as complete.
+func (f *Folder) Flush() (Entry, bool) {
+	if !f.pending {
+		return Entry{}, false
+	}
+	var e Entry
+	e.Fields, e.Message, e.Level, e.Matched = ParseLine(f.head)
+	stack := f.stack
+	for len(stack) > 0 && strings.TrimSpace(stack[len(stack)-1]) == "" {
+		stack = stack[:len(stack)-1]
+	}
+	if len(stack) > 0 {
+		e.Fields[StackKey] = strings.Join(stack, "\n")
+	}
+	for k, v := range f.envelope {
+		if _, ok := e.Fields[k]; !ok {
+			e.Fields[k] = v
+		}
+	}
+	if f.truncated {
+		e.Fields[TruncatedKey] = true
+	}
+	f.head, f.envelope, f.pending, f.stack = "", nil, false, f.stack[:0]
+	f.stackLen, f.truncated = 0, false
+	return e, true
+}
+
+// Truncate marks the pending entry, if any, with TruncatedKey, for the
+// readers cutting a line short to push it.
+func (f *Folder) Truncate() {
+	if f.pending {
+		f.truncated = true
+	}
+}
+
+// appendStack adds a continuation line to the stack of the pending entry,
+// cutting what's over MaxLineLength.
+func (f *Folder) appendStack(line string) {
+	max := maxLineLength(f.MaxLineLength)
+	if f.stackLen >= max {
+		f.truncated = true
+		return
+	}
+	if f.stackLen+len(line) > max {
+		line, f.truncated = line[:max-f.stackLen], true
+	}
+	f.stack = append(f.stack, line)
+	f.stackLen += len(line) + 1
+}
+
+func (f *Folder) continues(line string) bool {
+	if strings.TrimSpace(line) == "" || line[0] == ' ' || line[0] == '\t' {
+		return true
+	}
+	return f.prefixed && prefix.FindString(line) == ""
+}
@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of testdata")
+
+// golden is the content of a .golden.json file: the entries ParseLine
+// returns for each line of a .log file, and those Parser returns for the
+// whole of it.
+type golden struct {
+	Lines   []goldenEntry `json:"lines"`
+	Entries []goldenEntry `json:"entries"`
+}
+
+type goldenEntry struct {
+	Line    string                 `json:"line,omitempty"`
+	Message string                 `json:"message"`
+	Level   string                 `json:"level"`
+	Matched bool                   `json:"matched"`
+	Fields  map[string]interface{} `json:"fields"`
+}
+
+func newGoldenEntry(line string, fields Fields, message string, level Level, matched bool) goldenEntry {
+	e := goldenEntry{
+		Line:    line,
+		Message: message,
+		Level:   level.String(),
+		Matched: matched,
+		Fields:  make(map[string]interface{}, len(fields)),
+	}
+	for k, v := range fields {
+		switch v := v.(type) {
+		case time.Time:
+			// Without the zone, for timestamps parsed in time.Local to
+			// read the same everywhere.
+			e.Fields[k] = v.Format("2006-01-02T15:04:05.999999999")
+		case typeName:
+			e.Fields[k] = string(v)
+		default:
+			e.Fields[k] = v
+		}
+	}
+	return e
+}
+
+func parseGolden(t *testing.T, log string) golden {
+	var g golden
+	for _, line := range strings.Split(strings.TrimRight(log, "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields, message, level, matched := ParseLine(line)
+		g.Lines = append(g.Lines, newGoldenEntry(line, fields, message, level, matched))
+	}
+	var p Parser
+	err := p.Run(context.Background(), strings.NewReader(log), func(e Entry) error {
+		g.Entries = append(g.Entries, newGoldenEntry("", e.Fields, e.Message, e.Level, e.Matched))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestGolden(t *testing.T) {
+	logs, err := filepath.Glob(filepath.Join("testdata", "*.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) == 0 {
+		t.Fatal("no testdata")
+	}
+	for _, log := range logs {
+		t.Run(filepath.Base(log), func(t *testing.T) {
+			in, err := os.ReadFile(log)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.MarshalIndent(parseGolden(t, string(in)), "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			path := strings.TrimSuffix(log, ".log") + ".golden.json"
+			if *update {
+				if err := os.WriteFile(path, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v: run go test -run Golden -update", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s differs from the entries parsed: run go test -run Golden -update and review the diff", path)
+			}
+		})
+	}
+}
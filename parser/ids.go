@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"hash/fnv"
+	"reflect"
+	"strconv"
+)
+
+// Fields identifying the stream and connection of a message, for related
+// messages to be correlated: the id of the stream when it has one, or
+// else a hash of the pointer grpc logged, stable for the process' life.
+const (
+	StreamIDKey = "stream.id"
+	ConnIDKey   = "conn.id"
+)
+
+// idFields are the fields of the rules holding streams and connections,
+// and the id fields derived from them.
+var idFields = map[string]string{
+	"stream":              StreamIDKey,
+	"s.ServerTransport()": ConnIDKey,
+}
+
+// addIDs adds the id fields of the streams and connections of fields,
+// unless the rule set them already.
+func addIDs(fields Fields) {
+	for k, idKey := range idFields {
+		v, ok := fields[k]
+		if !ok {
+			continue
+		}
+		if _, ok := fields[idKey]; ok {
+			continue
+		}
+		if id, ok := identify(v); ok {
+			fields[idKey] = id
+		}
+	}
+}
+
+// identify is the id of v, a pointer to a struct with an integer id
+// field, as grpc's streams have, or else a hash of the pointer. Values
+// rendered in lines aren't pointers anymore, and have none.
+func identify(v interface{}) (string, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return "", false
+	}
+	if elem := rv.Elem(); elem.Kind() == reflect.Struct {
+		switch id := elem.FieldByName("id"); id.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return strconv.FormatInt(id.Int(), 10), true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return strconv.FormatUint(id.Uint(), 10), true
+		}
+	}
+	h := fnv.New64a()
+	var b [8]byte
+	p := uint64(rv.Pointer())
+	for i := range b {
+		b[i] = byte(p >> (8 * i))
+	}
+	h.Write(b[:])
+	return strconv.FormatUint(h.Sum64(), 16), true
+}
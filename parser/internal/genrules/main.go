@@ -0,0 +1,235 @@
+/*
+Genrules generates the fillers of the rules of package parser, from
+parser/rules.go to parser/rules_gen.go: a function per rule, filling the
+fields of a message in place, which the parser calls rather than the
+rule's closure to structure messages into pooled fields.
+
+Rules returning a Fields literal, or nil, with the message, get one.
+Others are skipped, the parser calling their closure. Run it with go
+generate in package parser.
+*/
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	in  = "rules.go"
+	out = "rules_gen.go"
+)
+
+func main() {
+	src, err := os.ReadFile(in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	gen, err := generate(src)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(out, gen, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// tables are the rule maps of rules.go, and the fillers generated for
+// them.
+var tables = []struct{ rules, fillers string }{
+	{"parsefRules", "parsefFillers"},
+	{"parselnRules", "parselnFillers"},
+}
+
+// filler is a generated function.
+type filler struct {
+	format, name string
+	// fields are the statements filling the fields.
+	fields []string
+	// message is the expression of the message.
+	message string
+}
+
+// generate returns the source of rules_gen.go for that of rules.go.
+func generate(src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, in, src, 0)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by genrules from rules.go. DO NOT EDIT.\n\npackage parser\n")
+	for _, table := range tables {
+		lit := ruleMap(file, table.rules)
+		if lit == nil {
+			return nil, fmt.Errorf("%s: no %s", in, table.rules)
+		}
+		var fillers []filler
+		for _, elt := range lit.Elts {
+			f, ok, err := newFiller(fset, elt)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			// Rules may share an id, such as the formats differing in
+			// case only.
+			for base, n := f.name, 2; names[f.name]; n++ {
+				f.name = base + strconv.Itoa(n)
+			}
+			names[f.name] = true
+			fillers = append(fillers, f)
+		}
+		sort.Slice(fillers, func(i, j int) bool { return fillers[i].format < fillers[j].format })
+
+		fmt.Fprintf(&buf, "\nvar %s = map[string]filler{\n", table.fillers)
+		for _, f := range fillers {
+			fmt.Fprintf(&buf, "%q: %s,\n", f.format, f.name)
+		}
+		buf.WriteString("}\n")
+		for _, f := range fillers {
+			fmt.Fprintf(&buf, "\n// %s fills the fields of %q.\n", f.name, f.format)
+			fmt.Fprintf(&buf, "func %s(fields Fields, args []interface{}) string {\n", f.name)
+			for _, stmt := range f.fields {
+				buf.WriteString(stmt + "\n")
+			}
+			fmt.Fprintf(&buf, "return %s\n}\n", f.message)
+		}
+	}
+	return format.Source(buf.Bytes())
+}
+
+// ruleMap finds the composite literal of the rule map of that name.
+func ruleMap(file *ast.File, name string) *ast.CompositeLit {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, id := range vs.Names {
+				if id.Name != name || i >= len(vs.Values) {
+					continue
+				}
+				lit, _ := vs.Values[i].(*ast.CompositeLit)
+				return lit
+			}
+		}
+	}
+	return nil
+}
+
+// newFiller makes the filler of a rule of a rule map, reporting false
+// for rules of another shape than:
+//
+//	"format": {"id", Level, func(args ...interface{}) (Fields, string) {
+//		return Fields{...}, "message"
+//	}},
+func newFiller(fset *token.FileSet, elt ast.Expr) (f filler, ok bool, err error) {
+	kv, isKV := elt.(*ast.KeyValueExpr)
+	if !isKV {
+		return f, false, nil
+	}
+	key, isLit := kv.Key.(*ast.BasicLit)
+	if !isLit || key.Kind != token.STRING {
+		return f, false, nil
+	}
+	if f.format, err = strconv.Unquote(key.Value); err != nil {
+		return f, false, err
+	}
+	r, isLit := kv.Value.(*ast.CompositeLit)
+	if !isLit || len(r.Elts) != 3 {
+		return f, false, nil
+	}
+	id, isLit := r.Elts[0].(*ast.BasicLit)
+	fn, isFunc := r.Elts[2].(*ast.FuncLit)
+	if !isLit || id.Kind != token.STRING || !isFunc || !takesArgs(fn) || len(fn.Body.List) != 1 {
+		return f, false, nil
+	}
+	ret, isRet := fn.Body.List[0].(*ast.ReturnStmt)
+	if !isRet || len(ret.Results) != 2 {
+		return f, false, nil
+	}
+	switch fields := ret.Results[0].(type) {
+	case *ast.Ident:
+		if fields.Name != "nil" {
+			return f, false, nil
+		}
+	case *ast.CompositeLit:
+		if t, isIdent := fields.Type.(*ast.Ident); !isIdent || t.Name != "Fields" {
+			return f, false, nil
+		}
+		for _, elt := range fields.Elts {
+			field, isKV := elt.(*ast.KeyValueExpr)
+			if !isKV {
+				return f, false, nil
+			}
+			f.fields = append(f.fields, fmt.Sprintf("fields[%s] = %s", source(fset, field.Key), source(fset, field.Value)))
+		}
+	default:
+		return f, false, nil
+	}
+	f.message = source(fset, ret.Results[1])
+	name, err := strconv.Unquote(id.Value)
+	if err != nil {
+		return f, false, err
+	}
+	f.name = "fill" + camel(name)
+	return f, true, nil
+}
+
+// takesArgs reports whether fn takes its args as "args ...interface{}",
+// as the fillers do, but as a slice.
+func takesArgs(fn *ast.FuncLit) bool {
+	params := fn.Type.Params.List
+	if len(params) != 1 || len(params[0].Names) != 1 || params[0].Names[0].Name != "args" {
+		return false
+	}
+	ellipsis, ok := params[0].Type.(*ast.Ellipsis)
+	if !ok {
+		return false
+	}
+	iface, ok := ellipsis.Elt.(*ast.InterfaceType)
+	return ok && len(iface.Methods.List) == 0
+}
+
+// source prints node as gofmt does.
+func source(fset *token.FileSet, node ast.Node) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, node); err != nil {
+		log.Fatal(err)
+	}
+	return buf.String()
+}
+
+// camel converts an event id to camel case: "grpc.server_failed" to
+// "GrpcServerFailed".
+func camel(id string) string {
+	var b strings.Builder
+	upper := true
+	for _, c := range id {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			upper = true
+			continue
+		}
+		if upper {
+			c = unicode.ToUpper(c)
+			upper = false
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
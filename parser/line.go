@@ -0,0 +1,206 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// typeName is a type name read from a rendered %T verb.
+type typeName string
+
+// typeOf renders v as %T would, unless v was read from a rendered %T.
+func typeOf(v interface{}) string {
+	if t, ok := v.(typeName); ok {
+		return string(t)
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// linePattern matches lines rendered from the format of a rule, capturing
+// the rendered args.
+type linePattern struct {
+	format  string
+	re      *regexp.Regexp
+	verbs   []byte
+	literal int
+	rule    *compiledRule
+}
+
+// compileLinePatterns compiles the patterns of the rules of a set, sorted
+// from the most to the least specific.
+func compileLinePatterns(set *ruleSet) []linePattern {
+	var patterns []linePattern
+	for format, r := range set.parsef {
+		patterns = append(patterns, compileFormat(format, r))
+	}
+	for format, r := range set.parseln {
+		patterns = append(patterns, linePattern{
+			format:  format,
+			re:      regexp.MustCompile(`^` + regexp.QuoteMeta(strings.TrimSpace(format)) + `(?:\s+(.*))?$`),
+			verbs:   []byte{'v'},
+			literal: len(format),
+			rule:    r,
+		})
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if patterns[i].literal != patterns[j].literal {
+			return patterns[i].literal > patterns[j].literal
+		}
+		return patterns[i].format < patterns[j].format
+	})
+	return patterns
+}
+
+func compileFormat(format string, r *compiledRule) linePattern {
+	p := linePattern{format: format, rule: r}
+	var expr strings.Builder
+	expr.WriteByte('^')
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c != '%' || i+1 == len(format) {
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+			p.literal++
+			continue
+		}
+		i++
+		switch verb := format[i]; verb {
+		case '%':
+			expr.WriteString(`%`)
+			p.literal++
+		case 'd':
+			expr.WriteString(`(-?\d+)`)
+			p.verbs = append(p.verbs, verb)
+		case 'q':
+			expr.WriteString(`("(?:[^"\\]|\\.)*"|` + "`[^`]*`" + `)`)
+			p.verbs = append(p.verbs, verb)
+		case 'T':
+			expr.WriteString(`(\S+)`)
+			p.verbs = append(p.verbs, verb)
+		default:
+			expr.WriteString(`(.*?)`)
+			p.verbs = append(p.verbs, verb)
+		}
+	}
+	expr.WriteByte('$')
+	p.re = regexp.MustCompile(expr.String())
+	return p
+}
+
+// args converts the rendered args back to the types of their verbs, as
+// far as possible.
+func (p *linePattern) args(rendered []string) []interface{} {
+	args := make([]interface{}, 0, len(rendered))
+	for i, s := range rendered {
+		switch p.verbs[i] {
+		case 'd':
+			if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+				args = append(args, n)
+				continue
+			}
+		case 'q':
+			if u, err := strconv.Unquote(s); err == nil {
+				args = append(args, u)
+				continue
+			}
+		case 'T':
+			args = append(args, typeName(s))
+			continue
+		}
+		args = append(args, s)
+	}
+	return args
+}
+
+// Fields extracted from the prefix of raw lines.
+const (
+	TimestampKey = "timestamp"
+	SeverityKey  = "severity"
+	CallerKey    = "caller"
+)
+
+// prefix matches what grpclog's loggers prepend to messages: an optional
+// severity, the date and time, and an optional file:line, as with
+// log.Lshortfile. The LoggerV2 default of older grpc-go versions writes
+// the severity before the date, that of newer ones after it.
+var prefix = regexp.MustCompile(`^(?:(INFO|WARNING|ERROR|FATAL): )?(?:(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}(?:\.\d+)?) )?(?:(INFO|WARNING|ERROR|FATAL): )?(?:(\S+\.go:\d+): )?`)
+
+var severities = map[string]Level{
+	"INFO":    InfoLevel,
+	"WARNING": WarnLevel,
+	"ERROR":   ErrorLevel,
+	"FATAL":   FatalLevel,
+}
+
+// ParseLine structures a line of grpclog output, as rendered by grpclog's
+// default loggers. The prefix is stripped, its timestamp, severity and
+// caller becoming fields; the severity, when present, overrides the level
+// of the rule matched. Lines matching no rule are returned as the
+// message, at InfoLevel unless their prefix says otherwise, with matched
+// false. The component and prefix of the lines of component loggers are
+// stripped too, becoming fields.
+func ParseLine(line string) (fields Fields, message string, level Level, matched bool) {
+	line = strings.TrimRight(line, "\r\n")
+	pre := prefix.FindStringSubmatch(line)
+	line = line[len(pre[0]):]
+
+	if m := componentLine.FindStringSubmatch(line); m != nil {
+		fields, message, level, matched = matchComponent(line, m, len(pre))
+	} else {
+		fields, message, level, matched = matchLine(line, len(pre))
+	}
+	if severity := pre[1] + pre[3]; severity != "" {
+		fields[SeverityKey] = severity
+		level = severities[severity]
+	}
+	if ts := pre[2]; ts != "" {
+		if t, err := time.ParseInLocation("2006/01/02 15:04:05", ts, time.Local); err == nil {
+			fields[TimestampKey] = t
+		}
+	}
+	if caller := pre[4]; caller != "" {
+		fields[CallerKey] = caller
+	}
+	return fields, message, level, matched
+}
+
+// matchLine structures a message rendered from its format and args, by
+// the first rule matching it. Unmatched messages get fields sized for
+// extra more, holding their template.
+func matchLine(line string, extra int) (fields Fields, message string, level Level, matched bool) {
+	lines := rules.Load().lines
+	for i := range lines {
+		p := &lines[i]
+		m := p.re.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if f, msg, ok := p.apply(m[1:]); ok {
+			return f, msg, p.rule.levelOf(), true
+		}
+	}
+	fields = make(Fields, extra+1)
+	fields[MsgTemplateKey] = normalizeTemplate(line)
+	return fields, line, InfoLevel, false
+}
+
+// apply reports false when the rule can't handle the rendered args.
+func (p *linePattern) apply(rendered []string) (fields Fields, message string, ok bool) {
+	defer func() {
+		if recover() != nil {
+			atomic.AddUint64(&rulePanics, 1)
+			ok = false
+		}
+	}()
+	fields, message = p.rule.structure(p.args(rendered))
+	if fields == nil {
+		// ParseLine adds the fields of the prefix: don't share.
+		fields = newFields()
+	}
+	// Rendered args are strings: there's no status to look for.
+	return p.rule.matched(fields, nil), message, true
+}
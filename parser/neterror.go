@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"crypto/x509"
+	"errors"
+	"net"
+)
+
+// Fields of the first error arg of a message wrapping a *net.OpError, a
+// net.Error, or a failure to verify a certificate, instead of their
+// opaque message alone.
+const (
+	NetOpKey      = "net.op"
+	NetworkKey    = "net.network"
+	RemoteAddrKey = "net.remote_addr"
+	TimeoutKey    = "net.timeout"
+	CertErrorKey  = "tls.cert_error"
+)
+
+// certReasons name the reasons a certificate is invalid.
+var certReasons = map[x509.InvalidReason]string{
+	x509.NotAuthorizedToSign:           "not_authorized_to_sign",
+	x509.Expired:                       "expired",
+	x509.CANotAuthorizedForThisName:    "ca_not_authorized_for_name",
+	x509.TooManyIntermediates:          "too_many_intermediates",
+	x509.IncompatibleUsage:             "incompatible_usage",
+	x509.NameMismatch:                  "name_mismatch",
+	x509.NameConstraintsWithoutSANs:    "name_constraints_without_sans",
+	x509.UnconstrainedName:             "unconstrained_name",
+	x509.TooManyConstraints:            "too_many_constraints",
+	x509.CANotAuthorizedForExtKeyUsage: "ca_not_authorized_for_ext_key_usage",
+	x509.NoValidChains:                 "no_valid_chains",
+}
+
+// netError is the first error of args with details to extract, if any.
+func netError(args []interface{}) error {
+	for _, arg := range args {
+		err, ok := arg.(error)
+		if !ok || err == nil {
+			continue
+		}
+		var netErr net.Error
+		if errors.As(err, &netErr) || certError(err) != "" {
+			return err
+		}
+	}
+	return nil
+}
+
+// addNetError adds the details of err, unless the rule set them already.
+func addNetError(fields Fields, err error) {
+	if _, ok := fields[NetOpKey]; ok {
+		return
+	}
+	var op *net.OpError
+	if errors.As(err, &op) {
+		fields[NetOpKey] = op.Op
+		fields[NetworkKey] = op.Net
+		if op.Addr != nil {
+			fields[RemoteAddrKey] = op.Addr.String()
+		}
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		fields[TimeoutKey] = netErr.Timeout()
+	}
+	if reason := certError(err); reason != "" {
+		fields[CertErrorKey] = reason
+	}
+}
+
+// certError names the reason err failed to verify a certificate, empty if
+// it didn't.
+func certError(err error) string {
+	var (
+		unknown  x509.UnknownAuthorityError
+		hostname x509.HostnameError
+		invalid  x509.CertificateInvalidError
+		roots    x509.SystemRootsError
+	)
+	switch {
+	case errors.As(err, &unknown):
+		return "unknown_authority"
+	case errors.As(err, &hostname):
+		return "hostname_mismatch"
+	case errors.As(err, &invalid):
+		if reason, ok := certReasons[invalid.Reason]; ok {
+			return reason
+		}
+		return "invalid"
+	case errors.As(err, &roots):
+		return "system_roots"
+	}
+	return ""
+}
@@ -0,0 +1,98 @@
+package parser
+
+import "sync"
+
+// Rule structures the messages of a format, for packs.
+type Rule struct {
+	// ID is the event id of the messages.
+	ID string
+	// Level is the level of the messages, before their category's.
+	Level Level
+	// Category classifies the messages, CategoryInternal when empty.
+	Category Category
+	// Parse returns the fields and message of the args of a message. It
+	// must not keep args, and may return nil fields.
+	Parse func(args ...interface{}) (Fields, string)
+}
+
+// Pack is a set of rules for the grpc-go messages of a deployment, e.g.
+// those of the grpc-go versions a project vendors, loaded with LoadPack.
+type Pack struct {
+	// Name is the source of the rules, in Rules.
+	Name string
+	// Parsef are the rules of Parsef, by format.
+	Parsef map[string]Rule
+	// Parseln are the rules of Parseln, by their first arg.
+	Parseln map[string]Rule
+}
+
+// rules converts the rules of the pack, returning the categories of
+// their ids.
+func (p Pack) rules() (parsef, parseln map[string]rule, category func(id string) Category) {
+	categories := make(map[string]Category)
+	convert := func(rules map[string]Rule) map[string]rule {
+		converted := make(map[string]rule, len(rules))
+		for format, r := range rules {
+			converted[format] = rule{r.ID, r.Level, r.Parse}
+			if r.Category != "" {
+				categories[r.ID] = r.Category
+			}
+		}
+		return converted
+	}
+	parsef, parseln = convert(p.Parsef), convert(p.Parseln)
+	return parsef, parseln, func(id string) Category {
+		if c, ok := categories[id]; ok {
+			return c
+		}
+		return CategoryInternal
+	}
+}
+
+var (
+	packsMu sync.Mutex
+	packs   []Pack
+)
+
+// LoadPack adds the rules of a pack to those of this package, replacing
+// the rules of the same formats, and those of a pack loaded before under
+// the same name. It may be called while messages are parsed. The rules
+// kept from the set before, of the same format, id and source, keep
+// their match counts; those of the others start from zero.
+func LoadPack(p Pack) {
+	ReplacePacks(nil, p)
+}
+
+// UnloadPack removes the rules of the pack loaded under that name, if
+// any, restoring those it replaced. The match counts are kept as with
+// LoadPack.
+func UnloadPack(name string) {
+	ReplacePacks([]string{name})
+}
+
+// ReplacePacks unloads the packs of names and loads load, in order, as
+// UnloadPack and LoadPack do, but at once: messages are parsed by the
+// rules of before or by those of after, never by those of a pack loaded
+// without the others.
+func ReplacePacks(names []string, load ...Pack) {
+	packsMu.Lock()
+	defer packsMu.Unlock()
+	unloaded := make(map[string]bool, len(names)+len(load))
+	for _, name := range names {
+		unloaded[name] = true
+	}
+	for _, p := range load {
+		unloaded[p.Name] = true
+	}
+	loaded := make([]Pack, 0, len(packs)+len(load))
+	for _, prev := range packs {
+		if !unloaded[prev.Name] {
+			loaded = append(loaded, prev)
+		}
+	}
+	if len(loaded) == len(packs) && len(load) == 0 {
+		return
+	}
+	packs = append(loaded, load...)
+	rules.Store(newRuleSet(packs, rules.Load()))
+}
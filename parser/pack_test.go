@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"sync"
+	"testing"
+)
+
+const stressFormat = "grpc: Server.Serve failed to create ServerTransport: "
+
+var stressPacks = []Pack{
+	{Name: "a", Parseln: map[string]Rule{
+		stressFormat: {ID: "a.transport", Level: WarnLevel, Parse: func(args ...interface{}) (Fields, string) {
+			return Fields{"err": args[0]}, "a"
+		}},
+	}},
+	{Name: "b", Parsef: map[string]Rule{
+		"stress: %v failed": {ID: "b.failed", Level: ErrorLevel, Category: CategoryConnection, Parse: func(args ...interface{}) (Fields, string) {
+			return Fields{"what": args[0]}, "b"
+		}},
+	}},
+}
+
+// unloadPacks restores the rules of this package when the test ends.
+func unloadPacks(t *testing.T) {
+	t.Cleanup(func() {
+		packsMu.Lock()
+		defer packsMu.Unlock()
+		packs = nil
+		rules.Store(newRuleSet(nil, nil))
+	})
+}
+
+func TestLoadPack(t *testing.T) {
+	unloadPacks(t)
+	LoadPack(stressPacks[0])
+	fields, message, level := Parseln(stressFormat, "eof")
+	if fields[EventIDKey] != "a.transport" || message != "a" || level != WarnLevel {
+		t.Errorf("want the rule of the pack, got %v %q %v", fields, message, level)
+	}
+	LoadPack(Pack{Name: "a"})
+	if fields, _, _ := Parseln(stressFormat, "eof"); fields[EventIDKey] != "grpc.server_serve_failed_to_create_servertransport" {
+		t.Errorf("want the builtin rule back once the pack is replaced, got %v", fields)
+	}
+	LoadPack(stressPacks[0])
+	UnloadPack("a")
+	if fields, _, _ := Parseln(stressFormat, "eof"); fields[EventIDKey] != "grpc.server_serve_failed_to_create_servertransport" {
+		t.Errorf("want the builtin rule back once the pack is unloaded, got %v", fields)
+	}
+	for _, r := range Rules() {
+		if r.Source == "a" {
+			t.Errorf("want no rule left of the replaced pack, got %v", r)
+		}
+	}
+}
+
+func TestReplacePacks(t *testing.T) {
+	unloadPacks(t)
+	LoadPack(stressPacks[0])
+	Parseln(stressFormat, "eof")
+	ReplacePacks([]string{"a"}, stressPacks...)
+	if fields, _, _ := Parsef("stress: %v failed", "dial"); fields[EventIDKey] != "b.failed" {
+		t.Errorf("want the rule of the pack loaded, got %v", fields)
+	}
+	for _, r := range Rules() {
+		if r.ID == "a.transport" && r.Matches != 1 {
+			t.Errorf("want the match of the rule kept across the swap, got %d", r.Matches)
+		}
+	}
+	ReplacePacks([]string{"a", "b"})
+	for _, r := range Rules() {
+		if r.Source == "a" || r.Source == "b" {
+			t.Errorf("want no rule left of the packs unloaded, got %v", r)
+		}
+	}
+}
+
+// TestLoadPackConcurrently is meant for -race: packs are loaded while
+// messages are parsed, each message parsed by one set or the other.
+func TestLoadPackConcurrently(t *testing.T) {
+	unloadPacks(t)
+	const (
+		parsers = 16
+		loads   = 40
+	)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < parsers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				switch fields, _, _ := Parseln(stressFormat, "eof"); fields[EventIDKey] {
+				case "a.transport", "grpc.server_serve_failed_to_create_servertransport":
+				default:
+					t.Errorf("Parseln: unexpected event id %v", fields[EventIDKey])
+				}
+				switch fields, _, _ := Parsef("stress: %v failed", "dial"); fields[EventIDKey] {
+				case "b.failed", nil:
+				default:
+					t.Errorf("Parsef: unexpected event id %v", fields[EventIDKey])
+				}
+				ParseLine("WARNING: 2020/01/01 00:00:00 stress: dial failed")
+				Rules()
+			}
+		}()
+	}
+	for i := 0; i < loads; i++ {
+		LoadPack(stressPacks[i%len(stressPacks)])
+		if i%5 == 0 {
+			LoadPack(Pack{Name: stressPacks[i%len(stressPacks)].Name})
+		}
+	}
+	close(done)
+	wg.Wait()
+}
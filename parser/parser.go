@@ -0,0 +1,206 @@
+/*
+Package parser structures grpc-go log messages, independently of the
+logging library they end up in.
+
+The parsing rules should be valid for grpc-go checked out
+at commit 91c8b79535eb6045d70ec671d302213f88a3ab95.
+
+The functions of the package are safe for concurrent use. The rules are
+held in an immutable set that Parsef, Parseln and ParseLine load once per
+message, without locking; LoadPack and ReplacePacks, serialized by a
+mutex, build a new set and swap it in atomically. A message parsed
+during a swap is parsed entirely by the old set or the new one, never a
+mix of both. SetStrict swaps the strict mode in atomically too.
+*/
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"sync/atomic"
+)
+
+// RulePack names the rules of this package: those of grpc-go's messages
+// at the commit they were written for.
+const RulePack = "grpc-go@91c8b79535eb"
+
+// Fields are the structured values extracted from a message.
+type Fields map[string]interface{}
+
+// EventIDKey is the field holding the id of the rule a message matched.
+// Messages matching no rule don't have it.
+const EventIDKey = "event_id"
+
+// ParseErrorKey is set on the messages matching the format of a rule but
+// given fewer args than it takes, which are structured as unmatched
+// messages are, keeping the id of the rule.
+const ParseErrorKey = "parse_error"
+
+// Level is the severity of a message. Levels share logrus' numbering.
+type Level uint32
+
+// Levels, from the most to the least severe.
+const (
+	FatalLevel Level = iota + 1
+	ErrorLevel
+	WarnLevel
+	InfoLevel
+	DebugLevel
+)
+
+func (l Level) String() string {
+	switch l {
+	case FatalLevel:
+		return "fatal"
+	case ErrorLevel:
+		return "error"
+	case WarnLevel:
+		return "warning"
+	case InfoLevel:
+		return "info"
+	case DebugLevel:
+		return "debug"
+	}
+	return "unknown"
+}
+
+// Parsef structures a grpclog Printf/Fatalf call. Formats without a
+// known rule fall back to the format itself, with args as arg0..argN,
+// at InfoLevel, or ErrorLevel in strict mode. Callers handling a Fatal
+// call should log at FatalLevel regardless of the level returned. The
+// fields of rules extracting none are shared between calls, see Shared:
+// callers must not modify fields.
+func Parsef(format string, args ...interface{}) (fields Fields, message string, level Level) {
+	rule, ok := rules.Load().parsef[format]
+	if !ok {
+		return unknownFormat(format, args, format)
+	}
+	if len(args) < rule.argc {
+		return rule.degraded(format, args)
+	}
+	defer func() {
+		if e := recover(); e != nil {
+			atomic.AddUint64(&rulePanics, 1)
+			fields, message, level = defaultParsef(format, args...)
+		}
+	}()
+	fields, message = rule.structure(args)
+	return rule.matched(fields, args), message, rule.levelOf()
+}
+
+// Parseln structures a grpclog Print/Println/Fatal/Fatalln call, using
+// the first arg as the format. The calls of the component loggers of
+// newer grpc-go versions, of which the first arg is the name of the
+// component, e.g. "[core]", are matched by their message, the component
+// becoming a field.
+func Parseln(args ...interface{}) (fields Fields, message string, level Level) {
+	if len(args) < 1 {
+		return noFields, "", InfoLevel
+	}
+	format := sprint(args[0])
+	if len(args) > 1 && componentName.MatchString(format) {
+		return parseComponent(args)
+	}
+	args = args[1:]
+	defer func() {
+		if recover() != nil {
+			atomic.AddUint64(&rulePanics, 1)
+			fields, message, level = defaultParsef(format, args...)
+		}
+	}()
+	rule, ok := rules.Load().parseln[format]
+	if !ok {
+		return unknownFormat(format, args, normalizeTemplate(format))
+	}
+	if len(args) < rule.argc {
+		return rule.degraded(format, args)
+	}
+	fields, message = rule.structure(args)
+	return rule.matched(fields, args), message, rule.levelOf()
+}
+
+var rulePanics uint64
+
+// RulePanics is the number of times a rule panicked on the args of a
+// message since the process started, the message falling back to its
+// format, or to another rule for lines. A rising count means grpc logs
+// something else than the rules expect.
+func RulePanics() uint64 {
+	return atomic.LoadUint64(&rulePanics)
+}
+
+func defaultParsef(format string, args ...interface{}) (Fields, string, Level) {
+	var fields Fields
+	if len(args) > 0 {
+		fields = argFields(args)
+	} else {
+		fields = newFields()
+	}
+	fields[MsgTemplateKey] = format
+	return fields, format, InfoLevel
+}
+
+// argFields holds args as arg0..argN, with the status and network
+// details of their error.
+func argFields(args []interface{}) Fields {
+	fields := newFields()
+	for i, arg := range args {
+		fields[argKey(i)] = sprint(arg)
+	}
+	if s := errorStatus(args); s != nil {
+		addStatus(fields, s)
+	}
+	if err := netError(args); err != nil {
+		addNetError(fields, err)
+	}
+	return fields
+}
+
+var argKeys = [...]string{"arg0", "arg1", "arg2", "arg3", "arg4", "arg5", "arg6", "arg7"}
+
+func argKey(i int) string {
+	if i < len(argKeys) {
+		return argKeys[i]
+	}
+	return "arg" + strconv.Itoa(i)
+}
+
+// sprint formats v as fmt's %v does, without going through fmt for the
+// types messages are mostly made of.
+func sprint(v interface{}) (s string) {
+	switch v := v.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case error, fmt.Stringer:
+		// fmt recovers the panics of Error and String, nil receivers
+		// included: so does falling back to it.
+		defer func() {
+			if recover() != nil {
+				s = fmt.Sprint(v)
+			}
+		}()
+		if err, ok := v.(error); ok {
+			return err.Error()
+		}
+		return v.(fmt.Stringer).String()
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,49 @@
+package parser
+
+import "sync"
+
+// maxPooled is the size beyond which fields aren't pooled, so that a
+// burst of large entries doesn't keep large maps around.
+const maxPooled = 16
+
+// fieldsPool holds the fields given back by Release, which structure the
+// messages matching no rule, and those matching a rule with a filler:
+// every built-in rule, unlike those of packs.
+var fieldsPool = sync.Pool{
+	New: func() interface{} { return make(Fields, 4) },
+}
+
+func newFields() Fields {
+	return fieldsPool.Get().(Fields)
+}
+
+// noFields are the fields of messages without any, shared read-only.
+var noFields = Fields{}
+
+// Release gives fields back to the parser, to structure later messages
+// with, once the caller is done with them: they must not be used
+// afterwards. Releasing fields is optional, saving the allocation of
+// those of a later message.
+func Release(fields Fields) {
+	if len(fields) == 0 || len(fields) > maxPooled || Shared(fields) {
+		return
+	}
+	for k := range fields {
+		delete(fields, k)
+	}
+	fieldsPool.Put(fields)
+}
+
+// Shared reports whether fields may be the shared fields of a rule,
+// holding only its id, category and template, for callers to reuse what
+// they derive from them. Fields alike but not shared aren't pooled
+// either, which costs little.
+func Shared(fields Fields) bool {
+	if len(fields) != 3 {
+		return false
+	}
+	_, id := fields[EventIDKey]
+	_, category := fields[CategoryKey]
+	_, template := fields[MsgTemplateKey]
+	return id && category && template
+}
@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+)
+
+const (
+	poolMatched  = "transport: http2Server.HandleStreams failed to read frame: %v"
+	poolFallback = "transport: some message without rule: %v"
+)
+
+var errPool = errors.New("connection reset by peer")
+
+// BenchmarkParsef compares messages whose fields are released to those
+// whose fields aren't, and the filler of a rule to its closure.
+func BenchmarkParsef(b *testing.B) {
+	for _, bench := range []struct {
+		name    string
+		format  string
+		release bool
+	}{
+		{"Matched/Released", poolMatched, true},
+		{"Matched/Kept", poolMatched, false},
+		{"Fallback/Released", poolFallback, true},
+		{"Fallback/Kept", poolFallback, false},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				fields, _, _ := Parsef(bench.format, errPool)
+				if bench.release {
+					Release(fields)
+				}
+			}
+		})
+	}
+	rule := rules.Load().parsef[poolMatched]
+	args := []interface{}{errPool}
+	b.Run("Rule/Filler", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			fields, _ := rule.structure(args)
+			Release(fields)
+		}
+	})
+	b.Run("Rule/Closure", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			fields, _ := rule.parse(args...)
+			Release(fields)
+		}
+	})
+}
+
+func TestReleaseSavesAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations aren't counted with the race detector")
+	}
+	for _, format := range []string{poolMatched, poolFallback} {
+		released := testing.AllocsPerRun(100, func() {
+			fields, _, _ := Parsef(format, errPool)
+			Release(fields)
+		})
+		kept := testing.AllocsPerRun(100, func() {
+			Parsef(format, errPool)
+		})
+		if released >= kept {
+			t.Errorf("%q: want fewer allocations released than kept, got %v and %v", format, released, kept)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package parser
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+// SourceBuiltin is the source of the rules of this package, those of
+// packs being the pack's name.
+const SourceBuiltin = "builtin"
+
+// RuleInfo describes a rule, for introspection.
+type RuleInfo struct {
+	// ID is the event id of the entries the rule structures.
+	ID string `json:"id"`
+	// Format is the grpclog format the rule matches.
+	Format string `json:"format"`
+	// Level is the level of its entries, unless logged as fatal.
+	Level string `json:"level"`
+	// Category classifies the rule.
+	Category Category `json:"category"`
+	// Source is where the rule comes from.
+	Source string `json:"source"`
+	// Priority is the rank at which ParseLine tries the rule, from 0:
+	// the most specific formats first. Parsef and Parseln look formats
+	// up as they are.
+	Priority int `json:"priority"`
+	// Arity is the number of fields of its entries, event_id included.
+	Arity int `json:"arity"`
+	// Fields are the names of the fields of its entries, but event_id,
+	// sorted.
+	Fields []string `json:"fields,omitempty"`
+	// Matches is the number of messages the rule structured since the
+	// process started.
+	Matches uint64 `json:"matches"`
+}
+
+// probe names the fields a rule returns for args, sorted, and counts
+// them, event_id included.
+func probe(r rule, args []interface{}) (names []string, arity int) {
+	defer func() {
+		if recover() != nil {
+			names, arity = nil, 0
+		}
+	}()
+	for i := range args {
+		args[i] = ""
+	}
+	fields, _ := r.parse(args...)
+	for k := range fields {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names, len(fields) + 1
+}
+
+// reads returns the number of args a rule reads: the fewest, up to max,
+// it takes without panicking, which is one past the last index it reads.
+// Rules panicking on any count are taken to read max.
+func reads(r rule, max int) int {
+	for n := 0; n < max; n++ {
+		if takes(r, n) {
+			return n
+		}
+	}
+	return max
+}
+
+// takes reports whether a rule returns when given n args.
+func takes(r rule, n int) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	args := make([]interface{}, n)
+	for i := range args {
+		args[i] = ""
+	}
+	r.parse(args...)
+	return true
+}
+
+// verbs counts the verbs of a format.
+func verbs(format string) int {
+	var n int
+	for i := 0; i+1 < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		if format[i] != '%' {
+			n++
+		}
+	}
+	return n
+}
+
+// RuleSource is the source of the rule that structured an entry, told by
+// its event id and template: SourceBuiltin or the name of a pack. It
+// reports false when no rule has them, e.g. since its pack was unloaded.
+func RuleSource(id, template string) (string, bool) {
+	set := rules.Load()
+	for _, compiled := range []map[string]*compiledRule{set.parsef, set.parseln} {
+		if r, ok := compiled[template]; ok && r.id == id {
+			return r.source, true
+		}
+	}
+	return "", false
+}
+
+// Rules describes the rules, in their order of priority.
+func Rules() []RuleInfo {
+	set := rules.Load()
+	priority := make(map[string]int, len(set.lines))
+	for i, p := range set.lines {
+		priority[p.format] = i
+	}
+	infos := make([]RuleInfo, 0, len(set.parsef)+len(set.parseln))
+	for _, compiled := range []map[string]*compiledRule{set.parsef, set.parseln} {
+		for format, r := range compiled {
+			infos = append(infos, RuleInfo{
+				ID:       r.id,
+				Format:   format,
+				Level:    r.level.String(),
+				Category: r.category,
+				Source:   r.source,
+				Priority: priority[format],
+				Arity:    r.arity,
+				Fields:   r.fields,
+				Matches:  atomic.LoadUint64(r.matches),
+			})
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Priority < infos[j].Priority })
+	return infos
+}
@@ -0,0 +1,318 @@
+package parser
+
+//go:generate go run ./internal/genrules
+
+// rule structures the messages of a known format. Its id is a stable
+// identifier of the event, and its level the severity of the message
+// when it isn't logged with a Fatal call. Rules extracting no fields
+// return nil fields, for the message to share the fields of its id.
+type rule struct {
+	id    string
+	level Level
+	parse func(args ...interface{}) (Fields, string)
+}
+
+var parsefRules = map[string]rule{
+
+	"grpc: ClientConn.resetTransport failed to create client transport: %v; Reconnecting to %q": {"grpc.clientconn_resettransport_failed", WarnLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "grpc", "err": args[0], "addr": args[1]}, "ClientConn.resetTransport failed to create client transport, reconnecting"
+	}},
+
+	"%v compleled with error code %d, want %d": {"completed_with_wrong_error_code", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"stream": args[0], "got.code": args[1], "want.code": args[2]}, "completed with wrong error code"
+	}},
+	"%v.CloseAndRecv() got error code %d, want %d": {"stream_closeandrecv_got_wrong_error_code", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"stream": args[0], "got.code": args[1], "want.code": args[2]}, "stream CloseAndRecv() got wrong error code"
+	}},
+	"Getting feature for point (%d, %d)": {"getting_feature_for_point", InfoLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"point.latitude": args[0], "point.longitude": args[1]}, "Getting feature for point"
+	}},
+	"Got %d reply, want %d": {"got_wrong_count_of_replies", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"got.count": args[0], "want.count": args[1]}, "got wrong count of replies"
+	}},
+	"Got message %s at point(%d, %d)": {"got_message_at_point", InfoLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"message": args[0], "point.latitude": args[1], "point.longitude": args[2]}, "got message at point"
+	}},
+	"Got reply body of length %d, want %d": {"got_reply_body_of_wrong_length", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"got.length": args[0], "want.length": args[1]}, "Got reply body of wrong length"
+	}},
+	"Got the reply of type %d, want %d": {"got_the_reply_of_wrong_type", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"got.type": args[0], "want.type": args[1]}, "Got the reply of wrong type"
+	}},
+	"Got the reply with type %d len %d; want %d, %d": {"got_the_reply_with_wrong_type_and_length", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"got.type": args[0], "got.len": args[1], "want.type": args[2], "want.len": args[3]}, "Got the reply with wrong type and length"
+	}},
+	"Requested a response with invalid length %d": {"requested_a_response_with_invalid_length", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"length": args[0]}, "Requested a response with invalid length"
+	}},
+	"Sent a request of size %d, aggregated size %d": {"sent_a_request_of_wrong_size", InfoLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"request.size": args[0], "aggregated.size": args[1]}, "Sent a request of wrong size"
+	}},
+	"Traversing %d points.": {"traversing_points", InfoLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"count": args[0]}, "traversing points"
+	}},
+	"Unsupported payload type: %d": {"unsupported_payload_type", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"type": args[0]}, "unsupported payload type"
+	}},
+	"%v failed to complele the ping pong test: %v": {"failed_to_complete_the_ping_pong_test", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"stream": args[0], "err": args[1]}, "failed to complele the ping pong test"
+	}},
+	"%v.CloseAndRecv() got error %v, want %v": {"stream_closeandrecv_got_error_expected_none", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"stream": args[0], "err": args[1], "want.err": args[2]}, "stream CloseAndRecv() got error, expected none"
+	}},
+	"%v.CloseSend() got %v, want %v": {"stream_closesend_got_error_expected_none", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"stream": args[0], "err": args[1], "want.err": args[2]}, "stream CloseSend() got error, expected none"
+	}},
+	"%v.CloseAndRecv().GetAggregatePayloadSize() = %v; want %v": {"stream_closeandrecv_got_wrong_aggregate_payload_size", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"stream": args[0], "got.size": args[1], "want.size": args[2]}, "stream CloseAndRecv().GetAggregatePayloadSize() got wrong size"
+	}},
+	"%v.GetFeatures(_) = _, %v: ": {"getfeatures", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"client": args[0], "err": args[1]}, "GetFeatures"
+	}},
+	"%v.ListFeatures(_) = _, %v": {"listfeatures", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"client": args[0], "err": args[1]}, "ListFeatures"
+	}},
+	"%v.RecordRoute(_) = _, %v": {"recordroute", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"client": args[0], "err": args[1]}, "RecordRoute"
+	}},
+	"%v.RouteChat(_) = _, %v": {"routechat", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"client": args[0], "err": args[1]}, "RouteChat"
+	}},
+	"%v.FullDuplexCall(_) = _, %v": {"fullduplexcall", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"tc": args[0], "err": args[1]}, "FullDuplexCall"
+	}},
+	"%v.Recv() = %v": {"stream_recv_got_error", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"stream": args[0], "err": args[1]}, "stream .Recv() got error"
+	}},
+	"%v.Send(%v) = %v": {"stream_send_got_error", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"stream": args[0], "point": args[1], "err": args[2]}, "stream .Send() got error"
+	}},
+	"Got OAuth scope %q which is NOT a substring of %q.": {"got_unexpected_oauth_scope", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"got.scope": args[0], "want.scope": args[1]}, "Got OAuth scope which is NOT a substring of expected scope"
+	}},
+	"Got user name %q which is NOT a substring of %q.": {"got_user_name_not_in_json_key", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"user": args[0], "json.key": args[1]}, "Got user name which is NOT a substring json key"
+	}},
+	"Got user name %q, want %q.": {"wrong_user_name", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"got.user": args[0], "want.user": args[1]}, "wrong user name"
+	}},
+	"grpc: Server.RegisterService found duplicate service registration for %q": {"grpc.server_registerservice_found_duplicate_service_registration", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "grpc", "service.name": args[0]}, "Server.RegisterService found duplicate service registration"
+	}},
+	"NewClientConn(%q) failed to create a ClientConn %v": {"newclientconn_failed_to_create_a_clientconn", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"addr": args[0], "err": args[1]}, "NewClientConn(_) failed to create a ClientConn"
+	}},
+	"transport: http2Server.HandleStreams received bogus greeting from client: %q": {"transport.http2server_handlestreams_received_bogus_greeting_from_client", WarnLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "transport", "preface": args[0]}, "http2Server.HandleStreams received bogus greeting from client"
+	}},
+	"%v.SendHeader(%v) = %v, want %v": {"sendheader", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"stream": args[0], "md": args[1], "err": args[2], "want.err": args[3]}, "SendHeader"
+	}},
+	"%v.StreamingCall(_) = _, %v": {"streamingcall", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"tc": args[0], "err": args[1]}, "StreamingCall"
+	}},
+	"%v.StreamingInputCall(_) = _, %v": {"streaminginputcall", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"tc": args[0], "err": args[1]}, "StreamingInputCall"
+	}},
+	"%v.StreamingOutputCall(_) = _, %v": {"streamingoutputcall", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"tc": args[0], "err": args[1]}, "StreamingOutputCall"
+	}},
+	"/TestService/EmptyCall receives %v, want %v": {"testservice_emptycall_receives", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"got.reply": args[0], "want.reply": args[1]}, "/TestService/EmptyCall receives"
+	}},
+	"Dial(%q) = %v": {"dial", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"addr": args[0], "err": args[1]}, "Dial"
+	}},
+	"Fail to dial: %v": {"fail_to_dial", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"err": args[0]}, "fail to dial"
+	}},
+	"fail to dial: %v": {"fail_to_dial", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"err": args[0]}, "fail to dial"
+	}},
+	"Failed to convert %v to *http2Server": {"failed_to_convert_to_http2server", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"s.ServerTransport()": args[0]}, "Failed to convert to *http2Server"
+	}},
+	"Failed to create credentials %v": {"failed_to_create_credentials", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"err": args[0]}, "Failed to create credentials"
+	}},
+	"Failed to create JWT credentials: %v": {"failed_to_create_jwt_credentials", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"err": args[0]}, "Failed to create JWT credentials"
+	}},
+	"Failed to create TLS credentials %v": {"failed_to_create_tls_credentials", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"err": args[0]}, "Failed to create TLS credentials"
+	}},
+	"Failed to decode (%q, %q): %v": {"failed_to_decode", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"f.Name": args[0], "f.Value": args[1], "err": args[2]}, "Failed to decode"
+	}},
+	"Failed to dial %s: %v; please retry.": {"failed_to_dial_please_retry", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"target": args[0], "err": args[1]}, "Failed to dial, please retry"
+	}},
+	"Failed to finish the server streaming rpc: %v": {"failed_to_finish_the_server_streaming_rpc", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"err": args[0]}, "Failed to finish the server streaming rpc"
+	}},
+	"Failed to generate credentials %v": {"failed_to_generate_credentials", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"err": args[0]}, "Failed to generate credentials"
+	}},
+	"Failed to listen: %v": {"failed_to_listen", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"err": args[0]}, "failed to listen"
+	}},
+	"failed to listen: %v": {"failed_to_listen", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"err": args[0]}, "failed to listen"
+	}},
+	"Failed to load default features: %v": {"failed_to_load_default_features", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"err": args[0]}, "Failed to load default features"
+	}},
+	"Failed to parse listener address: %v": {"failed_to_parse_listener_address", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"err": args[0]}, "Failed to parse listener address"
+	}},
+	"failed to parse listener address: %v": {"failed_to_parse_listener_address", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"err": args[0]}, "Failed to parse listener address"
+	}},
+	"Failed to read the service account key file: %v": {"failed_to_read_the_service_account_key_file", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"err": args[0]}, "Failed to read the service account key file"
+	}},
+	"Failed to receive a note : %v": {"failed_to_receive_a_note", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"err": args[0]}, "Failed to receive a note"
+	}},
+	"Failed to send a note: %v": {"failed_to_send_a_note", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"err": args[0]}, "Failed to send a not"
+	}},
+	"Failed to serve: %v": {"failed_to_serve", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"err": args[0]}, "Failed to serve"
+	}},
+	"grpc.SendHeader(%v, %v) = %v, want %v": {"grpc_sendheader", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"ctx": args[0], "md": args[1], "err": args[2], "want.err": args[3]}, "grpc.SendHeader"
+	}},
+	"transport: http2Server.HandleStreams saw invalid preface type %T from client": {"transport.http2server_handlestreams_saw_invalid_preface_type_from_client", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "transport", "frame": typeOf(args[0])}, "http2Server.HandleStreams saw invalid preface type from client"
+	}},
+	"grpc: ClientConn.transportMonitor exits due to: %v": {"grpc.clientconn_transportmonitor_exits", WarnLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "grpc", "err": args[0]}, "ClientConn.transportMonitor exits"
+	}},
+	"grpc: SendHeader: %v has no ServerTransport to send header metadata.": {"grpc.sendheader_stream_has_no_servertransport", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "grpc", "stream": args[0]}, "SendHeader: stream has no ServerTransport to send header metadata"
+	}},
+	"grpc: Server failed to encode response %v": {"grpc.server_failed_to_encode_response", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "grpc", "err": args[0]}, "Server failed to encode response"
+	}},
+	"grpc: Server.handleStream failed to write status: %v": {"grpc.server_handlestream_failed_to_write_status", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "grpc", "err": args[0]}, "Server.handleStream failed to write status"
+	}},
+	"grpc: Server.processUnaryRPC failed to write status: %v": {"grpc.server_processunaryrpc_failed_to_write_status", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "grpc", "err": args[0]}, "Server.processUnaryRPC failed to write status"
+	}},
+	"grpc: Server.RegisterService found the handler of type %v that does not satisfy %v": {"grpc.server_registerservice_found_unsatisfying_handler", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "grpc", "found.type": args[0], "expected.type": args[1]}, "Server.RegisterService found handler of type that does not satisfy expectations"
+	}},
+	"handleStream got error: %v, want <nil>; result: %v, want %v": {"handlestream_got_error", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"err": args[0], "got.result": args[1], "want.result": args[2]}, "handleStream got error"
+	}},
+	"Looking for features within %v": {"looking_for_features_within_rectangle", InfoLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"rect": args[0]}, "Looking for features withi rectangle"
+	}},
+	"PayloadType UNCOMPRESSABLE is not supported": {"payloadtype_uncompressable_is_not_supported", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return nil, "PayloadType UNCOMPRESSABLE is not supported"
+	}},
+	"Route summary: %v": {"route_summary", InfoLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"reply": args[0]}, "Route summary"
+	}},
+	"StreamingCall(_).Recv: %v": {"streamingcall_recv", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"err": args[0]}, "StreamingCall(_).Recv"
+	}},
+	"StreamingCall(_).Send: %v": {"streamingcall_send", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"err": args[0]}, "StreamingCall(_).Send"
+	}},
+	"TLS is not enabled. TLS is required to execute compute_engine_creds test case.": {"tls_required_for_compute_engine_creds", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return nil, "TLS is not enabled. TLS is required to execute compute_engine_creds test case"
+	}},
+	"TLS is not enabled. TLS is required to execute service_account_creds test case.": {"tls_required_for_service_account_creds", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return nil, "TLS is not enabled. TLS is required to execute service_account_creds test case"
+	}},
+	"transport: http2Client.controller got unexpected item type %v": {"transport.http2client_controller_got_unexpected_item_type", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "transport", "item.type": args[0]}, "http2Client.controller got unexpected item type"
+	}},
+	"transport: http2Client.notifyError got notified that the client transport was broken %v.": {"transport.http2client_notifyerror_client_transport_broken", WarnLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "transport", "err": args[0]}, "http2Client.notifyError got notified that the client transport was broken"
+	}},
+	"transport: http2Client.reader got unhandled frame type %v.": {"transport.http2client_reader_got_unhandled_frame_type", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "transport", "frame": args[0]}, "http2Client.reader got unhandled frame type"
+	}},
+	"transport: http2Server %v": {"transport.http2server_error", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "transport", "err": args[0]}, "http2Server error"
+	}},
+	"transport: http2Server.controller got unexpected item type %v": {"transport.http2server_controller_got_unexpected_item_type", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "transport", "item.type": args[0]}, "http2Server.controller got unexpected item type"
+	}},
+	"transport: http2Server.HandleStreams failed to read frame: %v": {"transport.http2server_handlestreams_failed_to_read_frame", WarnLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "transport", "err": args[0]}, "http2Server.HandleStreams failed to read frame"
+	}},
+	"transport: http2Server.HandleStreams failed to receive the preface from client: %v": {"transport.http2server_handlestreams_failed_to_receive_the_preface_from_client", WarnLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "transport", "err": args[0]}, "http2Server.HandleStreams failed to receive the preface from client"
+	}},
+	"transport: http2Server.HandleStreams found unhandled frame type %v.": {"transport.http2server_handlestreams_found_unhandled_frame_type", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "transport", "frame": args[0]}, "http2Server.HandleStreams found unhandled frame type"
+	}},
+	"transport: http2Server.operateHeader found %v": {"transport.http2server_operateheader_found", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "transport", "err": args[0]}, "http2Server.operateHeader found"
+	}},
+}
+
+var parselnRules = map[string]rule{
+	"/TestService/EmptyCall RPC failed: ": {"testservice_emptycall_rpc_failed", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"err": args[0]}, "/TestService/EmptyCall RPC failed"
+	}},
+	"/TestService/UnaryCall RPC failed: ": {"testservice_unarycall_rpc_failed", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"err": args[0]}, "/TestService/UnaryCall RPC failed"
+	}},
+	"CancelAfterBegin done": {"cancelafterbegin_done", InfoLevel, func(args ...interface{}) (Fields, string) {
+		return nil, "CancelAfterBegin done"
+	}},
+	"CancelAfterFirstResponse done": {"cancelafterfirstresponse_done", InfoLevel, func(args ...interface{}) (Fields, string) {
+		return nil, "CancelAfterFirstResponse done"
+	}},
+	"Client profiling address: ": {"client_profiling_address", InfoLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"addr": args[0]}, "Client profiling address"
+	}},
+	"ClientStreaming done": {"clientstreaming_done", InfoLevel, func(args ...interface{}) (Fields, string) {
+		return nil, "ClientStreaming done"
+	}},
+	"ComputeEngineCreds done": {"computeenginecreds_done", InfoLevel, func(args ...interface{}) (Fields, string) {
+		return nil, "ComputeEngineCreds done"
+	}},
+	"EmptyUnaryCall done": {"emptyunarycall_done", InfoLevel, func(args ...interface{}) (Fields, string) {
+		return nil, "EmptyUnaryCall done"
+	}},
+	"grpc: Server.Serve failed to complete security handshake.": {"grpc.server_serve_failed_to_complete_security_handshake", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "grpc"}, "Server.Serve failed to complete security handshake"
+	}},
+	"grpc: Server.Serve failed to create ServerTransport: ": {"grpc.server_serve_failed_to_create_servertransport", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "grpc", "err": args[0]}, "Server.Serve failed to create ServerTransport"
+	}},
+	"LargeUnaryCall done": {"largeunarycall_done", InfoLevel, func(args ...interface{}) (Fields, string) {
+		return nil, "LargeUnaryCall done"
+	}},
+	"Pingpong done": {"pingpong_done", InfoLevel, func(args ...interface{}) (Fields, string) {
+		return nil, "Pingpong done"
+	}},
+	"Server Address: ": {"server_address", InfoLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"addr": args[0]}, "Server Address"
+	}},
+	"Server profiling address: ": {"server_profiling_address", InfoLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"addr": args[0]}, "Server profiling address"
+	}},
+	"ServerStreaming done": {"serverstreaming_done", InfoLevel, func(args ...interface{}) (Fields, string) {
+		return nil, "ServerStreaming done"
+	}},
+	"ServiceAccountCreds done": {"serviceaccountcreds_done", InfoLevel, func(args ...interface{}) (Fields, string) {
+		return nil, "ServiceAccountCreds done"
+	}},
+	"transport: http2Client.handleRSTStream found no mapped gRPC status for the received http2 error ": {"transport.http2client_handlerststream_no_mapped_grpc_status", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "transport", "err": args[0]}, "http2Client.handleRSTStream found no mapped gRPC status for the received http2 error"
+	}},
+	"transport: http2Server.HandleStreams received an illegal stream id: ": {"transport.http2server_handlestreams_received_an_illegal_stream_id", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"package": "transport", "id": args[0], StreamIDKey: sprint(args[0])}, "http2Server.HandleStreams received an illegal stream id"
+	}},
+	"Unsupported test case: ": {"unsupported_test_case", ErrorLevel, func(args ...interface{}) (Fields, string) {
+		return Fields{"test.case": args[0]}, "Unsupported test case"
+	}},
+}
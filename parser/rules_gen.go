@@ -0,0 +1,764 @@
+// Code generated by genrules from rules.go. DO NOT EDIT.
+
+package parser
+
+var parsefFillers = map[string]filler{
+	"%v compleled with error code %d, want %d":                  fillCompletedWithWrongErrorCode,
+	"%v failed to complele the ping pong test: %v":              fillFailedToCompleteThePingPongTest,
+	"%v.CloseAndRecv() got error %v, want %v":                   fillStreamCloseandrecvGotErrorExpectedNone,
+	"%v.CloseAndRecv() got error code %d, want %d":              fillStreamCloseandrecvGotWrongErrorCode,
+	"%v.CloseAndRecv().GetAggregatePayloadSize() = %v; want %v": fillStreamCloseandrecvGotWrongAggregatePayloadSize,
+	"%v.CloseSend() got %v, want %v":                            fillStreamClosesendGotErrorExpectedNone,
+	"%v.FullDuplexCall(_) = _, %v":                              fillFullduplexcall,
+	"%v.GetFeatures(_) = _, %v: ":                               fillGetfeatures,
+	"%v.ListFeatures(_) = _, %v":                                fillListfeatures,
+	"%v.RecordRoute(_) = _, %v":                                 fillRecordroute,
+	"%v.Recv() = %v":                                            fillStreamRecvGotError,
+	"%v.RouteChat(_) = _, %v":                                   fillRoutechat,
+	"%v.Send(%v) = %v":                                          fillStreamSendGotError,
+	"%v.SendHeader(%v) = %v, want %v":                           fillSendheader,
+	"%v.StreamingCall(_) = _, %v":                               fillStreamingcall,
+	"%v.StreamingInputCall(_) = _, %v":                          fillStreaminginputcall,
+	"%v.StreamingOutputCall(_) = _, %v":                         fillStreamingoutputcall,
+	"/TestService/EmptyCall receives %v, want %v":               fillTestserviceEmptycallReceives,
+	"Dial(%q) = %v":                                             fillDial,
+	"Fail to dial: %v":                                          fillFailToDial,
+	"Failed to convert %v to *http2Server":                      fillFailedToConvertToHttp2server,
+	"Failed to create JWT credentials: %v":                      fillFailedToCreateJwtCredentials,
+	"Failed to create TLS credentials %v":                       fillFailedToCreateTlsCredentials,
+	"Failed to create credentials %v":                           fillFailedToCreateCredentials,
+	"Failed to decode (%q, %q): %v":                             fillFailedToDecode,
+	"Failed to dial %s: %v; please retry.":                      fillFailedToDialPleaseRetry,
+	"Failed to finish the server streaming rpc: %v":             fillFailedToFinishTheServerStreamingRpc,
+	"Failed to generate credentials %v":                         fillFailedToGenerateCredentials,
+	"Failed to listen: %v":                                      fillFailedToListen,
+	"Failed to load default features: %v":                       fillFailedToLoadDefaultFeatures,
+	"Failed to parse listener address: %v":                      fillFailedToParseListenerAddress,
+	"Failed to read the service account key file: %v":           fillFailedToReadTheServiceAccountKeyFile,
+	"Failed to receive a note : %v":                             fillFailedToReceiveANote,
+	"Failed to send a note: %v":                                 fillFailedToSendANote,
+	"Failed to serve: %v":                                       fillFailedToServe,
+	"Getting feature for point (%d, %d)":                        fillGettingFeatureForPoint,
+	"Got %d reply, want %d":                                     fillGotWrongCountOfReplies,
+	"Got OAuth scope %q which is NOT a substring of %q.":        fillGotUnexpectedOauthScope,
+	"Got message %s at point(%d, %d)":                           fillGotMessageAtPoint,
+	"Got reply body of length %d, want %d":                      fillGotReplyBodyOfWrongLength,
+	"Got the reply of type %d, want %d":                         fillGotTheReplyOfWrongType,
+	"Got the reply with type %d len %d; want %d, %d":            fillGotTheReplyWithWrongTypeAndLength,
+	"Got user name %q which is NOT a substring of %q.":          fillGotUserNameNotInJsonKey,
+	"Got user name %q, want %q.":                                fillWrongUserName,
+	"Looking for features within %v":                            fillLookingForFeaturesWithinRectangle,
+	"NewClientConn(%q) failed to create a ClientConn %v":        fillNewclientconnFailedToCreateAClientconn,
+	"PayloadType UNCOMPRESSABLE is not supported":               fillPayloadtypeUncompressableIsNotSupported,
+	"Requested a response with invalid length %d":               fillRequestedAResponseWithInvalidLength,
+	"Route summary: %v":                                         fillRouteSummary,
+	"Sent a request of size %d, aggregated size %d":             fillSentARequestOfWrongSize,
+	"StreamingCall(_).Recv: %v":                                 fillStreamingcallRecv,
+	"StreamingCall(_).Send: %v":                                 fillStreamingcallSend,
+	"TLS is not enabled. TLS is required to execute compute_engine_creds test case.":  fillTlsRequiredForComputeEngineCreds,
+	"TLS is not enabled. TLS is required to execute service_account_creds test case.": fillTlsRequiredForServiceAccountCreds,
+	"Traversing %d points.":                 fillTraversingPoints,
+	"Unsupported payload type: %d":          fillUnsupportedPayloadType,
+	"fail to dial: %v":                      fillFailToDial2,
+	"failed to listen: %v":                  fillFailedToListen2,
+	"failed to parse listener address: %v":  fillFailedToParseListenerAddress2,
+	"grpc.SendHeader(%v, %v) = %v, want %v": fillGrpcSendheader,
+	"grpc: ClientConn.resetTransport failed to create client transport: %v; Reconnecting to %q": fillGrpcClientconnResettransportFailed,
+	"grpc: ClientConn.transportMonitor exits due to: %v":                                        fillGrpcClientconnTransportmonitorExits,
+	"grpc: SendHeader: %v has no ServerTransport to send header metadata.":                      fillGrpcSendheaderStreamHasNoServertransport,
+	"grpc: Server failed to encode response %v":                                                 fillGrpcServerFailedToEncodeResponse,
+	"grpc: Server.RegisterService found duplicate service registration for %q":                  fillGrpcServerRegisterserviceFoundDuplicateServiceRegistration,
+	"grpc: Server.RegisterService found the handler of type %v that does not satisfy %v":        fillGrpcServerRegisterserviceFoundUnsatisfyingHandler,
+	"grpc: Server.handleStream failed to write status: %v":                                      fillGrpcServerHandlestreamFailedToWriteStatus,
+	"grpc: Server.processUnaryRPC failed to write status: %v":                                   fillGrpcServerProcessunaryrpcFailedToWriteStatus,
+	"handleStream got error: %v, want <nil>; result: %v, want %v":                               fillHandlestreamGotError,
+	"transport: http2Client.controller got unexpected item type %v":                             fillTransportHttp2clientControllerGotUnexpectedItemType,
+	"transport: http2Client.notifyError got notified that the client transport was broken %v.":  fillTransportHttp2clientNotifyerrorClientTransportBroken,
+	"transport: http2Client.reader got unhandled frame type %v.":                                fillTransportHttp2clientReaderGotUnhandledFrameType,
+	"transport: http2Server %v":                                                                 fillTransportHttp2serverError,
+	"transport: http2Server.HandleStreams failed to read frame: %v":                             fillTransportHttp2serverHandlestreamsFailedToReadFrame,
+	"transport: http2Server.HandleStreams failed to receive the preface from client: %v":        fillTransportHttp2serverHandlestreamsFailedToReceiveThePrefaceFromClient,
+	"transport: http2Server.HandleStreams found unhandled frame type %v.":                       fillTransportHttp2serverHandlestreamsFoundUnhandledFrameType,
+	"transport: http2Server.HandleStreams received bogus greeting from client: %q":              fillTransportHttp2serverHandlestreamsReceivedBogusGreetingFromClient,
+	"transport: http2Server.HandleStreams saw invalid preface type %T from client":              fillTransportHttp2serverHandlestreamsSawInvalidPrefaceTypeFromClient,
+	"transport: http2Server.controller got unexpected item type %v":                             fillTransportHttp2serverControllerGotUnexpectedItemType,
+	"transport: http2Server.operateHeader found %v":                                             fillTransportHttp2serverOperateheaderFound,
+}
+
+// fillCompletedWithWrongErrorCode fills the fields of "%v compleled with error code %d, want %d".
+func fillCompletedWithWrongErrorCode(fields Fields, args []interface{}) string {
+	fields["stream"] = args[0]
+	fields["got.code"] = args[1]
+	fields["want.code"] = args[2]
+	return "completed with wrong error code"
+}
+
+// fillFailedToCompleteThePingPongTest fills the fields of "%v failed to complele the ping pong test: %v".
+func fillFailedToCompleteThePingPongTest(fields Fields, args []interface{}) string {
+	fields["stream"] = args[0]
+	fields["err"] = args[1]
+	return "failed to complele the ping pong test"
+}
+
+// fillStreamCloseandrecvGotErrorExpectedNone fills the fields of "%v.CloseAndRecv() got error %v, want %v".
+func fillStreamCloseandrecvGotErrorExpectedNone(fields Fields, args []interface{}) string {
+	fields["stream"] = args[0]
+	fields["err"] = args[1]
+	fields["want.err"] = args[2]
+	return "stream CloseAndRecv() got error, expected none"
+}
+
+// fillStreamCloseandrecvGotWrongErrorCode fills the fields of "%v.CloseAndRecv() got error code %d, want %d".
+func fillStreamCloseandrecvGotWrongErrorCode(fields Fields, args []interface{}) string {
+	fields["stream"] = args[0]
+	fields["got.code"] = args[1]
+	fields["want.code"] = args[2]
+	return "stream CloseAndRecv() got wrong error code"
+}
+
+// fillStreamCloseandrecvGotWrongAggregatePayloadSize fills the fields of "%v.CloseAndRecv().GetAggregatePayloadSize() = %v; want %v".
+func fillStreamCloseandrecvGotWrongAggregatePayloadSize(fields Fields, args []interface{}) string {
+	fields["stream"] = args[0]
+	fields["got.size"] = args[1]
+	fields["want.size"] = args[2]
+	return "stream CloseAndRecv().GetAggregatePayloadSize() got wrong size"
+}
+
+// fillStreamClosesendGotErrorExpectedNone fills the fields of "%v.CloseSend() got %v, want %v".
+func fillStreamClosesendGotErrorExpectedNone(fields Fields, args []interface{}) string {
+	fields["stream"] = args[0]
+	fields["err"] = args[1]
+	fields["want.err"] = args[2]
+	return "stream CloseSend() got error, expected none"
+}
+
+// fillFullduplexcall fills the fields of "%v.FullDuplexCall(_) = _, %v".
+func fillFullduplexcall(fields Fields, args []interface{}) string {
+	fields["tc"] = args[0]
+	fields["err"] = args[1]
+	return "FullDuplexCall"
+}
+
+// fillGetfeatures fills the fields of "%v.GetFeatures(_) = _, %v: ".
+func fillGetfeatures(fields Fields, args []interface{}) string {
+	fields["client"] = args[0]
+	fields["err"] = args[1]
+	return "GetFeatures"
+}
+
+// fillListfeatures fills the fields of "%v.ListFeatures(_) = _, %v".
+func fillListfeatures(fields Fields, args []interface{}) string {
+	fields["client"] = args[0]
+	fields["err"] = args[1]
+	return "ListFeatures"
+}
+
+// fillRecordroute fills the fields of "%v.RecordRoute(_) = _, %v".
+func fillRecordroute(fields Fields, args []interface{}) string {
+	fields["client"] = args[0]
+	fields["err"] = args[1]
+	return "RecordRoute"
+}
+
+// fillStreamRecvGotError fills the fields of "%v.Recv() = %v".
+func fillStreamRecvGotError(fields Fields, args []interface{}) string {
+	fields["stream"] = args[0]
+	fields["err"] = args[1]
+	return "stream .Recv() got error"
+}
+
+// fillRoutechat fills the fields of "%v.RouteChat(_) = _, %v".
+func fillRoutechat(fields Fields, args []interface{}) string {
+	fields["client"] = args[0]
+	fields["err"] = args[1]
+	return "RouteChat"
+}
+
+// fillStreamSendGotError fills the fields of "%v.Send(%v) = %v".
+func fillStreamSendGotError(fields Fields, args []interface{}) string {
+	fields["stream"] = args[0]
+	fields["point"] = args[1]
+	fields["err"] = args[2]
+	return "stream .Send() got error"
+}
+
+// fillSendheader fills the fields of "%v.SendHeader(%v) = %v, want %v".
+func fillSendheader(fields Fields, args []interface{}) string {
+	fields["stream"] = args[0]
+	fields["md"] = args[1]
+	fields["err"] = args[2]
+	fields["want.err"] = args[3]
+	return "SendHeader"
+}
+
+// fillStreamingcall fills the fields of "%v.StreamingCall(_) = _, %v".
+func fillStreamingcall(fields Fields, args []interface{}) string {
+	fields["tc"] = args[0]
+	fields["err"] = args[1]
+	return "StreamingCall"
+}
+
+// fillStreaminginputcall fills the fields of "%v.StreamingInputCall(_) = _, %v".
+func fillStreaminginputcall(fields Fields, args []interface{}) string {
+	fields["tc"] = args[0]
+	fields["err"] = args[1]
+	return "StreamingInputCall"
+}
+
+// fillStreamingoutputcall fills the fields of "%v.StreamingOutputCall(_) = _, %v".
+func fillStreamingoutputcall(fields Fields, args []interface{}) string {
+	fields["tc"] = args[0]
+	fields["err"] = args[1]
+	return "StreamingOutputCall"
+}
+
+// fillTestserviceEmptycallReceives fills the fields of "/TestService/EmptyCall receives %v, want %v".
+func fillTestserviceEmptycallReceives(fields Fields, args []interface{}) string {
+	fields["got.reply"] = args[0]
+	fields["want.reply"] = args[1]
+	return "/TestService/EmptyCall receives"
+}
+
+// fillDial fills the fields of "Dial(%q) = %v".
+func fillDial(fields Fields, args []interface{}) string {
+	fields["addr"] = args[0]
+	fields["err"] = args[1]
+	return "Dial"
+}
+
+// fillFailToDial fills the fields of "Fail to dial: %v".
+func fillFailToDial(fields Fields, args []interface{}) string {
+	fields["err"] = args[0]
+	return "fail to dial"
+}
+
+// fillFailedToConvertToHttp2server fills the fields of "Failed to convert %v to *http2Server".
+func fillFailedToConvertToHttp2server(fields Fields, args []interface{}) string {
+	fields["s.ServerTransport()"] = args[0]
+	return "Failed to convert to *http2Server"
+}
+
+// fillFailedToCreateJwtCredentials fills the fields of "Failed to create JWT credentials: %v".
+func fillFailedToCreateJwtCredentials(fields Fields, args []interface{}) string {
+	fields["err"] = args[0]
+	return "Failed to create JWT credentials"
+}
+
+// fillFailedToCreateTlsCredentials fills the fields of "Failed to create TLS credentials %v".
+func fillFailedToCreateTlsCredentials(fields Fields, args []interface{}) string {
+	fields["err"] = args[0]
+	return "Failed to create TLS credentials"
+}
+
+// fillFailedToCreateCredentials fills the fields of "Failed to create credentials %v".
+func fillFailedToCreateCredentials(fields Fields, args []interface{}) string {
+	fields["err"] = args[0]
+	return "Failed to create credentials"
+}
+
+// fillFailedToDecode fills the fields of "Failed to decode (%q, %q): %v".
+func fillFailedToDecode(fields Fields, args []interface{}) string {
+	fields["f.Name"] = args[0]
+	fields["f.Value"] = args[1]
+	fields["err"] = args[2]
+	return "Failed to decode"
+}
+
+// fillFailedToDialPleaseRetry fills the fields of "Failed to dial %s: %v; please retry.".
+func fillFailedToDialPleaseRetry(fields Fields, args []interface{}) string {
+	fields["target"] = args[0]
+	fields["err"] = args[1]
+	return "Failed to dial, please retry"
+}
+
+// fillFailedToFinishTheServerStreamingRpc fills the fields of "Failed to finish the server streaming rpc: %v".
+func fillFailedToFinishTheServerStreamingRpc(fields Fields, args []interface{}) string {
+	fields["err"] = args[0]
+	return "Failed to finish the server streaming rpc"
+}
+
+// fillFailedToGenerateCredentials fills the fields of "Failed to generate credentials %v".
+func fillFailedToGenerateCredentials(fields Fields, args []interface{}) string {
+	fields["err"] = args[0]
+	return "Failed to generate credentials"
+}
+
+// fillFailedToListen fills the fields of "Failed to listen: %v".
+func fillFailedToListen(fields Fields, args []interface{}) string {
+	fields["err"] = args[0]
+	return "failed to listen"
+}
+
+// fillFailedToLoadDefaultFeatures fills the fields of "Failed to load default features: %v".
+func fillFailedToLoadDefaultFeatures(fields Fields, args []interface{}) string {
+	fields["err"] = args[0]
+	return "Failed to load default features"
+}
+
+// fillFailedToParseListenerAddress fills the fields of "Failed to parse listener address: %v".
+func fillFailedToParseListenerAddress(fields Fields, args []interface{}) string {
+	fields["err"] = args[0]
+	return "Failed to parse listener address"
+}
+
+// fillFailedToReadTheServiceAccountKeyFile fills the fields of "Failed to read the service account key file: %v".
+func fillFailedToReadTheServiceAccountKeyFile(fields Fields, args []interface{}) string {
+	fields["err"] = args[0]
+	return "Failed to read the service account key file"
+}
+
+// fillFailedToReceiveANote fills the fields of "Failed to receive a note : %v".
+func fillFailedToReceiveANote(fields Fields, args []interface{}) string {
+	fields["err"] = args[0]
+	return "Failed to receive a note"
+}
+
+// fillFailedToSendANote fills the fields of "Failed to send a note: %v".
+func fillFailedToSendANote(fields Fields, args []interface{}) string {
+	fields["err"] = args[0]
+	return "Failed to send a not"
+}
+
+// fillFailedToServe fills the fields of "Failed to serve: %v".
+func fillFailedToServe(fields Fields, args []interface{}) string {
+	fields["err"] = args[0]
+	return "Failed to serve"
+}
+
+// fillGettingFeatureForPoint fills the fields of "Getting feature for point (%d, %d)".
+func fillGettingFeatureForPoint(fields Fields, args []interface{}) string {
+	fields["point.latitude"] = args[0]
+	fields["point.longitude"] = args[1]
+	return "Getting feature for point"
+}
+
+// fillGotWrongCountOfReplies fills the fields of "Got %d reply, want %d".
+func fillGotWrongCountOfReplies(fields Fields, args []interface{}) string {
+	fields["got.count"] = args[0]
+	fields["want.count"] = args[1]
+	return "got wrong count of replies"
+}
+
+// fillGotUnexpectedOauthScope fills the fields of "Got OAuth scope %q which is NOT a substring of %q.".
+func fillGotUnexpectedOauthScope(fields Fields, args []interface{}) string {
+	fields["got.scope"] = args[0]
+	fields["want.scope"] = args[1]
+	return "Got OAuth scope which is NOT a substring of expected scope"
+}
+
+// fillGotMessageAtPoint fills the fields of "Got message %s at point(%d, %d)".
+func fillGotMessageAtPoint(fields Fields, args []interface{}) string {
+	fields["message"] = args[0]
+	fields["point.latitude"] = args[1]
+	fields["point.longitude"] = args[2]
+	return "got message at point"
+}
+
+// fillGotReplyBodyOfWrongLength fills the fields of "Got reply body of length %d, want %d".
+func fillGotReplyBodyOfWrongLength(fields Fields, args []interface{}) string {
+	fields["got.length"] = args[0]
+	fields["want.length"] = args[1]
+	return "Got reply body of wrong length"
+}
+
+// fillGotTheReplyOfWrongType fills the fields of "Got the reply of type %d, want %d".
+func fillGotTheReplyOfWrongType(fields Fields, args []interface{}) string {
+	fields["got.type"] = args[0]
+	fields["want.type"] = args[1]
+	return "Got the reply of wrong type"
+}
+
+// fillGotTheReplyWithWrongTypeAndLength fills the fields of "Got the reply with type %d len %d; want %d, %d".
+func fillGotTheReplyWithWrongTypeAndLength(fields Fields, args []interface{}) string {
+	fields["got.type"] = args[0]
+	fields["got.len"] = args[1]
+	fields["want.type"] = args[2]
+	fields["want.len"] = args[3]
+	return "Got the reply with wrong type and length"
+}
+
+// fillGotUserNameNotInJsonKey fills the fields of "Got user name %q which is NOT a substring of %q.".
+func fillGotUserNameNotInJsonKey(fields Fields, args []interface{}) string {
+	fields["user"] = args[0]
+	fields["json.key"] = args[1]
+	return "Got user name which is NOT a substring json key"
+}
+
+// fillWrongUserName fills the fields of "Got user name %q, want %q.".
+func fillWrongUserName(fields Fields, args []interface{}) string {
+	fields["got.user"] = args[0]
+	fields["want.user"] = args[1]
+	return "wrong user name"
+}
+
+// fillLookingForFeaturesWithinRectangle fills the fields of "Looking for features within %v".
+func fillLookingForFeaturesWithinRectangle(fields Fields, args []interface{}) string {
+	fields["rect"] = args[0]
+	return "Looking for features withi rectangle"
+}
+
+// fillNewclientconnFailedToCreateAClientconn fills the fields of "NewClientConn(%q) failed to create a ClientConn %v".
+func fillNewclientconnFailedToCreateAClientconn(fields Fields, args []interface{}) string {
+	fields["addr"] = args[0]
+	fields["err"] = args[1]
+	return "NewClientConn(_) failed to create a ClientConn"
+}
+
+// fillPayloadtypeUncompressableIsNotSupported fills the fields of "PayloadType UNCOMPRESSABLE is not supported".
+func fillPayloadtypeUncompressableIsNotSupported(fields Fields, args []interface{}) string {
+	return "PayloadType UNCOMPRESSABLE is not supported"
+}
+
+// fillRequestedAResponseWithInvalidLength fills the fields of "Requested a response with invalid length %d".
+func fillRequestedAResponseWithInvalidLength(fields Fields, args []interface{}) string {
+	fields["length"] = args[0]
+	return "Requested a response with invalid length"
+}
+
+// fillRouteSummary fills the fields of "Route summary: %v".
+func fillRouteSummary(fields Fields, args []interface{}) string {
+	fields["reply"] = args[0]
+	return "Route summary"
+}
+
+// fillSentARequestOfWrongSize fills the fields of "Sent a request of size %d, aggregated size %d".
+func fillSentARequestOfWrongSize(fields Fields, args []interface{}) string {
+	fields["request.size"] = args[0]
+	fields["aggregated.size"] = args[1]
+	return "Sent a request of wrong size"
+}
+
+// fillStreamingcallRecv fills the fields of "StreamingCall(_).Recv: %v".
+func fillStreamingcallRecv(fields Fields, args []interface{}) string {
+	fields["err"] = args[0]
+	return "StreamingCall(_).Recv"
+}
+
+// fillStreamingcallSend fills the fields of "StreamingCall(_).Send: %v".
+func fillStreamingcallSend(fields Fields, args []interface{}) string {
+	fields["err"] = args[0]
+	return "StreamingCall(_).Send"
+}
+
+// fillTlsRequiredForComputeEngineCreds fills the fields of "TLS is not enabled. TLS is required to execute compute_engine_creds test case.".
+func fillTlsRequiredForComputeEngineCreds(fields Fields, args []interface{}) string {
+	return "TLS is not enabled. TLS is required to execute compute_engine_creds test case"
+}
+
+// fillTlsRequiredForServiceAccountCreds fills the fields of "TLS is not enabled. TLS is required to execute service_account_creds test case.".
+func fillTlsRequiredForServiceAccountCreds(fields Fields, args []interface{}) string {
+	return "TLS is not enabled. TLS is required to execute service_account_creds test case"
+}
+
+// fillTraversingPoints fills the fields of "Traversing %d points.".
+func fillTraversingPoints(fields Fields, args []interface{}) string {
+	fields["count"] = args[0]
+	return "traversing points"
+}
+
+// fillUnsupportedPayloadType fills the fields of "Unsupported payload type: %d".
+func fillUnsupportedPayloadType(fields Fields, args []interface{}) string {
+	fields["type"] = args[0]
+	return "unsupported payload type"
+}
+
+// fillFailToDial2 fills the fields of "fail to dial: %v".
+func fillFailToDial2(fields Fields, args []interface{}) string {
+	fields["err"] = args[0]
+	return "fail to dial"
+}
+
+// fillFailedToListen2 fills the fields of "failed to listen: %v".
+func fillFailedToListen2(fields Fields, args []interface{}) string {
+	fields["err"] = args[0]
+	return "failed to listen"
+}
+
+// fillFailedToParseListenerAddress2 fills the fields of "failed to parse listener address: %v".
+func fillFailedToParseListenerAddress2(fields Fields, args []interface{}) string {
+	fields["err"] = args[0]
+	return "Failed to parse listener address"
+}
+
+// fillGrpcSendheader fills the fields of "grpc.SendHeader(%v, %v) = %v, want %v".
+func fillGrpcSendheader(fields Fields, args []interface{}) string {
+	fields["ctx"] = args[0]
+	fields["md"] = args[1]
+	fields["err"] = args[2]
+	fields["want.err"] = args[3]
+	return "grpc.SendHeader"
+}
+
+// fillGrpcClientconnResettransportFailed fills the fields of "grpc: ClientConn.resetTransport failed to create client transport: %v; Reconnecting to %q".
+func fillGrpcClientconnResettransportFailed(fields Fields, args []interface{}) string {
+	fields["package"] = "grpc"
+	fields["err"] = args[0]
+	fields["addr"] = args[1]
+	return "ClientConn.resetTransport failed to create client transport, reconnecting"
+}
+
+// fillGrpcClientconnTransportmonitorExits fills the fields of "grpc: ClientConn.transportMonitor exits due to: %v".
+func fillGrpcClientconnTransportmonitorExits(fields Fields, args []interface{}) string {
+	fields["package"] = "grpc"
+	fields["err"] = args[0]
+	return "ClientConn.transportMonitor exits"
+}
+
+// fillGrpcSendheaderStreamHasNoServertransport fills the fields of "grpc: SendHeader: %v has no ServerTransport to send header metadata.".
+func fillGrpcSendheaderStreamHasNoServertransport(fields Fields, args []interface{}) string {
+	fields["package"] = "grpc"
+	fields["stream"] = args[0]
+	return "SendHeader: stream has no ServerTransport to send header metadata"
+}
+
+// fillGrpcServerFailedToEncodeResponse fills the fields of "grpc: Server failed to encode response %v".
+func fillGrpcServerFailedToEncodeResponse(fields Fields, args []interface{}) string {
+	fields["package"] = "grpc"
+	fields["err"] = args[0]
+	return "Server failed to encode response"
+}
+
+// fillGrpcServerRegisterserviceFoundDuplicateServiceRegistration fills the fields of "grpc: Server.RegisterService found duplicate service registration for %q".
+func fillGrpcServerRegisterserviceFoundDuplicateServiceRegistration(fields Fields, args []interface{}) string {
+	fields["package"] = "grpc"
+	fields["service.name"] = args[0]
+	return "Server.RegisterService found duplicate service registration"
+}
+
+// fillGrpcServerRegisterserviceFoundUnsatisfyingHandler fills the fields of "grpc: Server.RegisterService found the handler of type %v that does not satisfy %v".
+func fillGrpcServerRegisterserviceFoundUnsatisfyingHandler(fields Fields, args []interface{}) string {
+	fields["package"] = "grpc"
+	fields["found.type"] = args[0]
+	fields["expected.type"] = args[1]
+	return "Server.RegisterService found handler of type that does not satisfy expectations"
+}
+
+// fillGrpcServerHandlestreamFailedToWriteStatus fills the fields of "grpc: Server.handleStream failed to write status: %v".
+func fillGrpcServerHandlestreamFailedToWriteStatus(fields Fields, args []interface{}) string {
+	fields["package"] = "grpc"
+	fields["err"] = args[0]
+	return "Server.handleStream failed to write status"
+}
+
+// fillGrpcServerProcessunaryrpcFailedToWriteStatus fills the fields of "grpc: Server.processUnaryRPC failed to write status: %v".
+func fillGrpcServerProcessunaryrpcFailedToWriteStatus(fields Fields, args []interface{}) string {
+	fields["package"] = "grpc"
+	fields["err"] = args[0]
+	return "Server.processUnaryRPC failed to write status"
+}
+
+// fillHandlestreamGotError fills the fields of "handleStream got error: %v, want <nil>; result: %v, want %v".
+func fillHandlestreamGotError(fields Fields, args []interface{}) string {
+	fields["err"] = args[0]
+	fields["got.result"] = args[1]
+	fields["want.result"] = args[2]
+	return "handleStream got error"
+}
+
+// fillTransportHttp2clientControllerGotUnexpectedItemType fills the fields of "transport: http2Client.controller got unexpected item type %v".
+func fillTransportHttp2clientControllerGotUnexpectedItemType(fields Fields, args []interface{}) string {
+	fields["package"] = "transport"
+	fields["item.type"] = args[0]
+	return "http2Client.controller got unexpected item type"
+}
+
+// fillTransportHttp2clientNotifyerrorClientTransportBroken fills the fields of "transport: http2Client.notifyError got notified that the client transport was broken %v.".
+func fillTransportHttp2clientNotifyerrorClientTransportBroken(fields Fields, args []interface{}) string {
+	fields["package"] = "transport"
+	fields["err"] = args[0]
+	return "http2Client.notifyError got notified that the client transport was broken"
+}
+
+// fillTransportHttp2clientReaderGotUnhandledFrameType fills the fields of "transport: http2Client.reader got unhandled frame type %v.".
+func fillTransportHttp2clientReaderGotUnhandledFrameType(fields Fields, args []interface{}) string {
+	fields["package"] = "transport"
+	fields["frame"] = args[0]
+	return "http2Client.reader got unhandled frame type"
+}
+
+// fillTransportHttp2serverError fills the fields of "transport: http2Server %v".
+func fillTransportHttp2serverError(fields Fields, args []interface{}) string {
+	fields["package"] = "transport"
+	fields["err"] = args[0]
+	return "http2Server error"
+}
+
+// fillTransportHttp2serverHandlestreamsFailedToReadFrame fills the fields of "transport: http2Server.HandleStreams failed to read frame: %v".
+func fillTransportHttp2serverHandlestreamsFailedToReadFrame(fields Fields, args []interface{}) string {
+	fields["package"] = "transport"
+	fields["err"] = args[0]
+	return "http2Server.HandleStreams failed to read frame"
+}
+
+// fillTransportHttp2serverHandlestreamsFailedToReceiveThePrefaceFromClient fills the fields of "transport: http2Server.HandleStreams failed to receive the preface from client: %v".
+func fillTransportHttp2serverHandlestreamsFailedToReceiveThePrefaceFromClient(fields Fields, args []interface{}) string {
+	fields["package"] = "transport"
+	fields["err"] = args[0]
+	return "http2Server.HandleStreams failed to receive the preface from client"
+}
+
+// fillTransportHttp2serverHandlestreamsFoundUnhandledFrameType fills the fields of "transport: http2Server.HandleStreams found unhandled frame type %v.".
+func fillTransportHttp2serverHandlestreamsFoundUnhandledFrameType(fields Fields, args []interface{}) string {
+	fields["package"] = "transport"
+	fields["frame"] = args[0]
+	return "http2Server.HandleStreams found unhandled frame type"
+}
+
+// fillTransportHttp2serverHandlestreamsReceivedBogusGreetingFromClient fills the fields of "transport: http2Server.HandleStreams received bogus greeting from client: %q".
+func fillTransportHttp2serverHandlestreamsReceivedBogusGreetingFromClient(fields Fields, args []interface{}) string {
+	fields["package"] = "transport"
+	fields["preface"] = args[0]
+	return "http2Server.HandleStreams received bogus greeting from client"
+}
+
+// fillTransportHttp2serverHandlestreamsSawInvalidPrefaceTypeFromClient fills the fields of "transport: http2Server.HandleStreams saw invalid preface type %T from client".
+func fillTransportHttp2serverHandlestreamsSawInvalidPrefaceTypeFromClient(fields Fields, args []interface{}) string {
+	fields["package"] = "transport"
+	fields["frame"] = typeOf(args[0])
+	return "http2Server.HandleStreams saw invalid preface type from client"
+}
+
+// fillTransportHttp2serverControllerGotUnexpectedItemType fills the fields of "transport: http2Server.controller got unexpected item type %v".
+func fillTransportHttp2serverControllerGotUnexpectedItemType(fields Fields, args []interface{}) string {
+	fields["package"] = "transport"
+	fields["item.type"] = args[0]
+	return "http2Server.controller got unexpected item type"
+}
+
+// fillTransportHttp2serverOperateheaderFound fills the fields of "transport: http2Server.operateHeader found %v".
+func fillTransportHttp2serverOperateheaderFound(fields Fields, args []interface{}) string {
+	fields["package"] = "transport"
+	fields["err"] = args[0]
+	return "http2Server.operateHeader found"
+}
+
+var parselnFillers = map[string]filler{
+	"/TestService/EmptyCall RPC failed: ":                       fillTestserviceEmptycallRpcFailed,
+	"/TestService/UnaryCall RPC failed: ":                       fillTestserviceUnarycallRpcFailed,
+	"CancelAfterBegin done":                                     fillCancelafterbeginDone,
+	"CancelAfterFirstResponse done":                             fillCancelafterfirstresponseDone,
+	"Client profiling address: ":                                fillClientProfilingAddress,
+	"ClientStreaming done":                                      fillClientstreamingDone,
+	"ComputeEngineCreds done":                                   fillComputeenginecredsDone,
+	"EmptyUnaryCall done":                                       fillEmptyunarycallDone,
+	"LargeUnaryCall done":                                       fillLargeunarycallDone,
+	"Pingpong done":                                             fillPingpongDone,
+	"Server Address: ":                                          fillServerAddress,
+	"Server profiling address: ":                                fillServerProfilingAddress,
+	"ServerStreaming done":                                      fillServerstreamingDone,
+	"ServiceAccountCreds done":                                  fillServiceaccountcredsDone,
+	"Unsupported test case: ":                                   fillUnsupportedTestCase,
+	"grpc: Server.Serve failed to complete security handshake.": fillGrpcServerServeFailedToCompleteSecurityHandshake,
+	"grpc: Server.Serve failed to create ServerTransport: ":     fillGrpcServerServeFailedToCreateServertransport,
+	"transport: http2Client.handleRSTStream found no mapped gRPC status for the received http2 error ": fillTransportHttp2clientHandlerststreamNoMappedGrpcStatus,
+	"transport: http2Server.HandleStreams received an illegal stream id: ":                             fillTransportHttp2serverHandlestreamsReceivedAnIllegalStreamId,
+}
+
+// fillTestserviceEmptycallRpcFailed fills the fields of "/TestService/EmptyCall RPC failed: ".
+func fillTestserviceEmptycallRpcFailed(fields Fields, args []interface{}) string {
+	fields["err"] = args[0]
+	return "/TestService/EmptyCall RPC failed"
+}
+
+// fillTestserviceUnarycallRpcFailed fills the fields of "/TestService/UnaryCall RPC failed: ".
+func fillTestserviceUnarycallRpcFailed(fields Fields, args []interface{}) string {
+	fields["err"] = args[0]
+	return "/TestService/UnaryCall RPC failed"
+}
+
+// fillCancelafterbeginDone fills the fields of "CancelAfterBegin done".
+func fillCancelafterbeginDone(fields Fields, args []interface{}) string {
+	return "CancelAfterBegin done"
+}
+
+// fillCancelafterfirstresponseDone fills the fields of "CancelAfterFirstResponse done".
+func fillCancelafterfirstresponseDone(fields Fields, args []interface{}) string {
+	return "CancelAfterFirstResponse done"
+}
+
+// fillClientProfilingAddress fills the fields of "Client profiling address: ".
+func fillClientProfilingAddress(fields Fields, args []interface{}) string {
+	fields["addr"] = args[0]
+	return "Client profiling address"
+}
+
+// fillClientstreamingDone fills the fields of "ClientStreaming done".
+func fillClientstreamingDone(fields Fields, args []interface{}) string {
+	return "ClientStreaming done"
+}
+
+// fillComputeenginecredsDone fills the fields of "ComputeEngineCreds done".
+func fillComputeenginecredsDone(fields Fields, args []interface{}) string {
+	return "ComputeEngineCreds done"
+}
+
+// fillEmptyunarycallDone fills the fields of "EmptyUnaryCall done".
+func fillEmptyunarycallDone(fields Fields, args []interface{}) string {
+	return "EmptyUnaryCall done"
+}
+
+// fillLargeunarycallDone fills the fields of "LargeUnaryCall done".
+func fillLargeunarycallDone(fields Fields, args []interface{}) string {
+	return "LargeUnaryCall done"
+}
+
+// fillPingpongDone fills the fields of "Pingpong done".
+func fillPingpongDone(fields Fields, args []interface{}) string {
+	return "Pingpong done"
+}
+
+// fillServerAddress fills the fields of "Server Address: ".
+func fillServerAddress(fields Fields, args []interface{}) string {
+	fields["addr"] = args[0]
+	return "Server Address"
+}
+
+// fillServerProfilingAddress fills the fields of "Server profiling address: ".
+func fillServerProfilingAddress(fields Fields, args []interface{}) string {
+	fields["addr"] = args[0]
+	return "Server profiling address"
+}
+
+// fillServerstreamingDone fills the fields of "ServerStreaming done".
+func fillServerstreamingDone(fields Fields, args []interface{}) string {
+	return "ServerStreaming done"
+}
+
+// fillServiceaccountcredsDone fills the fields of "ServiceAccountCreds done".
+func fillServiceaccountcredsDone(fields Fields, args []interface{}) string {
+	return "ServiceAccountCreds done"
+}
+
+// fillUnsupportedTestCase fills the fields of "Unsupported test case: ".
+func fillUnsupportedTestCase(fields Fields, args []interface{}) string {
+	fields["test.case"] = args[0]
+	return "Unsupported test case"
+}
+
+// fillGrpcServerServeFailedToCompleteSecurityHandshake fills the fields of "grpc: Server.Serve failed to complete security handshake.".
+func fillGrpcServerServeFailedToCompleteSecurityHandshake(fields Fields, args []interface{}) string {
+	fields["package"] = "grpc"
+	return "Server.Serve failed to complete security handshake"
+}
+
+// fillGrpcServerServeFailedToCreateServertransport fills the fields of "grpc: Server.Serve failed to create ServerTransport: ".
+func fillGrpcServerServeFailedToCreateServertransport(fields Fields, args []interface{}) string {
+	fields["package"] = "grpc"
+	fields["err"] = args[0]
+	return "Server.Serve failed to create ServerTransport"
+}
+
+// fillTransportHttp2clientHandlerststreamNoMappedGrpcStatus fills the fields of "transport: http2Client.handleRSTStream found no mapped gRPC status for the received http2 error ".
+func fillTransportHttp2clientHandlerststreamNoMappedGrpcStatus(fields Fields, args []interface{}) string {
+	fields["package"] = "transport"
+	fields["err"] = args[0]
+	return "http2Client.handleRSTStream found no mapped gRPC status for the received http2 error"
+}
+
+// fillTransportHttp2serverHandlestreamsReceivedAnIllegalStreamId fills the fields of "transport: http2Server.HandleStreams received an illegal stream id: ".
+func fillTransportHttp2serverHandlestreamsReceivedAnIllegalStreamId(fields Fields, args []interface{}) string {
+	fields["package"] = "transport"
+	fields["id"] = args[0]
+	fields[StreamIDKey] = sprint(args[0])
+	return "http2Server.HandleStreams received an illegal stream id"
+}
@@ -0,0 +1,226 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// argLabels are the fields receiving each arg of the rules of Parsef,
+// by position.
+var argLabels = map[string][]string{
+	"%v compleled with error code %d, want %d":                  {"stream", "got.code", "want.code"},
+	"%v failed to complele the ping pong test: %v":              {"stream", "err"},
+	"%v.CloseAndRecv() got error %v, want %v":                   {"stream", "err", "want.err"},
+	"%v.CloseAndRecv() got error code %d, want %d":              {"stream", "got.code", "want.code"},
+	"%v.CloseAndRecv().GetAggregatePayloadSize() = %v; want %v": {"stream", "got.size", "want.size"},
+	"%v.CloseSend() got %v, want %v":                            {"stream", "err", "want.err"},
+	"%v.FullDuplexCall(_) = _, %v":                              {"tc", "err"},
+	"%v.GetFeatures(_) = _, %v: ":                               {"client", "err"},
+	"%v.ListFeatures(_) = _, %v":                                {"client", "err"},
+	"%v.RecordRoute(_) = _, %v":                                 {"client", "err"},
+	"%v.Recv() = %v":                                            {"stream", "err"},
+	"%v.RouteChat(_) = _, %v":                                   {"client", "err"},
+	"%v.Send(%v) = %v":                                          {"stream", "point", "err"},
+	"%v.SendHeader(%v) = %v, want %v":                           {"stream", "md", "err", "want.err"},
+	"%v.StreamingCall(_) = _, %v":                               {"tc", "err"},
+	"%v.StreamingInputCall(_) = _, %v":                          {"tc", "err"},
+	"%v.StreamingOutputCall(_) = _, %v":                         {"tc", "err"},
+	"/TestService/EmptyCall receives %v, want %v":               {"got.reply", "want.reply"},
+	"Dial(%q) = %v":                                             {"addr", "err"},
+	"Fail to dial: %v":                                          {"err"},
+	"Failed to convert %v to *http2Server":                      {"s.ServerTransport()"},
+	"Failed to create JWT credentials: %v":                      {"err"},
+	"Failed to create TLS credentials %v":                       {"err"},
+	"Failed to create credentials %v":                           {"err"},
+	"Failed to decode (%q, %q): %v":                             {"f.Name", "f.Value", "err"},
+	"Failed to dial %s: %v; please retry.":                      {"target", "err"},
+	"Failed to finish the server streaming rpc: %v":             {"err"},
+	"Failed to generate credentials %v":                         {"err"},
+	"Failed to listen: %v":                                      {"err"},
+	"Failed to load default features: %v":                       {"err"},
+	"Failed to parse listener address: %v":                      {"err"},
+	"Failed to read the service account key file: %v":           {"err"},
+	"Failed to receive a note : %v":                             {"err"},
+	"Failed to send a note: %v":                                 {"err"},
+	"Failed to serve: %v":                                       {"err"},
+	"Getting feature for point (%d, %d)":                        {"point.latitude", "point.longitude"},
+	"Got %d reply, want %d":                                     {"got.count", "want.count"},
+	"Got OAuth scope %q which is NOT a substring of %q.":        {"got.scope", "want.scope"},
+	"Got message %s at point(%d, %d)":                           {"message", "point.latitude", "point.longitude"},
+	"Got reply body of length %d, want %d":                      {"got.length", "want.length"},
+	"Got the reply of type %d, want %d":                         {"got.type", "want.type"},
+	"Got the reply with type %d len %d; want %d, %d":            {"got.type", "got.len", "want.type", "want.len"},
+	"Got user name %q which is NOT a substring of %q.":          {"user", "json.key"},
+	"Got user name %q, want %q.":                                {"got.user", "want.user"},
+	"Looking for features within %v":                            {"rect"},
+	"NewClientConn(%q) failed to create a ClientConn %v":        {"addr", "err"},
+	"Requested a response with invalid length %d":               {"length"},
+	"Route summary: %v":                                         {"reply"},
+	"Sent a request of size %d, aggregated size %d":             {"request.size", "aggregated.size"},
+	"StreamingCall(_).Recv: %v":                                 {"err"},
+	"StreamingCall(_).Send: %v":                                 {"err"},
+	"Traversing %d points.":                                     {"count"},
+	"Unsupported payload type: %d":                              {"type"},
+	"fail to dial: %v":                                          {"err"},
+	"failed to listen: %v":                                      {"err"},
+	"failed to parse listener address: %v":                      {"err"},
+	"grpc.SendHeader(%v, %v) = %v, want %v":                     {"ctx", "md", "err", "want.err"},
+	"grpc: ClientConn.resetTransport failed to create client transport: %v; Reconnecting to %q": {"err", "addr"},
+	"grpc: ClientConn.transportMonitor exits due to: %v":                                        {"err"},
+	"grpc: SendHeader: %v has no ServerTransport to send header metadata.":                      {"stream"},
+	"grpc: Server failed to encode response %v":                                                 {"err"},
+	"grpc: Server.RegisterService found duplicate service registration for %q":                  {"service.name"},
+	"grpc: Server.RegisterService found the handler of type %v that does not satisfy %v":        {"found.type", "expected.type"},
+	"grpc: Server.handleStream failed to write status: %v":                                      {"err"},
+	"grpc: Server.processUnaryRPC failed to write status: %v":                                   {"err"},
+	"handleStream got error: %v, want <nil>; result: %v, want %v":                               {"err", "got.result", "want.result"},
+	"transport: http2Client.controller got unexpected item type %v":                             {"item.type"},
+	"transport: http2Client.notifyError got notified that the client transport was broken %v.":  {"err"},
+	"transport: http2Client.reader got unhandled frame type %v.":                                {"frame"},
+	"transport: http2Server %v":                                                                 {"err"},
+	"transport: http2Server.HandleStreams failed to read frame: %v":                             {"err"},
+	"transport: http2Server.HandleStreams failed to receive the preface from client: %v":        {"err"},
+	"transport: http2Server.HandleStreams found unhandled frame type %v.":                       {"frame"},
+	"transport: http2Server.HandleStreams received bogus greeting from client: %q":              {"preface"},
+	"transport: http2Server.HandleStreams saw invalid preface type %T from client":              {"frame"},
+	"transport: http2Server.controller got unexpected item type %v":                             {"item.type"},
+	"transport: http2Server.operateHeader found %v":                                             {"err"},
+}
+
+// sentinelType is the type of the args of %T verbs.
+type sentinelType struct{}
+
+// sentinel is a value recognizable in the fields of a rule, for the arg
+// at position i of a verb.
+func sentinel(i int, verb byte) interface{} {
+	if verb == 'T' {
+		return sentinelType{}
+	}
+	return fmt.Sprintf("sentinel%d", i)
+}
+
+// formatVerbs returns the verbs of a format, in order, and the text
+// before each.
+func formatVerbs(format string) (verbs []byte, before []string) {
+	start := 0
+	for i := 0; i+1 < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		if format[i] == '%' {
+			continue
+		}
+		verbs = append(verbs, format[i])
+		before = append(before, format[start:i-1])
+		start = i + 1
+	}
+	return verbs, before
+}
+
+func TestRuleArgLabels(t *testing.T) {
+	for format, r := range parsefRules {
+		verbs, _ := formatVerbs(format)
+		if len(verbs) == 0 {
+			continue
+		}
+		labels, ok := argLabels[format]
+		if !ok {
+			t.Errorf("%q: no labels in argLabels", format)
+			continue
+		}
+		if len(labels) != len(verbs) {
+			t.Errorf("%q: %d labels for %d verbs", format, len(labels), len(verbs))
+			continue
+		}
+		args := make([]interface{}, len(verbs))
+		for i, verb := range verbs {
+			args[i] = sentinel(i, verb)
+		}
+		fields, _ := r.parse(args...)
+		for i, label := range labels {
+			want := fmt.Sprint(args[i])
+			if verbs[i] == 'T' {
+				want = "sentinelType"
+			}
+			got := fmt.Sprint(fields[label])
+			if !strings.Contains(got, want) {
+				t.Errorf("%q: want arg %d in %q, got %q", format, i, label, got)
+			}
+			for k, v := range fields {
+				if k != label && strings.Contains(fmt.Sprint(v), want) {
+					t.Errorf("%q: arg %d also in %q", format, i, k)
+				}
+			}
+		}
+	}
+}
+
+// sides are the words marking the args that follow as got or wanted,
+// until the end of the clause.
+var sides = regexp.MustCompile(`(?i)\b(got|want|substring of)\b|;`)
+
+// TestRuleArgLabelSides checks argLabels against the formats: want.*
+// labels are for the args after "want", which aren't labeled got.*.
+func TestRuleArgLabelSides(t *testing.T) {
+	for format, labels := range argLabels {
+		_, before := formatVerbs(format)
+		side := ""
+		for i, label := range labels {
+			for _, m := range sides.FindAllString(before[i], -1) {
+				switch m = strings.ToLower(m); m {
+				case ";":
+					side = ""
+				case "substring of":
+					side = "want"
+				default:
+					side = m
+				}
+			}
+			switch {
+			case side != "want" && strings.HasPrefix(label, "want."):
+				t.Errorf("%q: arg %d isn't wanted but labeled %q", format, i, label)
+			case side == "want" && strings.HasPrefix(label, "got."):
+				t.Errorf("%q: arg %d is wanted but labeled %q", format, i, label)
+			}
+		}
+	}
+}
+
+func TestFillersMatchRules(t *testing.T) {
+	for _, table := range []struct {
+		rules   map[string]rule
+		fillers map[string]filler
+		argc    func(string) int
+	}{
+		{parsefRules, parsefFillers, verbs},
+		{parselnRules, parselnFillers, lnArgc},
+	} {
+		for format, r := range table.rules {
+			fill, ok := table.fillers[format]
+			if !ok {
+				continue
+			}
+			args := make([]interface{}, table.argc(format))
+			for i := range args {
+				args[i] = sentinel(i, 'v')
+			}
+			want, wantMessage := r.parse(args...)
+			got := make(Fields)
+			if message := fill(got, args); message != wantMessage {
+				t.Errorf("%q: want message %q, got %q", format, wantMessage, message)
+			}
+			if len(got) != len(want) {
+				t.Errorf("%q: want fields %v, got %v", format, want, got)
+				continue
+			}
+			for k, v := range want {
+				if got[k] != v {
+					t.Errorf("%q: want %s=%v, got %v", format, k, v, got[k])
+				}
+			}
+		}
+	}
+}
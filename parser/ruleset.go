@@ -0,0 +1,177 @@
+package parser
+
+import "sync/atomic"
+
+// ruleSet holds the rules along with what's derived from them when they
+// are loaded. It's immutable once made: changing the rules means making
+// a new set and swapping it in, so that looking rules up, on every
+// message, never takes a lock.
+type ruleSet struct {
+	parsef  map[string]*compiledRule
+	parseln map[string]*compiledRule
+	// lines are sorted from the most to the least specific, so that
+	// formats sharing a shape don't shadow longer ones.
+	lines []linePattern
+}
+
+// compiledRule is a rule of a set, with its format, source, match
+// counter, arity and the names of its fields.
+type compiledRule struct {
+	rule
+	format   string
+	source   string
+	matches  *uint64
+	arity    int
+	fields   []string
+	category Category
+	// argc is the number of args the rule reads, checked before passing
+	// them rather than recovering from the rule indexing past them.
+	argc int
+	// shared are the fields of the messages it matches when it extracts
+	// none but their id, category and template, shared read-only by these
+	// messages.
+	shared Fields
+	// fill is the generated filler of the rule, nil for the rules of
+	// packs and those genrules skips.
+	fill filler
+}
+
+// filler fills the fields of a message in place, returning the message:
+// the rules of rules.go compiled by genrules into rules_gen.go, for the
+// fields of messages to be pooled.
+type filler func(fields Fields, args []interface{}) string
+
+// structure returns the fields and message of args, filled into pooled
+// fields by the rule's filler when it has one, nil when there are none.
+func (c *compiledRule) structure(args []interface{}) (Fields, string) {
+	if c.fill == nil {
+		return c.parse(args...)
+	}
+	fields := newFields()
+	message := c.fill(fields, args)
+	if len(fields) == 0 {
+		fieldsPool.Put(fields)
+		return nil, message
+	}
+	return fields, message
+}
+
+// rules is the current set, loaded once per message.
+var rules atomic.Pointer[ruleSet]
+
+func init() {
+	rules.Store(newRuleSet(nil, nil))
+}
+
+// newRuleSet makes the set of the rules of this package and of packs, in
+// the order they were loaded, a pack's rules replacing those of the same
+// format. Its rules keep the match counts of those of prev, the set it
+// replaces, if any.
+func newRuleSet(packs []Pack, prev *ruleSet) *ruleSet {
+	set := &ruleSet{
+		parsef:  compileRules(parsefRules, verbs, SourceBuiltin, categoryOf),
+		parseln: compileRules(parselnRules, lnArgc, SourceBuiltin, categoryOf),
+	}
+	setFillers(set.parsef, parsefFillers)
+	setFillers(set.parseln, parselnFillers)
+	for _, p := range packs {
+		parsef, parseln, category := p.rules()
+		for format, c := range compileRules(parsef, verbs, p.Name, category) {
+			set.parsef[format] = c
+		}
+		for format, c := range compileRules(parseln, lnArgc, p.Name, category) {
+			set.parseln[format] = c
+		}
+	}
+	if prev != nil {
+		keepMatches(set.parsef, prev.parsef)
+		keepMatches(set.parseln, prev.parseln)
+	}
+	set.lines = compileLinePatterns(set)
+	return set
+}
+
+// keepMatches shares the match counters of the rules of a set before
+// with those of the same format, id and source, the messages parsed by
+// either counting alike.
+func keepMatches(compiled, prev map[string]*compiledRule) {
+	for format, c := range compiled {
+		if p, ok := prev[format]; ok && p.id == c.id && p.source == c.source {
+			c.matches = p.matches
+		}
+	}
+}
+
+// setFillers sets the fillers of compiled rules.
+func setFillers(compiled map[string]*compiledRule, fillers map[string]filler) {
+	for format, fill := range fillers {
+		if c, ok := compiled[format]; ok {
+			c.fill = fill
+		}
+	}
+}
+
+// lnArgc is the argc of Parseln's rules, which take their args after
+// the format as one.
+func lnArgc(string) int { return 1 }
+
+// compileRules probes the rules of a source with as many args as their
+// format takes, argc, for their arity and fields.
+func compileRules(rules map[string]rule, argc func(format string) int, source string, category func(id string) Category) map[string]*compiledRule {
+	compiled := make(map[string]*compiledRule, len(rules))
+	for format, r := range rules {
+		fields, arity := probe(r, make([]interface{}, argc(format)))
+		c := &compiledRule{
+			rule:     r,
+			format:   format,
+			source:   source,
+			matches:  new(uint64),
+			arity:    arity,
+			fields:   fields,
+			category: category(r.id),
+			argc:     reads(r, argc(format)),
+		}
+		if c.arity == 1 {
+			c.shared = Fields{EventIDKey: r.id, CategoryKey: string(c.category), MsgTemplateKey: format}
+		}
+		compiled[format] = c
+	}
+	return compiled
+}
+
+// matched counts a message the rule structured into fields from args,
+// returning the fields with its id, category and template, the ids of
+// its stream or connection, and the status and network details of its
+// error.
+func (c *compiledRule) matched(fields Fields, args []interface{}) Fields {
+	atomic.AddUint64(c.matches, 1)
+	s := errorStatus(args)
+	netErr := netError(args)
+	if fields == nil && s == nil && netErr == nil && c.shared != nil {
+		return c.shared
+	}
+	if fields == nil {
+		fields = newFields()
+	}
+	fields[EventIDKey] = c.id
+	fields[CategoryKey] = string(c.category)
+	fields[MsgTemplateKey] = c.format
+	if s != nil {
+		addStatus(fields, s)
+	}
+	if netErr != nil {
+		addNetError(fields, netErr)
+	}
+	addIDs(fields)
+	return fields
+}
+
+// degraded structures a message given fewer args than the rule takes.
+func (c *compiledRule) degraded(format string, args []interface{}) (Fields, string, Level) {
+	fields := argFields(args)
+	fields[EventIDKey] = c.id
+	fields[CategoryKey] = string(c.category)
+	fields[MsgTemplateKey] = c.format
+	fields[ParseErrorKey] = true
+	return fields, format, c.levelOf()
+}
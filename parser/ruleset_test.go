@@ -0,0 +1,31 @@
+package parser
+
+import "testing"
+
+func TestParselnWithoutArgs(t *testing.T) {
+	for format, r := range parselnRules {
+		if !takes(r, 0) {
+			continue
+		}
+		fields, message, level := Parseln(format)
+		if fields[EventIDKey] != r.id {
+			t.Errorf("%q: want event id %q, got %v", format, r.id, fields[EventIDKey])
+		}
+		if _, ok := fields[ParseErrorKey]; ok {
+			t.Errorf("%q: unexpected %s", format, ParseErrorKey)
+		}
+		if _, want := r.parse(); message != want {
+			t.Errorf("%q: want message %q, got %q", format, want, message)
+		}
+		if level != r.level {
+			t.Errorf("%q: want level %v, got %v", format, r.level, level)
+		}
+	}
+}
+
+func TestParselnConstantFields(t *testing.T) {
+	fields, _, _ := Parseln("grpc: Server.Serve failed to complete security handshake.")
+	if fields["package"] != "grpc" {
+		t.Errorf("want package grpc, got %v", fields)
+	}
+}
@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/status"
+)
+
+// Fields of the status of the first error arg of a message, when it is a
+// grpc status error, or a context's deadline or cancellation: the code by
+// name and number, and the number of details of the status. They are
+// named as the fields of the interceptors of package grpclogrus.
+const (
+	CodeKey         = "code"
+	CodeNumberKey   = "code_number"
+	DetailsCountKey = "details_count"
+)
+
+// errorStatus is the status of the first error of args that has one.
+func errorStatus(args []interface{}) *status.Status {
+	for _, arg := range args {
+		err, ok := arg.(error)
+		if !ok || err == nil {
+			continue
+		}
+		if s, ok := status.FromError(err); ok {
+			return s
+		}
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+			return status.FromContextError(err)
+		}
+	}
+	return nil
+}
+
+// addStatus adds the fields of s, unless the rule set them already.
+func addStatus(fields Fields, s *status.Status) {
+	if _, ok := fields[CodeKey]; ok {
+		return
+	}
+	fields[CodeKey] = s.Code().String()
+	fields[CodeNumberKey] = int(s.Code())
+	fields[DetailsCountKey] = len(s.Proto().GetDetails())
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"bufio"
+	"context"
+	"io"
+)
+
+// DefaultMaxLineLength is the MaxLineLength of a zero Parser, Folder or
+// Unwrapper.
+const DefaultMaxLineLength = 64 << 10
+
+func maxLineLength(max int) int {
+	if max <= 0 {
+		return DefaultMaxLineLength
+	}
+	return max
+}
+
+// Parser parses streams of grpclog output into entries, folding
+// multi-line ones, with memory bounded by MaxLineLength and the size of
+// the entries. The zero value is ready to use.
+type Parser struct {
+	// MaxLineLength bounds the lines read. Longer lines are cut to it,
+	// the rest of them dropped, and their entries marked with
+	// TruncatedKey. Set before use.
+	MaxLineLength int
+}
+
+// Run calls fn with the entries read from r until r is exhausted, fn
+// returns an error, or ctx is done, returning the error that stopped it,
+// if not io.EOF. Reading waits for fn to return, so a slow fn slows down
+// the reads rather than making entries pile up. ctx is checked between
+// lines: a read blocked on r isn't interrupted.
+func (p *Parser) Run(ctx context.Context, r io.Reader, fn func(Entry) error) error {
+	max := maxLineLength(p.MaxLineLength)
+	br := bufio.NewReaderSize(r, max)
+
+	folder := Folder{MaxLineLength: max}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line, isPrefix, err := br.ReadLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		e, ok := folder.Push(string(line))
+		if isPrefix {
+			skipped, err := skipLine(br)
+			if err != nil && err != io.EOF {
+				return err
+			}
+			if skipped > 0 {
+				folder.Truncate()
+			}
+		}
+		if ok {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+	}
+	if e, ok := folder.Flush(); ok {
+		return fn(e)
+	}
+	return nil
+}
+
+// skipLine discards the rest of a line, returning its length.
+func skipLine(br *bufio.Reader) (n int, err error) {
+	for {
+		rest, isPrefix, err := br.ReadLine()
+		n += len(rest)
+		if err != nil || !isPrefix {
+			return n, err
+		}
+	}
+}
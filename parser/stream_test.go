@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRunTruncatesLongLines(t *testing.T) {
+	long := "WARNING: 2020/01/01 00:00:00 " + strings.Repeat("x", 100)
+	in := long + "\n\tat frame\n" + "INFO: 2020/01/01 00:00:01 next\n"
+	var entries []Entry
+	p := Parser{MaxLineLength: 64}
+	err := p.Run(context.Background(), strings.NewReader(in), func(e Entry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("want 2 entries, got %d: %v", len(entries), entries)
+	}
+	if entries[0].Fields[TruncatedKey] != true {
+		t.Errorf("want the first entry truncated, got %v", entries[0].Fields)
+	}
+	if stack := entries[0].Fields[StackKey]; stack != "\tat frame" {
+		t.Errorf("want the stack to hold the continuation line only, got %q", stack)
+	}
+	if _, ok := entries[1].Fields[TruncatedKey]; ok {
+		t.Errorf("want the second entry whole, got %v", entries[1].Fields)
+	}
+}
+
+func TestRunTruncatesLongStacks(t *testing.T) {
+	in := "WARNING: 2020/01/01 00:00:00 panic\n" + strings.Repeat("\tat frame\n", 20)
+	var entries []Entry
+	p := Parser{MaxLineLength: 64}
+	err := p.Run(context.Background(), strings.NewReader(in), func(e Entry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("want 1 entry, got %d: %v", len(entries), entries)
+	}
+	if stack := entries[0].Fields[StackKey].(string); len(stack) > 64 || !strings.HasPrefix(stack, "\tat frame\n") {
+		t.Errorf("want the stack cut to 64 bytes, got %q", stack)
+	}
+	if entries[0].Fields[TruncatedKey] != true {
+		t.Errorf("want the entry truncated, got %v", entries[0].Fields)
+	}
+}
+
+func TestUnwrapTruncatesPartialLines(t *testing.T) {
+	u := Unwrapper{MaxLineLength: 16}
+	for i := 0; i < 10; i++ {
+		if _, _, ok := u.Unwrap("2020-01-01T00:00:00Z stderr P " + strings.Repeat("x", 8)); ok {
+			t.Fatal("want a partial piece held")
+		}
+	}
+	line, fields, ok := u.Unwrap("2020-01-01T00:00:00Z stderr F end")
+	if !ok || line != strings.Repeat("x", 16) || fields[TruncatedKey] != true {
+		t.Errorf("want the line cut to 16 bytes and truncated, got %q %v", line, fields)
+	}
+	line, fields, _ = u.Unwrap("2020-01-01T00:00:01Z stderr F next")
+	if line != "next" || fields[TruncatedKey] != nil {
+		t.Errorf("want the next line whole, got %q %v", line, fields)
+	}
+}
@@ -0,0 +1,54 @@
+package parser
+
+import "sync/atomic"
+
+type strictMode struct {
+	report func(format string)
+}
+
+var (
+	strict         atomic.Pointer[strictMode]
+	unknownFormats uint64
+)
+
+// SetStrict turns strict mode on or off, for rule pack maintainers to
+// discover the formats of new grpc-go versions deliberately: in strict
+// mode, the messages Parsef and Parseln find no rule for are structured
+// at ErrorLevel rather than InfoLevel, counted in UnknownFormats, and
+// their format passed to report, when not nil, e.g. to fail a test.
+// Report is called from the goroutine logging the message.
+func SetStrict(on bool, report func(format string)) {
+	if !on {
+		strict.Store(nil)
+		return
+	}
+	strict.Store(&strictMode{report: report})
+}
+
+// UnknownFormats is the number of messages strict mode found no rule for
+// since the process started.
+func UnknownFormats() uint64 {
+	return atomic.LoadUint64(&unknownFormats)
+}
+
+// unknownFormat structures a message of a format without rule, and of
+// the template given.
+func unknownFormat(format string, args []interface{}, template string) (Fields, string, Level) {
+	fields, message, level := defaultParsef(format, args...)
+	fields[MsgTemplateKey] = template
+	return fields, message, unknown(format, level)
+}
+
+// unknown returns the level of a message of a format without rule,
+// ErrorLevel in strict mode.
+func unknown(format string, level Level) Level {
+	s := strict.Load()
+	if s == nil {
+		return level
+	}
+	atomic.AddUint64(&unknownFormats, 1)
+	if s.report != nil {
+		s.report(format)
+	}
+	return ErrorLevel
+}
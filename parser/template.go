@@ -0,0 +1,34 @@
+package parser
+
+import "strings"
+
+// MsgTemplateKey is the field holding the template of a message, for log
+// aggregators to cluster messages on: the format of the rule it matched,
+// verbs preserved, or the format of a Printf call without rule, or else a
+// template normalized from the message, its variable words replaced by
+// %v.
+const MsgTemplateKey = "msg_template"
+
+// normalizeTemplate replaces the words of a message which look variable,
+// holding digits or quoted, by %v, keeping their trailing punctuation.
+func normalizeTemplate(message string) string {
+	words := strings.Split(message, " ")
+	for i, w := range words {
+		core := strings.TrimRight(w, ",;:.)]}")
+		if variable(core) {
+			words[i] = "%v" + w[len(core):]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func variable(word string) bool {
+	if word == "" {
+		return false
+	}
+	switch word[0] {
+	case '"', '\'', '`':
+		return true
+	}
+	return strings.ContainsAny(word, "0123456789")
+}
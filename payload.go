@@ -0,0 +1,135 @@
+package grpclogrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Fields of the payloads logged with WithPayloads.
+const (
+	RequestKey  = "request"
+	ResponseKey = "response"
+)
+
+// Redacted replaces the values of the fields redacted from payloads.
+const Redacted = "[REDACTED]"
+
+// WithPayloads logs the request and response messages of the calls of
+// the methods for which allow reports true, such as "/pkg.Service/Method":
+// on their finish entries for unary calls, and for the streams of the
+// StreamServerInterceptor, each on an entry of its own, at debug level,
+// as they're sent and received. Messages are rendered as JSON, with the
+// fields given to WithRedactedFields redacted, and are truncated to
+// maxBytes. Keep this to the methods whose messages are safe to log.
+func WithPayloads(maxBytes int, allow func(fullMethod string) bool) Option {
+	return func(o *options) {
+		o.payloadMax = maxBytes
+		o.payloadAllow = allow
+	}
+}
+
+// WithRedactedFields redacts the fields of the given names, at any depth,
+// from the payloads logged with WithPayloads. Names are those of the JSON
+// rendering: the lowerCamelCase names of proto fields.
+func WithRedactedFields(names ...string) Option {
+	return func(o *options) {
+		if o.redacted == nil {
+			o.redacted = make(map[string]bool)
+		}
+		for _, name := range names {
+			o.redacted[name] = true
+		}
+	}
+}
+
+// payloads adds the payloads of a call to its entry, when allowed.
+func (o *options) payloads(entry *logrus.Entry, fullMethod string, req, resp interface{}) *logrus.Entry {
+	if o.payloadAllow == nil || !o.payloadAllow(fullMethod) {
+		return entry
+	}
+	fields := logrus.Fields{RequestKey: o.payload(req)}
+	if resp != nil {
+		fields[ResponseKey] = o.payload(resp)
+	}
+	return entry.WithFields(o.fields(fields))
+}
+
+// streamPayloads logs the messages of a stream each on an entry of its
+// own, when allowed, the requests received and the responses sent, or is
+// nil.
+func (o *options) streamPayloads(entry *logrus.Entry, fullMethod string) func(key string, m interface{}) {
+	if o.payloadAllow == nil || !o.payloadAllow(fullMethod) {
+		return nil
+	}
+	return func(key string, m interface{}) {
+		eventID, message := "grpc.stream_message_received", "stream message received"
+		if key == ResponseKey {
+			eventID, message = "grpc.stream_message_sent", "stream message sent"
+		}
+		entry.WithFields(o.fields(logrus.Fields{key: o.payload(m)})).
+			WithField(parser.EventIDKey, eventID).Debug(message)
+	}
+}
+
+// payload renders a message as redacted and truncated JSON. Messages
+// that fail to redact are left out, for their fields not to leak.
+func (o *options) payload(m interface{}) string {
+	var (
+		b   []byte
+		err error
+	)
+	if pm, ok := m.(proto.Message); ok {
+		b, err = protojson.Marshal(pm)
+	} else {
+		b, err = json.Marshal(m)
+	}
+	if err != nil {
+		return "<" + err.Error() + ">"
+	}
+	if len(o.redacted) > 0 {
+		if b, err = o.redactJSON(b); err != nil {
+			return "<redaction failed: " + err.Error() + ">"
+		}
+	}
+	s := string(b)
+	if o.payloadMax > 0 && len(s) > o.payloadMax {
+		s = strings.ToValidUTF8(s[:o.payloadMax], "") + "…"
+	}
+	return s
+}
+
+// redactJSON redacts the fields of a JSON rendering, keeping its numbers
+// as they are.
+func (o *options) redactJSON(b []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(o.redact(v))
+}
+
+func (o *options) redact(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if o.redacted[k] {
+				v[k] = Redacted
+				continue
+			}
+			v[k] = o.redact(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = o.redact(e)
+		}
+	}
+	return v
+}
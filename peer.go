@@ -0,0 +1,68 @@
+package grpclogrus
+
+import (
+	"crypto/tls"
+	"net"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// Fields of the peer details logged WithPeerDetails. The TLS fields are
+// only present on TLS connections, and the peer certificate's when the
+// peer presented one.
+const (
+	PeerIPKey     = "peer_ip"
+	PeerPortKey   = "peer_port"
+	TLSVersionKey = "tls_version"
+	TLSCipherKey  = "tls_cipher"
+	PeerCNKey     = "peer_cn"
+	PeerSANKey    = "peer_san"
+)
+
+// WithPeerDetails adds the IP and port of the peer of a call to its
+// entries, along with the TLS version and cipher suite negotiated, and
+// the common name and subject alternative names of the peer's
+// certificate: who called which method, over what.
+func WithPeerDetails() Option {
+	return func(o *options) { o.peerDetails = true }
+}
+
+// peerFields are the details of a peer.
+func peerFields(p *peer.Peer) logrus.Fields {
+	fields := logrus.Fields{}
+	if p.Addr != nil {
+		if host, port, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			fields[PeerIPKey] = host
+			fields[PeerPortKey] = port
+		}
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return fields
+	}
+	state := info.State
+	fields[TLSVersionKey] = tls.VersionName(state.Version)
+	fields[TLSCipherKey] = tls.CipherSuiteName(state.CipherSuite)
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		if cert.Subject.CommonName != "" {
+			fields[PeerCNKey] = cert.Subject.CommonName
+		}
+		var san []string
+		san = append(san, cert.DNSNames...)
+		for _, ip := range cert.IPAddresses {
+			san = append(san, ip.String())
+		}
+		for _, u := range cert.URIs {
+			san = append(san, u.String())
+		}
+		san = append(san, cert.EmailAddresses...)
+		if len(san) > 0 {
+			fields[PeerSANKey] = strings.Join(san, ",")
+		}
+	}
+	return fields
+}
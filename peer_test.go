@@ -0,0 +1,69 @@
+package grpclogrus_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+func TestPeerDetails(t *testing.T) {
+	spiffe, _ := url.Parse("spiffe://example.org/billing")
+	p := &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 4242},
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			Version:     tls.VersionTLS13,
+			CipherSuite: tls.TLS_AES_128_GCM_SHA256,
+			PeerCertificates: []*x509.Certificate{{
+				Subject:     pkix.Name{CommonName: "billing"},
+				DNSNames:    []string{"billing.internal"},
+				IPAddresses: []net.IP{net.IPv4(10, 0, 0, 1)},
+				URIs:        []*url.URL{spiffe},
+			}},
+		}},
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpc.testing.TestService/UnaryCall"}
+	handler := func(context.Context, interface{}) (interface{}, error) { return nil, nil }
+
+	l, captured := capture()
+	interceptor := grpclogrus.UnaryServerInterceptor(l, grpclogrus.WithoutStart(), grpclogrus.WithPeerDetails())
+	interceptor(peer.NewContext(context.Background(), p), "req", info, handler)
+	want := logrus.Fields{
+		grpclogrus.PeerIPKey:     "2001:db8::1",
+		grpclogrus.PeerPortKey:   "4242",
+		grpclogrus.TLSVersionKey: "TLS 1.3",
+		grpclogrus.TLSCipherKey:  "TLS_AES_128_GCM_SHA256",
+		grpclogrus.PeerCNKey:     "billing",
+		grpclogrus.PeerSANKey:    "billing.internal,10.0.0.1,spiffe://example.org/billing",
+	}
+	if !captured.HasEntry(logrus.InfoLevel, "grpc.unary_call_finished", want) {
+		t.Errorf("want the details of the peer, got %v", captured.Entries())
+	}
+
+	l, captured = capture()
+	interceptor = grpclogrus.UnaryServerInterceptor(l, grpclogrus.WithoutStart(), grpclogrus.WithPeerDetails())
+	interceptor(incoming(), "req", info, handler)
+	fields := captured.Entries()[0].Fields
+	if fields[grpclogrus.PeerIPKey] != "10.0.0.1" {
+		t.Errorf("want the IP of the peer, got %v", fields)
+	}
+	if _, ok := fields[grpclogrus.TLSVersionKey]; ok {
+		t.Errorf("want no TLS details without TLS, got %v", fields)
+	}
+
+	l, captured = capture()
+	interceptor = grpclogrus.UnaryServerInterceptor(l, grpclogrus.WithoutStart())
+	interceptor(peer.NewContext(context.Background(), p), "req", info, handler)
+	if _, ok := captured.Entries()[0].Fields[grpclogrus.PeerIPKey]; ok {
+		t.Error("want no peer details without WithPeerDetails")
+	}
+}
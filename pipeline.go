@@ -0,0 +1,149 @@
+package grpclogrus
+
+import (
+	"math/rand"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Stage is a step of a pipeline, making a Backend that processes entries
+// before emitting them to next, or not. Backends like Truncated and
+// Renamed are stages.
+type Stage func(next Backend) Backend
+
+// BackendFunc is a Backend calling a function.
+type BackendFunc func(level logrus.Level, fields logrus.Fields, message string)
+
+// Emit calls f.
+func (f BackendFunc) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	f(level, fields, message)
+}
+
+// Phase orders the stages of a pipeline. Entries go through the phases in
+// order, whatever the order the stages were added in, and through the
+// stages of a phase in the order they were added.
+type Phase int
+
+// Phases of a pipeline, in order: entries are filtered and sampled first,
+// not to process those dropped; redacted before anything else sees them;
+// rewritten; and mapped last, the other stages knowing fields by their
+// native names.
+const (
+	FilterPhase Phase = iota
+	SamplePhase
+	RedactPhase
+	RewritePhase
+	MapPhase
+	phases
+)
+
+// PipelineBuilder composes stages into a Backend. Make one with Pipeline.
+type PipelineBuilder struct {
+	stages [phases][]Stage
+}
+
+// Pipeline starts a pipeline, e.g.:
+//
+//	b := grpclogrus.Pipeline().
+//		Filter(noHealthChecks).
+//		Redact("authorization").
+//		Sample(0.1).
+//		Emit(grpclogrus.Logrus(l))
+func Pipeline() *PipelineBuilder {
+	return &PipelineBuilder{}
+}
+
+// Use adds a custom stage to a phase.
+func (p *PipelineBuilder) Use(phase Phase, stage Stage) *PipelineBuilder {
+	p.stages[phase] = append(p.stages[phase], stage)
+	return p
+}
+
+// Filter drops the entries keep reports false for, in the FilterPhase.
+// Fatal entries are always kept.
+func (p *PipelineBuilder) Filter(keep func(level logrus.Level, fields logrus.Fields, message string) bool) *PipelineBuilder {
+	return p.Use(FilterPhase, func(next Backend) Backend {
+		return BackendFunc(func(level logrus.Level, fields logrus.Fields, message string) {
+			if level <= logrus.FatalLevel || keep(level, fields, message) {
+				next.Emit(level, fields, message)
+			}
+		})
+	})
+}
+
+// Sample keeps a fraction rate of the entries below error level, picked
+// at random, in the SamplePhase. Errors are always kept.
+func (p *PipelineBuilder) Sample(rate float64) *PipelineBuilder {
+	return p.Use(SamplePhase, func(next Backend) Backend {
+		return BackendFunc(func(level logrus.Level, fields logrus.Fields, message string) {
+			if level <= logrus.ErrorLevel || rand.Float64() < rate {
+				next.Emit(level, fields, message)
+			}
+		})
+	})
+}
+
+// Redact replaces the values of the fields of the keys given with
+// Redacted, in the RedactPhase.
+func (p *PipelineBuilder) Redact(keys ...string) *PipelineBuilder {
+	return p.Use(RedactPhase, func(next Backend) Backend {
+		return BackendFunc(func(level logrus.Level, fields logrus.Fields, message string) {
+			next.Emit(level, redactFields(fields, keys), message)
+		})
+	})
+}
+
+// redactFields returns fields with the values of keys redacted, copied
+// when there are some.
+func redactFields(fields logrus.Fields, keys []string) logrus.Fields {
+	var redacted logrus.Fields
+	for _, k := range keys {
+		if _, ok := fields[k]; !ok {
+			continue
+		}
+		if redacted == nil {
+			redacted = make(logrus.Fields, len(fields))
+			for k, v := range fields {
+				redacted[k] = v
+			}
+		}
+		redacted[k] = Redacted
+	}
+	if redacted == nil {
+		return fields
+	}
+	return redacted
+}
+
+// Rewrite passes entries through fn, in the RewritePhase. Fn must not
+// modify the fields it's given, but return a copy.
+func (p *PipelineBuilder) Rewrite(fn func(level logrus.Level, fields logrus.Fields, message string) (logrus.Level, logrus.Fields, string)) *PipelineBuilder {
+	return p.Use(RewritePhase, func(next Backend) Backend {
+		return BackendFunc(func(level logrus.Level, fields logrus.Fields, message string) {
+			next.Emit(fn(level, fields, message))
+		})
+	})
+}
+
+// Truncate truncates long values as Truncated does, in the RewritePhase.
+func (p *PipelineBuilder) Truncate(n int) *PipelineBuilder {
+	return p.Use(RewritePhase, func(next Backend) Backend { return Truncated(next, n) })
+}
+
+// Map renames fields by m, as Renamed does, in the MapPhase.
+func (p *PipelineBuilder) Map(m FieldMapping) *PipelineBuilder {
+	return p.Use(MapPhase, func(next Backend) Backend { return Renamed(next, m) })
+}
+
+// Emit makes the Backend of the pipeline, emitting the entries that go
+// through its stages to next.
+func (p *PipelineBuilder) Emit(next Backend) Backend {
+	b := next
+	for phase := phases - 1; phase >= 0; phase-- {
+		stages := p.stages[phase]
+		for i := len(stages) - 1; i >= 0; i-- {
+			b = stages[i](b)
+		}
+	}
+	return b
+}
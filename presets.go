@@ -0,0 +1,45 @@
+package grpclogrus
+
+import (
+	"os"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// TimestampFormat is the format of the timestamps of the loggers of
+// NewJSON and NewText: RFC 3339, to the millisecond.
+const TimestampFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// NewJSON makes a logrus.Logger writing entries of level and above to
+// stderr as JSON, and injects it in grpclog, for structured grpc logs
+// without a logrus setup. The logger is returned for the interceptors.
+func NewJSON(level logrus.Level) *logrus.Logger {
+	return inject(jsonFormatter(), level)
+}
+
+// NewText is NewJSON writing text, its fields in FieldOrder.
+func NewText(level logrus.Level) *logrus.Logger {
+	return inject(textFormatter(false), level)
+}
+
+func jsonFormatter() logrus.Formatter {
+	return &logrus.JSONFormatter{TimestampFormat: TimestampFormat}
+}
+
+func textFormatter(colors bool) logrus.Formatter {
+	f := NewTextFormatter()
+	f.FullTimestamp = true
+	f.TimestampFormat = TimestampFormat
+	f.ForceColors = colors
+	f.DisableColors = !colors
+	return f
+}
+
+func inject(f logrus.Formatter, level logrus.Level) *logrus.Logger {
+	l := logrus.New()
+	l.Out = os.Stderr
+	l.Formatter = f
+	l.Level = level
+	Inject(l.WithField("source", "grpc"))
+	return l
+}
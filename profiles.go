@@ -0,0 +1,54 @@
+package grpclogrus
+
+import "github.com/aybabtme/grpclogrus/parser"
+
+// DefaultRedactedFields are the names of the fields of payloads redacted
+// in Production: credentials, by their usual JSON names.
+var DefaultRedactedFields = []string{
+	"password",
+	"secret",
+	"token",
+	"accessToken",
+	"refreshToken",
+	"apiKey",
+	"authorization",
+}
+
+// ProductionSampling is the rate of the calls sampled in Production.
+const ProductionSampling = 0.1
+
+// Production is a Config for production: JSON at info, the transport's
+// chatter at warning, security messages at least at warning, the
+// internal ones at debug, a sample of the calls, and credentials redacted
+// from payloads. Adjust it before use, e.g.:
+//
+//	cfg := grpclogrus.Production()
+//	l, _ := cfg.Logger()
+//	g, _, _ := grpclogrus.NewFromConfig(l, cfg)
+//	grpclogrus.SetLogger(g)
+//	opts, _ := cfg.Options()
+func Production() Config {
+	sampling := ProductionSampling
+	return Config{
+		Format:          "json",
+		Level:           "info",
+		ComponentLevels: map[string]string{"transport": "warning"},
+		CategoryLevels: map[string]CategoryLevelConfig{
+			string(parser.CategorySecurity): {Min: "warning"},
+			string(parser.CategoryInternal): {Override: "debug"},
+		},
+		Sampling:       &sampling,
+		RedactedFields: append([]string(nil), DefaultRedactedFields...),
+	}
+}
+
+// Development is a Config for development: colored text, everything at
+// debug, with grpc's verbose info logs, and no sampling.
+func Development() Config {
+	return Config{
+		Format:    "text",
+		Colors:    true,
+		Level:     "debug",
+		Verbosity: 2,
+	}
+}
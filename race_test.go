@@ -0,0 +1,7 @@
+//go:build race
+
+package grpclogrus_test
+
+// raceEnabled skips the tests counting allocations: the race detector
+// drops items put in sync.Pools at random.
+const raceEnabled = true
@@ -0,0 +1,67 @@
+package grpclogrus
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PanicKey is the field holding the value a handler panicked with. The
+// stack is in parser.StackKey, as for entries parsed from grpclog.
+const PanicKey = "panic"
+
+// UnaryServerRecoveryInterceptor recovers the panics of unary handlers,
+// logging an error entry of l with the method, panic value, stack and
+// request id of the call, and failing the call with codes.Internal. The
+// panic value isn't sent to the client. Chain it after
+// UnaryServerInterceptor for the request id to be known.
+func UnaryServerRecoveryInterceptor(l *logrus.Entry, opts ...Option) grpc.UnaryServerInterceptor {
+	if l == nil {
+		l = logrus.WithFields(logrus.Fields{"source": "grpc"})
+	}
+	o := newOptions(opts)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				err = recovered(ctx, l, o, info.FullMethod, v)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerRecoveryInterceptor recovers the panics of stream
+// handlers, as UnaryServerRecoveryInterceptor does.
+func StreamServerRecoveryInterceptor(l *logrus.Entry, opts ...Option) grpc.StreamServerInterceptor {
+	if l == nil {
+		l = logrus.WithFields(logrus.Fields{"source": "grpc"})
+	}
+	o := newOptions(opts)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				err = recovered(ss.Context(), l, o, info.FullMethod, v)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// recovered logs the panic of a handler, returning the error of the call.
+func recovered(ctx context.Context, l *logrus.Entry, o options, fullMethod string, v interface{}) error {
+	fields := callFields(ctx, fullMethod)
+	fields[PanicKey] = v
+	fields[parser.StackKey] = string(debug.Stack())
+	if id, ok := RequestIDFromContext(ctx); ok {
+		fields[RequestIDKey] = id
+	}
+	fields = o.fields(fields)
+	fields[parser.EventIDKey] = "grpc.handler_panicked"
+	o.withContext(ctx, l.WithFields(fields)).Error("handler panicked")
+	return status.Error(codes.Internal, "internal error")
+}
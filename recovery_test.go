@@ -0,0 +1,66 @@
+package grpclogrus_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/logtest"
+	"github.com/aybabtme/grpclogrus/parser"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerRecoveryInterceptor(t *testing.T) {
+	l, captured := capture()
+	logging := grpclogrus.UnaryServerInterceptor(l, grpclogrus.WithoutStart(), grpclogrus.WithRequestID(""))
+	recovery := grpclogrus.UnaryServerRecoveryInterceptor(l)
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpc.testing.TestService/UnaryCall"}
+	handler := func(context.Context, interface{}) (interface{}, error) { panic("nil map") }
+
+	_, err := logging(incoming("x-request-id", "abc"), "req", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return recovery(ctx, req, info, handler)
+	})
+	if status.Code(err) != codes.Internal || strings.Contains(err.Error(), "nil map") {
+		t.Errorf("want the call failed as internal, without the panic value, got %v", err)
+	}
+	panicked, ok := captured.Find(func(e logtest.Entry) bool { return e.EventID() == "grpc.handler_panicked" })
+	if !ok {
+		t.Fatalf("want the panic logged, got %v", captured.Entries())
+	}
+	if panicked.Level != logrus.ErrorLevel || !logtest.Contains(panicked.Fields, logrus.Fields{
+		grpclogrus.MethodKey:    "UnaryCall",
+		grpclogrus.PanicKey:     "nil map",
+		grpclogrus.RequestIDKey: "abc",
+	}) {
+		t.Errorf("unexpected entry %v", panicked)
+	}
+	if stack, _ := panicked.Fields[parser.StackKey].(string); !strings.Contains(stack, "TestUnaryServerRecoveryInterceptor") {
+		t.Errorf("want the stack of the panic, got %q", stack)
+	}
+	if !captured.HasEntry(logrus.ErrorLevel, "grpc.unary_call_finished", logrus.Fields{grpclogrus.CodeKey: "Internal"}) {
+		t.Errorf("want the call finished as internal, got %v", captured.Entries())
+	}
+}
+
+func TestStreamServerRecoveryInterceptor(t *testing.T) {
+	l, captured := capture()
+	recovery := grpclogrus.StreamServerRecoveryInterceptor(l, grpclogrus.WithFieldPrefix("grpc."))
+	info := &grpc.StreamServerInfo{FullMethod: "/grpc.testing.TestService/FullDuplexCall"}
+	err := recovery(nil, &countedStream{ctx: incoming()}, info, func(interface{}, grpc.ServerStream) error { panic("closed channel") })
+	if status.Code(err) != codes.Internal {
+		t.Errorf("want the stream failed as internal, got %v", err)
+	}
+	if !captured.HasEntry(logrus.ErrorLevel, "grpc.handler_panicked", logrus.Fields{
+		"grpc." + grpclogrus.PanicKey: "closed channel",
+		"grpc." + grpclogrus.PeerKey:  "10.0.0.1:4242",
+	}) {
+		t.Errorf("want the panic logged, got %v", captured.Entries())
+	}
+	if err := recovery(nil, &countedStream{ctx: incoming()}, info, func(interface{}, grpc.ServerStream) error { return nil }); err != nil {
+		t.Errorf("want the error of the handler without panic, got %v", err)
+	}
+}
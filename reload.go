@@ -0,0 +1,111 @@
+package grpclogrus
+
+import (
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+// Fields of the entries of EnableSignalReload.
+const (
+	ChangedKey       = "changed"
+	RestartNeededKey = "restart_needed"
+	ConfigPathKey    = "config_path"
+)
+
+// reloadable are the keys of the settings of Config that a reload
+// applies. The others are set once, by NewFromConfig and Options.
+var reloadable = map[string]bool{
+	"level":            true,
+	"verbosity":        true,
+	"component_levels": true,
+	"category_levels":  true,
+	"strict":           true,
+	"rule_packs":       true,
+	"rule_files":       true,
+}
+
+// EnableSignalReload reloads the Config of path on SIGHUP, applying its
+// changes to levels, made by NewFromConfig from cfg, and to package
+// parser, at once and only if the config is valid. The rule files are
+// read anew, and the rule packs loaded, on every reload. Each reload is logged
+// to l, with the keys of the settings changed and those that need a
+// restart to apply, such as the options of the interceptors. Call stop
+// to stop reloading.
+func EnableSignalReload(path string, cfg Config, levels *Levels, l *logrus.Entry) (stop func()) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-sig:
+				cfg = reload(path, cfg, levels, l)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(sig)
+		close(done)
+	}
+}
+
+// reload applies the config of path, returning the config now applied.
+func reload(path string, prev Config, levels *Levels, l *logrus.Entry) Config {
+	l = l.WithField(ConfigPathKey, path)
+	cfg, err := LoadConfig(path)
+	if err == nil {
+		err = cfg.apply(levels, prev)
+	}
+	if err != nil {
+		l.WithFields(logrus.Fields{
+			parser.EventIDKey: "grpclogrus.config_reload_failed",
+			ErrKey:            err,
+		}).Error("config reload failed")
+		return prev
+	}
+	var changed, restart []string
+	keys := diffConfig(prev, cfg)
+	if len(cfg.RuleFiles) > 0 && !slices.Contains(keys, "rule_files") {
+		// Read anew, whether their paths changed or not.
+		keys = append(keys, "rule_files")
+	}
+	for _, key := range keys {
+		if reloadable[key] {
+			changed = append(changed, key)
+		} else {
+			restart = append(restart, key)
+		}
+	}
+	fields := logrus.Fields{
+		parser.EventIDKey: "grpclogrus.config_reloaded",
+		ChangedKey:        strings.Join(changed, ","),
+	}
+	if len(restart) > 0 {
+		fields[RestartNeededKey] = strings.Join(restart, ",")
+	}
+	l.WithFields(fields).Info("config reloaded")
+	return cfg
+}
+
+// diffConfig names the settings of two configs that differ, by their
+// keys, in order.
+func diffConfig(a, b Config) []string {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	t := va.Type()
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			keys = append(keys, strings.Split(t.Field(i).Tag.Get("json"), ",")[0])
+		}
+	}
+	return keys
+}
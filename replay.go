@@ -0,0 +1,39 @@
+package grpclogrus
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+// Replay structures the historical grpclog output read from r into
+// entries of l, dated by the timestamps of the original lines rather than
+// by the time of the replay, so that backfilled entries land where they
+// belong in time-series log stores. Lines without a timestamp are dated
+// by the last one seen, or by the time of the replay before any. Fatal
+// lines are logged without exiting.
+func Replay(ctx context.Context, r io.Reader, l *logrus.Entry) error {
+	if l == nil {
+		l = logrus.WithFields(logrus.Fields{"source": "grpc"})
+	}
+	var (
+		p    parser.Parser
+		last time.Time
+	)
+	return p.Run(ctx, r, func(e parser.Entry) error {
+		fields := logrus.Fields(e.Fields)
+		if t, ok := fields[parser.TimestampKey].(time.Time); ok {
+			last = t
+			delete(fields, parser.TimestampKey)
+		}
+		entry := l.WithFields(fields)
+		if !last.IsZero() {
+			entry = entry.WithTime(last)
+		}
+		entry.Log(logrusLevel(e.Level), e.Message)
+		return nil
+	})
+}
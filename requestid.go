@@ -0,0 +1,79 @@
+package grpclogrus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/Sirupsen/logrus"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIDKey is the field holding the request id of a call.
+const RequestIDKey = "request_id"
+
+// DefaultRequestIDHeader is the metadata key usually carrying request ids.
+const DefaultRequestIDHeader = "x-request-id"
+
+type requestIDContextKey struct{}
+
+// WithRequestID correlates the entries of a call by a request id, read
+// from the metadata key given, DefaultRequestIDHeader when empty. The
+// server interceptors generate one when the client sent none, and store
+// it in the context of the handler, for RequestIDFromContext; the client
+// interceptor sends the id of its context along, when there's one.
+func WithRequestID(header string) Option {
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+	return func(o *options) { o.requestIDHeader = header }
+}
+
+// RequestIDFromContext returns the request id of a call, set by the
+// server interceptors WithRequestID, or by ContextWithRequestID.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDContextKey{}).(string)
+	return id, ok
+}
+
+// ContextWithRequestID sets the request id sent by the client
+// interceptor WithRequestID.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, id)
+}
+
+// incomingRequestID reads or generates the request id of an incoming
+// call, returning the context and entry of the call with it.
+func (o *options) incomingRequestID(ctx context.Context, entry *logrus.Entry) (context.Context, *logrus.Entry) {
+	if o.requestIDHeader == "" {
+		return ctx, entry
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	var id string
+	if v := md.Get(o.requestIDHeader); len(v) > 0 && v[0] != "" {
+		id = v[0]
+	} else {
+		id = newRequestID()
+	}
+	return ContextWithRequestID(ctx, id), entry.WithFields(o.fields(logrus.Fields{RequestIDKey: id}))
+}
+
+// outgoingRequestID sends the request id of an outgoing call, if any,
+// returning the context and entry of the call with it.
+func (o *options) outgoingRequestID(ctx context.Context, entry *logrus.Entry) (context.Context, *logrus.Entry) {
+	if o.requestIDHeader == "" {
+		return ctx, entry
+	}
+	id, ok := RequestIDFromContext(ctx)
+	if !ok {
+		return ctx, entry
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, o.requestIDHeader, id)
+	return ctx, entry.WithFields(o.fields(logrus.Fields{RequestIDKey: id}))
+}
+
+func newRequestID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
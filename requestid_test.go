@@ -0,0 +1,69 @@
+package grpclogrus_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestRequestIDIncoming(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpc.testing.TestService/UnaryCall"}
+	for name, ctx := range map[string]context.Context{
+		"sent":      incoming("x-request-id", "abc"),
+		"generated": incoming(),
+	} {
+		l, captured := capture()
+		interceptor := grpclogrus.UnaryServerInterceptor(l, grpclogrus.WithRequestID(""))
+		var id string
+		interceptor(ctx, "req", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			id, _ = grpclogrus.RequestIDFromContext(ctx)
+			return nil, nil
+		})
+		if name == "sent" && id != "abc" || name == "generated" && len(id) != 32 {
+			t.Errorf("%s: unexpected request id %q", name, id)
+		}
+		for _, e := range captured.Entries() {
+			if e.Fields[grpclogrus.RequestIDKey] != id {
+				t.Errorf("%s: want every entry of the call with the request id %q, got %v", name, id, e.Fields)
+			}
+		}
+	}
+}
+
+func TestRequestIDOutgoing(t *testing.T) {
+	cc, err := grpc.NewClient("passthrough:///backend:443", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cc.Close()
+	var sent []string
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		sent = md.Get("x-correlation-id")
+		return nil
+	}
+
+	l, captured := capture()
+	interceptor := grpclogrus.UnaryClientInterceptor(l, grpclogrus.WithRequestID("x-correlation-id"))
+	ctx := grpclogrus.ContextWithRequestID(context.Background(), "abc")
+	if err := interceptor(ctx, "/grpc.testing.TestService/UnaryCall", "req", nil, cc, invoker); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 || sent[0] != "abc" {
+		t.Errorf("want the request id sent along, got %v", sent)
+	}
+	if !captured.HasEntry(logrus.InfoLevel, "grpc.unary_client_call_finished", logrus.Fields{grpclogrus.RequestIDKey: "abc"}) {
+		t.Errorf("want the entries with the request id, got %v", captured.Entries())
+	}
+
+	sent = nil
+	interceptor(context.Background(), "/grpc.testing.TestService/UnaryCall", "req", nil, cc, invoker)
+	if sent != nil {
+		t.Errorf("want no request id sent without one in the context, got %v", sent)
+	}
+}
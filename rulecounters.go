@@ -0,0 +1,33 @@
+package grpclogrus
+
+import "sort"
+
+// DefaultRuleCounters maps the ids of the rules whose entries are
+// signals of grpc failures to the counters they count toward, which the
+// expvar counters, grpcprom and grpcotel expose as metrics: the signals
+// then exist even where the logs are sampled. Set before use.
+var DefaultRuleCounters = map[string]string{
+	"grpc.clientconn_resettransport_failed":                                         "reconnects",
+	"grpc.server_serve_failed_to_complete_security_handshake":                       "handshake_failures",
+	"transport.http2server_handlestreams_received_bogus_greeting_from_client":       "bogus_prefaces",
+	"transport.http2server_handlestreams_saw_invalid_preface_type_from_client":      "bogus_prefaces",
+	"transport.http2server_handlestreams_failed_to_receive_the_preface_from_client": "bogus_prefaces",
+	"transport.http2client_notifyerror_client_transport_broken":                     "transport_failures",
+	"grpc.clientconn_transportmonitor_exits":                                        "transport_failures",
+	"grpc.server_serve_failed_to_create_servertransport":                            "transport_failures",
+}
+
+// RuleCounterNames are the names of the counters of DefaultRuleCounters,
+// without duplicates.
+func RuleCounterNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, name := range DefaultRuleCounters {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,178 @@
+package grpclogrus
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aybabtme/grpclogrus/parser"
+	"github.com/aybabtme/grpclogrus/parser/etcdpack"
+)
+
+// RuleFile declares a rule pack, for deployments to structure the
+// messages of the grpc-go version they vendor without writing Go, e.g. in
+// YAML:
+//
+//	name: myproject@grpc-go-v1.60
+//	parsef:
+//	  "grpc: failed to dial %s: %v":
+//	    id: grpc.dial_failed
+//	    level: error
+//	    category: connection
+//	    message: failed to dial
+//	    args: [target, err]
+//
+// Load it with LoadRuleFile, or through the RuleFiles of Config.
+type RuleFile struct {
+	// Name is the source of the rules, the path of the file when empty.
+	Name string `json:"name" yaml:"name"`
+	// Parsef are the rules of Parsef, by format.
+	Parsef map[string]RuleConfig `json:"parsef" yaml:"parsef"`
+	// Parseln are the rules of Parseln, by their first arg.
+	Parseln map[string]RuleConfig `json:"parseln" yaml:"parseln"`
+}
+
+// RuleConfig is a rule of a RuleFile.
+type RuleConfig struct {
+	// ID is the event id of the messages.
+	ID string `json:"id" yaml:"id"`
+	// Level of the messages, info when empty.
+	Level string `json:"level" yaml:"level"`
+	// Category of the messages, e.g. "connection", internal when empty.
+	Category string `json:"category" yaml:"category"`
+	// Message of the entries, the format when empty.
+	Message string `json:"message" yaml:"message"`
+	// Args name the fields of the args of the messages, in order. Args
+	// named "" are left out.
+	Args []string `json:"args" yaml:"args"`
+}
+
+// LoadRuleFile reads the RuleFile of path, as LoadConfig reads configs,
+// returning the pack of its rules, for parser.LoadPack.
+func LoadRuleFile(path string) (parser.Pack, error) {
+	var f RuleFile
+	if err := decodeFile(path, &f); err != nil {
+		return parser.Pack{}, fmt.Errorf("grpclogrus: rule file %s: %v", path, err)
+	}
+	if f.Name == "" {
+		f.Name = path
+	}
+	p := parser.Pack{Name: f.Name}
+	var err error
+	if p.Parsef, err = compileRuleConfigs(f.Parsef); err != nil {
+		return p, fmt.Errorf("grpclogrus: rule file %s: %v", path, err)
+	}
+	if p.Parseln, err = compileRuleConfigs(f.Parseln); err != nil {
+		return p, fmt.Errorf("grpclogrus: rule file %s: %v", path, err)
+	}
+	return p, nil
+}
+
+func compileRuleConfigs(configs map[string]RuleConfig) (map[string]parser.Rule, error) {
+	rules := make(map[string]parser.Rule, len(configs))
+	for format, c := range configs {
+		if c.ID == "" {
+			return nil, fmt.Errorf("rule %q: no id", format)
+		}
+		level, err := parseLevel(c.Level)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %v", c.ID, err)
+		}
+		message := c.Message
+		if message == "" {
+			message = strings.TrimSpace(format)
+		}
+		names := c.Args
+		rules[format] = parser.Rule{
+			ID:       c.ID,
+			Level:    parser.Level(level),
+			Category: parser.Category(c.Category),
+			Parse: func(args ...interface{}) (parser.Fields, string) {
+				fields := make(parser.Fields, len(names))
+				for i, name := range names {
+					if name != "" && i < len(args) {
+						fields[name] = args[i]
+					}
+				}
+				return fields, message
+			},
+		}
+	}
+	return rules, nil
+}
+
+// rulePacks are the packs of RegisterRulePack, by name.
+var rulePacks = struct {
+	sync.Mutex
+	m map[string]parser.Pack
+}{m: map[string]parser.Pack{etcdpack.Name: etcdpack.Rules}}
+
+// RegisterRulePack makes a pack loadable by name through the RulePacks
+// of Config. The packs of this module, such as etcdpack's, are.
+func RegisterRulePack(p parser.Pack) {
+	rulePacks.Lock()
+	rulePacks.m[p.Name] = p
+	rulePacks.Unlock()
+}
+
+// RulePackNames are the names of the packs registered, sorted.
+func RulePackNames() []string {
+	rulePacks.Lock()
+	defer rulePacks.Unlock()
+	names := make([]string, 0, len(rulePacks.m))
+	for name := range rulePacks.m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func rulePack(name string) (parser.Pack, error) {
+	rulePacks.Lock()
+	defer rulePacks.Unlock()
+	p, ok := rulePacks.m[name]
+	if !ok {
+		return p, fmt.Errorf("grpclogrus: unknown rule pack %q", name)
+	}
+	return p, nil
+}
+
+// configPacks are the names of the packs loaded by the last config
+// applied, for the next one to unload those it drops.
+var configPacks struct {
+	sync.Mutex
+	names []string
+}
+
+// packs are the rule packs and files of cfg, the files read anew.
+func (cfg Config) packs() ([]parser.Pack, error) {
+	var packs []parser.Pack
+	for _, name := range cfg.RulePacks {
+		p, err := rulePack(name)
+		if err != nil {
+			return nil, err
+		}
+		packs = append(packs, p)
+	}
+	for _, path := range cfg.RuleFiles {
+		p, err := LoadRuleFile(path)
+		if err != nil {
+			return nil, err
+		}
+		packs = append(packs, p)
+	}
+	return packs, nil
+}
+
+// loadConfigPacks loads the packs of a config in place of those of the
+// config loaded before, at once.
+func loadConfigPacks(packs []parser.Pack) {
+	configPacks.Lock()
+	defer configPacks.Unlock()
+	parser.ReplacePacks(configPacks.names, packs...)
+	configPacks.names = configPacks.names[:0]
+	for _, p := range packs {
+		configPacks.names = append(configPacks.names, p.Name)
+	}
+}
@@ -0,0 +1,54 @@
+package grpclogrus
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// LevelFatal is the slog level of entries from grpclog's Fatal calls,
+// slog having none of its own.
+const LevelFatal = slog.LevelError + 4
+
+type slogBackend struct {
+	l *slog.Logger
+}
+
+// Slog makes a Backend from a slog.Logger. Fields are emitted as
+// attributes, sorted by key.
+func Slog(l *slog.Logger) Backend {
+	if l == nil {
+		l = slog.Default().With("source", "grpc")
+	}
+	return &slogBackend{l: l}
+}
+
+func (b *slogBackend) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, slog.Any(k, fields[k]))
+	}
+	b.l.LogAttrs(context.Background(), slogLevel(level), message, attrs...)
+}
+
+func slogLevel(level logrus.Level) slog.Level {
+	switch level {
+	case logrus.PanicLevel, logrus.FatalLevel:
+		return LevelFatal
+	case logrus.ErrorLevel:
+		return slog.LevelError
+	case logrus.WarnLevel:
+		return slog.LevelWarn
+	case logrus.InfoLevel:
+		return slog.LevelInfo
+	default:
+		return slog.LevelDebug
+	}
+}
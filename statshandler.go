@@ -0,0 +1,168 @@
+package grpclogrus
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// Fields of the entries logged by the StatsHandler. The length of a
+// payload is that of its message, its compressed length that of the
+// message as compressed, if it is, and its wire length that of the
+// message with its framing, as it went on the wire. The bytes sent and
+// received are the wire lengths of all the payloads of a call.
+const (
+	SideKey             = "side"
+	LengthKey           = "length"
+	CompressedLengthKey = "compressed_length"
+	WireLengthKey       = "wire_length"
+	BytesSentKey        = "bytes_sent"
+	BytesReceivedKey    = "bytes_received"
+)
+
+// WithSampling logs a fraction rate of the calls, between 0 and 1, rather
+// than all of them: in the interceptors and the StatsHandler, each picking
+// its calls at random when they begin. The calls left out are counted
+// under SampledOutVar.
+func WithSampling(rate float64) Option {
+	return func(o *options) { o.sampling = rate }
+}
+
+// StatsHandler logs the events grpc reports of the calls and connections
+// of a client or server as entries of l, at debug level: the begin and end
+// of connections and calls, and the payloads sent and received, with their
+// lengths as sent on the wire, which the interceptors don't see. The end
+// entry of a call is logged at the level of its code instead, with the
+// bytes it sent and received. Install it with grpc.StatsHandler on a
+// server, or grpc.WithStatsHandler on a client connection.
+//
+// The calls of methods not logged by the options aren't either, and only a
+// sample of them are WithSampling.
+func StatsHandler(l *logrus.Entry, opts ...Option) stats.Handler {
+	if l == nil {
+		l = logrus.WithFields(logrus.Fields{"source": "grpc"})
+	}
+	return &statsHandler{l: l, o: newOptions(opts)}
+}
+
+type statsHandler struct {
+	l *logrus.Entry
+	o options
+}
+
+type connStatsContextKey struct{}
+type rpcStatsContextKey struct{}
+
+// rpcStats is what the StatsHandler knows of a call. Payloads are
+// received and sent concurrently.
+type rpcStats struct {
+	method         string
+	level          func(codes.Code) logrus.Level
+	sent, received int64
+	sentBytes      int64
+	receivedBytes  int64
+}
+
+func (h *statsHandler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	return context.WithValue(ctx, connStatsContextKey{}, info)
+}
+
+func (h *statsHandler) HandleConn(ctx context.Context, s stats.ConnStats) {
+	var eventID, message string
+	switch s.(type) {
+	case *stats.ConnBegin:
+		eventID, message = "grpc.conn_began", "connection began"
+	case *stats.ConnEnd:
+		eventID, message = "grpc.conn_ended", "connection ended"
+	default:
+		return
+	}
+	fields := h.o.fields(logrus.Fields{SideKey: side(s.IsClient())})
+	fields[parser.EventIDKey] = eventID
+	h.withConn(ctx, h.l.WithFields(fields)).Debug(message)
+}
+
+func (h *statsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	log, level := h.o.method(info.FullMethodName)
+	if !log {
+		return ctx
+	}
+	if !h.o.sampled() {
+		return ctx
+	}
+	return context.WithValue(ctx, rpcStatsContextKey{}, &rpcStats{method: info.FullMethodName, level: level})
+}
+
+func (h *statsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	rs, ok := ctx.Value(rpcStatsContextKey{}).(*rpcStats)
+	if !ok {
+		return
+	}
+	fields := methodFields(rs.method)
+	fields[SideKey] = side(s.IsClient())
+	var eventID, message string
+	level := logrus.DebugLevel
+	switch s := s.(type) {
+	case *stats.Begin:
+		eventID, message = "grpc.rpc_began", "rpc began"
+	case *stats.InPayload:
+		atomic.AddInt64(&rs.received, 1)
+		atomic.AddInt64(&rs.receivedBytes, int64(s.WireLength))
+		eventID, message = "grpc.payload_received", "payload received"
+		payloadFields(fields, s.Length, s.CompressedLength, s.WireLength)
+	case *stats.OutPayload:
+		atomic.AddInt64(&rs.sent, 1)
+		atomic.AddInt64(&rs.sentBytes, int64(s.WireLength))
+		eventID, message = "grpc.payload_sent", "payload sent"
+		payloadFields(fields, s.Length, s.CompressedLength, s.WireLength)
+	case *stats.End:
+		eventID, message = "grpc.rpc_ended", "rpc ended"
+		code := status.Code(s.Error)
+		level = rs.level(code)
+		fields[CodeKey] = code.String()
+		fields[DurationKey] = s.EndTime.Sub(s.BeginTime).String()
+		fields[SentKey] = atomic.LoadInt64(&rs.sent)
+		fields[ReceivedKey] = atomic.LoadInt64(&rs.received)
+		fields[BytesSentKey] = atomic.LoadInt64(&rs.sentBytes)
+		fields[BytesReceivedKey] = atomic.LoadInt64(&rs.receivedBytes)
+		if s.Error != nil {
+			fields[ErrKey] = s.Error
+		}
+	default:
+		return
+	}
+	fields = h.o.fields(fields)
+	fields[parser.EventIDKey] = eventID
+	entry := h.withConn(ctx, h.l.WithFields(fields))
+	h.o.withContext(ctx, entry).Log(level, message)
+}
+
+// withConn adds the peer of the connection of a context to an entry, if
+// known.
+func (h *statsHandler) withConn(ctx context.Context, entry *logrus.Entry) *logrus.Entry {
+	info, ok := ctx.Value(connStatsContextKey{}).(*stats.ConnTagInfo)
+	if !ok || info.RemoteAddr == nil {
+		return entry
+	}
+	return entry.WithFields(h.o.fields(logrus.Fields{PeerKey: info.RemoteAddr.String()}))
+}
+
+func payloadFields(fields logrus.Fields, length, compressed, wire int) {
+	fields[LengthKey] = length
+	if compressed != length {
+		fields[CompressedLengthKey] = compressed
+	}
+	fields[WireLengthKey] = wire
+}
+
+func side(client bool) string {
+	if client {
+		return "client"
+	}
+	return "server"
+}
@@ -0,0 +1,108 @@
+package grpclogrus
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"golang.org/x/net/websocket"
+)
+
+// TailBuffer is the number of entries buffered for each client of a
+// Tail. Entries are dropped for clients falling further behind, rather
+// than slowing down grpc.
+const TailBuffer = 256
+
+// Tail is a Backend streaming entries live to WebSocket clients, one
+// JSON object per message, as logrus' JSONFormatter renders them. It's
+// an http.Handler, to be mounted on a debug server; entries emitted while
+// no client is connected are dropped.
+type Tail struct {
+	formatter logrus.JSONFormatter
+
+	mu      sync.Mutex
+	clients map[chan []byte]struct{}
+}
+
+var _ Backend = (*Tail)(nil)
+
+// NewTail makes a Tail without clients.
+func NewTail() *Tail {
+	return &Tail{clients: make(map[chan []byte]struct{})}
+}
+
+// ServeTail serves a Tail on addr in the background, at every path,
+// until the process exits. Point a browser or websocat at ws://addr/ to
+// watch the entries.
+func ServeTail(addr string) (*Tail, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	t := NewTail()
+	go http.Serve(l, t)
+	return t, nil
+}
+
+func (t *Tail) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.clients) == 0 {
+		return
+	}
+	b, err := t.formatter.Format(&logrus.Entry{
+		Data:    fields,
+		Time:    time.Now(),
+		Level:   level,
+		Message: message,
+	})
+	if err != nil {
+		return
+	}
+	for c := range t.clients {
+		select {
+		case c <- b:
+		default:
+			CountDropped(1)
+		}
+	}
+}
+
+func (t *Tail) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	websocket.Server{Handler: t.serve}.ServeHTTP(w, r)
+}
+
+func (t *Tail) serve(ws *websocket.Conn) {
+	defer ws.Close()
+	c := make(chan []byte, TailBuffer)
+	t.mu.Lock()
+	t.clients[c] = struct{}{}
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.clients, c)
+		t.mu.Unlock()
+	}()
+
+	// Clients aren't expected to send anything: reading detects them
+	// going away.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var discard []byte
+		for websocket.Message.Receive(ws, &discard) == nil {
+		}
+	}()
+	for {
+		select {
+		case b := <-c:
+			if err := websocket.Message.Send(ws, string(b)); err != nil {
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package grpclogrus
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// NewTesting routes grpc's logs to t.Logf, for them to land in the output
+// of the test logging them, as the level, message, and fields as
+// key=value in FieldOrder. The logger set before, by an Inject function,
+// SetLogger or another NewTesting, is restored when the test and its
+// subtests complete, and grpc's default one, logging errors to stderr,
+// when there was none.
+// Tests calling NewTesting can't run in parallel with others logging
+// through grpc.
+func NewTesting(t testing.TB) {
+	t.Helper()
+	b := &testingBackend{t: t}
+	previous := SetLogger(Tee(b))
+	t.Cleanup(func() {
+		b.mu.Lock()
+		b.done = true
+		b.mu.Unlock()
+		SetLogger(previous)
+	})
+}
+
+type testingBackend struct {
+	t    testing.TB
+	mu   sync.Mutex
+	done bool
+}
+
+func (b *testingBackend) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	SortFields(keys)
+	var line strings.Builder
+	fmt.Fprintf(&line, "grpc %s %q", level, message)
+	for _, k := range keys {
+		fmt.Fprintf(&line, " %s=%v", k, fields[k])
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	// grpc's goroutines may outlive the test, which t must not log to.
+	if !b.done {
+		b.t.Logf("%s", line.String())
+	}
+}
@@ -0,0 +1,91 @@
+package grpclogrus
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// LengthSuffix names the field holding the length, in bytes, of a value
+// truncated by WithMaxFieldLength or Truncated: a preface truncated is
+// followed by preface_length.
+const LengthSuffix = "_length"
+
+// WithMaxFieldLength truncates the values of the interceptors' fields
+// longer than n bytes, as Truncated does for the entries of a backend.
+func WithMaxFieldLength(n int) Option {
+	return func(o *options) { o.maxFieldLength = n }
+}
+
+// Truncated makes a Backend truncating the values longer than n bytes
+// before emitting entries to next, for huge or binary args, like the
+// bogus prefaces of clients, not to flood the logs. Values are cut on a
+// rune boundary, invalid UTF-8 replaced, and marked with an ellipsis;
+// their length is kept in a field named after theirs, with LengthSuffix.
+// Strings, byte slices, errors and Stringers are truncated, the others
+// being short.
+func Truncated(next Backend, n int) Backend {
+	return &truncated{next: next, n: n}
+}
+
+type truncated struct {
+	next Backend
+	n    int
+}
+
+func (t *truncated) Emit(level logrus.Level, fields logrus.Fields, message string) {
+	t.next.Emit(level, truncateFields(fields, t.n), message)
+}
+
+// truncateFields returns fields with its long values truncated, copied
+// when there are some.
+func truncateFields(fields logrus.Fields, n int) logrus.Fields {
+	var truncated logrus.Fields
+	for k, v := range fields {
+		s, ok := longValue(v, n)
+		if !ok {
+			continue
+		}
+		if truncated == nil {
+			truncated = make(logrus.Fields, len(fields)+1)
+			for k, v := range fields {
+				truncated[k] = v
+			}
+		}
+		truncated[k] = truncate(s, n)
+		truncated[k+LengthSuffix] = len(s)
+	}
+	if truncated == nil {
+		return fields
+	}
+	return truncated
+}
+
+// longValue renders v if it is a value that can be longer than n bytes,
+// and is.
+func longValue(v interface{}, n int) (string, bool) {
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case error:
+		s = v.Error()
+	case fmt.Stringer:
+		s = v.String()
+	default:
+		return "", false
+	}
+	return s, n > 0 && len(s) > n
+}
+
+// truncate cuts s to at most n bytes, on a rune boundary.
+func truncate(s string, n int) string {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return strings.ToValidUTF8(s[:n], "�") + "…"
+}
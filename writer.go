@@ -0,0 +1,110 @@
+package grpclogrus
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+// MaxLineLength bounds the size of the lines buffered by a ParseWriter.
+// Longer lines are cut to it, the rest of them dropped, and their entries
+// marked with parser.TruncatedKey.
+const MaxLineLength = 64 << 10
+
+// ParseLine structures a line of grpclog output with the rules of New,
+// without a logger: the prefix grpclog's loggers prepend is stripped, its
+// timestamp, severity and caller becoming fields. Lines matching no rule
+// are returned as the message, with matched false.
+func ParseLine(line string) (fields logrus.Fields, msg string, level logrus.Level, matched bool) {
+	f, msg, lvl, matched := parser.ParseLine(line)
+	return logrus.Fields(f), msg, logrusLevel(lvl), matched
+}
+
+type parseWriter struct {
+	l *logrus.Entry
+
+	mu     sync.Mutex
+	buf    []byte
+	folder parser.Folder
+	// cut is set while the rest of a line cut short is dropped.
+	cut bool
+}
+
+// NewParseWriter makes an io.WriteCloser structuring the grpclog output
+// written to it, line by line, into entries of l. It suits processes
+// that can't inject a logger but can redirect grpclog's output, e.g. with
+// log.SetOutput or as an exec.Cmd's Stderr. Fatal lines are logged
+// without exiting.
+//
+// Multi-line entries are folded, their continuation lines becoming a
+// stack field, however they're split across writes: an entry is logged
+// once the first line of the next one is written, or on Close. Lines
+// wrapped by container runtimes, Docker's JSON or the CRI format, are
+// unwrapped, which lets the writer consume container logs.
+func NewParseWriter(l *logrus.Entry) io.WriteCloser {
+	if l == nil {
+		l = logrus.WithFields(logrus.Fields{"source": "grpc"})
+	}
+	return &parseWriter{l: l, folder: parser.Folder{MaxLineLength: MaxLineLength}}
+}
+
+func (w *parseWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if w.cut {
+			if i < 0 {
+				w.buf = w.buf[:0]
+				break
+			}
+			w.buf, w.cut = w.buf[i+1:], false
+			continue
+		}
+		if i < 0 && len(w.buf) <= MaxLineLength {
+			break
+		}
+		if i < 0 || i > MaxLineLength {
+			w.emit(string(w.buf[:MaxLineLength]))
+			w.folder.Truncate()
+			w.buf, w.cut = w.buf[MaxLineLength:], true
+			continue
+		}
+		w.emit(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	if len(w.buf) == 0 {
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+// Close logs the entry still pending, of the last line written, even
+// without a newline.
+func (w *parseWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 && !w.cut {
+		w.emit(string(w.buf))
+	}
+	w.buf, w.cut = nil, false
+	if e, ok := w.folder.Flush(); ok {
+		w.log(e)
+	}
+	return nil
+}
+
+func (w *parseWriter) emit(line string) {
+	if e, ok := w.folder.Push(line); ok {
+		w.log(e)
+	}
+}
+
+func (w *parseWriter) log(e parser.Entry) {
+	countMatch(logrus.Fields(e.Fields))
+	w.l.WithFields(logrus.Fields(e.Fields)).Log(logrusLevel(e.Level), e.Message)
+}
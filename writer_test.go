@@ -0,0 +1,72 @@
+package grpclogrus_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/aybabtme/grpclogrus"
+	"github.com/aybabtme/grpclogrus/parser"
+)
+
+// writeEntries writes each of writes to a ParseWriter, closing it,
+// returning the entries it logged.
+func writeEntries(t *testing.T, writes ...string) []map[string]interface{} {
+	t.Helper()
+	var out bytes.Buffer
+	l := logrus.New()
+	l.Out = &out
+	l.Formatter = &logrus.JSONFormatter{}
+	w := grpclogrus.NewParseWriter(logrus.NewEntry(l))
+	for _, p := range writes {
+		if _, err := w.Write([]byte(p)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	var entries []map[string]interface{}
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var e map[string]interface{}
+		if err := dec.Decode(&e); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestParseWriterFoldsAcrossWrites(t *testing.T) {
+	entries := writeEntries(t,
+		"2020/01/01 00:00:00 panic: boom\n\tat fra",
+		"me one\n",
+		"\tat frame two\n2020/01/01 00:00:01 EmptyUnaryCall done",
+	)
+	if len(entries) != 2 {
+		t.Fatalf("want 2 entries, got %v", entries)
+	}
+	if stack := entries[0][parser.StackKey]; stack != "\tat frame one\n\tat frame two" {
+		t.Errorf("want the stack whole, got %q", stack)
+	}
+	if entries[1]["msg"] == nil {
+		t.Errorf("want the last line logged on Close, got %v", entries[1])
+	}
+}
+
+func TestParseWriterTruncatesLongLines(t *testing.T) {
+	long := "2020/01/01 00:00:00 " + strings.Repeat("x", grpclogrus.MaxLineLength)
+	entries := writeEntries(t, long[:100], long[100:]+"\n2020/01/01 00:00:01 EmptyUnaryCall done\n")
+	if len(entries) != 2 {
+		t.Fatalf("want 2 entries, got %d", len(entries))
+	}
+	if entries[0][parser.TruncatedKey] != true {
+		t.Errorf("want the long line truncated, got %v", entries[0][parser.TruncatedKey])
+	}
+	if _, ok := entries[1][parser.TruncatedKey]; ok {
+		t.Errorf("want the next line whole, got %v", entries[1])
+	}
+}